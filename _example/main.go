@@ -2,7 +2,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/kenshaw/goji"
@@ -16,5 +18,7 @@ func main() {
 	m.HandleFunc(goji.Get("/"), func(res http.ResponseWriter, req *http.Request) {
 		fmt.Fprint(res, "a page")
 	})
-	http.ListenAndServe(":3000", m)
+	if err := goji.Run(context.Background(), ":3000", m); err != nil {
+		log.Fatal(err)
+	}
 }
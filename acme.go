@@ -0,0 +1,86 @@
+package goji
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+)
+
+// CertManager is the interface for automatic certificate managers that
+// acquire and renew certificates using the ACME protocol (such as Let's
+// Encrypt), for example golang.org/x/crypto/acme/autocert.Manager:
+//
+//	m := &autocert.Manager{
+//		Prompt:     autocert.AcceptTOS,
+//		HostPolicy: autocert.HostWhitelist("example.com", "www.example.com"),
+//		Cache:      autocert.DirCache("/var/cache/certs"),
+//	}
+//	err := goji.Run(ctx, ":https", mux, goji.WithAutocert(m, ":http"))
+type CertManager interface {
+	// GetCertificate returns the certificate for the TLS client hello,
+	// acquiring or renewing the certificate as necessary.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	// HTTPHandler returns a handler that responds to ACME HTTP-01 challenge
+	// requests, passing all other requests to fallback. When fallback is
+	// nil, requests are redirected to HTTPS.
+	HTTPHandler(fallback http.Handler) http.Handler
+}
+
+// WithAutocert is a server option to serve TLS using certificates
+// automatically acquired and renewed by the certificate manager.
+//
+// When redirectAddr is not empty, an additional HTTP listener is served on
+// the address that answers the certificate manager's HTTP-01 challenges and
+// redirects all other requests to HTTPS. Challenge requests to the served
+// handler are additionally answered prior to routing, allowing challenges to
+// be answered regardless of the handler's routes when it is also served over
+// HTTP by other means.
+func WithAutocert(m CertManager, redirectAddr string) ServerOption {
+	return func(s *server) {
+		if s.server.server.TLSConfig == nil {
+			s.server.server.TLSConfig = new(tls.Config)
+		}
+		s.server.server.TLSConfig.GetCertificate = m.GetCertificate
+		s.server.server.TLSConfig.NextProtos = append(s.server.server.TLSConfig.NextProtos, "h2", "http/1.1", "acme-tls/1")
+		s.server.server.Handler = &acmeHandler{
+			Handler:   s.server.server.Handler,
+			challenge: m.HTTPHandler(s.server.server.Handler),
+		}
+		if redirectAddr != "" {
+			s.servers = append(s.servers, &httpServer{
+				server: &http.Server{
+					Addr:    redirectAddr,
+					Handler: m.HTTPHandler(nil),
+				},
+			})
+		}
+	}
+}
+
+// acmeChallengePath is the path prefix of ACME HTTP-01 challenge requests.
+const acmeChallengePath = "/.well-known/acme-challenge/"
+
+// acmeHandler is a http.Handler that answers ACME HTTP-01 challenge requests
+// prior to passing requests to the wrapped handler.
+type acmeHandler struct {
+	http.Handler
+	challenge http.Handler
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (h *acmeHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if strings.HasPrefix(req.URL.Path, acmeChallengePath) {
+		h.challenge.ServeHTTP(res, req)
+		return
+	}
+	h.Handler.ServeHTTP(res, req)
+}
+
+// Drain drains the wrapped handler, when it supports draining (see
+// Mux.Drain).
+func (h *acmeHandler) Drain() {
+	if d, ok := h.Handler.(interface{ Drain() }); ok {
+		d.Drain()
+	}
+}
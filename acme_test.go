@@ -0,0 +1,102 @@
+package goji
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithAutocert(t *testing.T) {
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	cm := &testCertManager{cert: &ts.TLS.Certificates[0]}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	rl, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := New()
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("secure"))
+	})
+	m.HandleFunc(Get("/*"), func(res http.ResponseWriter, req *http.Request) {
+		http.NotFound(res, req)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	started, done := make(chan bool), make(chan error)
+	go func() {
+		done <- Run(ctx, "", m,
+			WithListener(l),
+			WithAutocert(cm, rl.Addr().String()),
+			func(s *server) {
+				s.servers[1].listener = rl
+			},
+			OnStart(func(context.Context) error {
+				close(started)
+				return nil
+			}),
+		)
+	}()
+	<-started
+
+	cl := ts.Client()
+	cl.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	tests := []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"https://" + l.Addr().String() + "/", http.StatusOK, "secure"},
+		{"https://" + l.Addr().String() + "/.well-known/acme-challenge/token", http.StatusOK, "challenge"},
+		{"http://" + rl.Addr().String() + "/.well-known/acme-challenge/token", http.StatusOK, "challenge"},
+		{"http://" + rl.Addr().String() + "/", http.StatusFound, ""},
+	}
+	for i, test := range tests {
+		res, err := cl.Get(test.url)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != test.status || !strings.HasPrefix(string(body), test.body) {
+			t.Errorf("test %d expected %d %q, got: %d %q", i, test.status, test.body, res.StatusCode, body)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+type testCertManager struct {
+	cert *tls.Certificate
+}
+
+func (cm *testCertManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return cm.cert, nil
+}
+
+func (cm *testCertManager) HTTPHandler(fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/.well-known/acme-challenge/") {
+			res.Write([]byte("challenge"))
+			return
+		}
+		if fallback == nil {
+			http.Redirect(res, req, "https://"+req.Host+req.URL.RequestURI(), http.StatusFound)
+			return
+		}
+		fallback.ServeHTTP(res, req)
+	})
+}
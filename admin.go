@@ -0,0 +1,126 @@
+package goji
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+)
+
+// AdminMux returns a Mux for an internal admin plane, pre-wired with the
+// following observability endpoints for the Mux:
+//
+//	GET /healthz         liveness, 200 OK while the process is serving
+//	GET /readyz          readiness, 503 Service Unavailable while the Mux is draining
+//	GET /metrics         expvar variables (see PublishExpvar)
+//	GET /routes          JSON listing of the Mux's routes
+//	GET /limits          limiter introspection (see WithAdminLimits)
+//	GET /slo             SLO compliance report (see WithAdminSLOs)
+//	    /debug/pprof/*   net/http/pprof profiles
+//
+// The admin Mux is intended to be served on an internal listener (see
+// WithAddr), as its endpoints expose details of the process:
+//
+//	admin := goji.AdminMux(mux,
+//		goji.WithAdminHealth(checker.Liveness(), checker.Readiness()),
+//		goji.WithAdminLimits(middleware.DefaultLimits),
+//	)
+//	err := goji.Run(ctx, ":443", mux, goji.WithAddr("localhost:9090", admin))
+func AdminMux(m *Mux, opts ...AdminOption) *Mux {
+	a := &admin{
+		liveness: http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Set("Cache-Control", "no-store")
+			_, _ = res.Write([]byte("ok\n"))
+		}),
+		readiness: http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Set("Cache-Control", "no-store")
+			if m.Draining() {
+				http.Error(res, "draining", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = res.Write([]byte("ok\n"))
+		}),
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	mux := New()
+	mux.Handle(Get("/healthz"), a.liveness)
+	mux.Handle(Get("/readyz"), a.readiness)
+	mux.Handle(Get("/metrics"), expvar.Handler())
+	mux.HandleFunc(Get("/routes"), func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		res.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(res)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(adminRoutes(m))
+	})
+	if a.limits != nil {
+		mux.Handle(Get("/limits"), a.limits)
+	}
+	if a.slos != nil {
+		mux.Handle(Get("/slo"), a.slos)
+	}
+	mux.HandleFunc(Get("/debug/pprof/cmdline"), pprof.Cmdline)
+	mux.HandleFunc(NewPathSpec("/debug/pprof/profile"), pprof.Profile)
+	mux.HandleFunc(NewPathSpec("/debug/pprof/symbol"), pprof.Symbol)
+	mux.HandleFunc(Get("/debug/pprof/trace"), pprof.Trace)
+	mux.HandleFunc(Get("/debug/pprof/*"), pprof.Index)
+	return mux
+}
+
+// adminRoute is a route listed by the admin Mux.
+type adminRoute struct {
+	Route   string   `json:"route"`
+	Name    string   `json:"name,omitempty"`
+	Methods []string `json:"methods,omitempty"`
+}
+
+// adminRoutes returns the routes of the Mux, in registration order (see
+// Mux.Routes).
+func adminRoutes(m *Mux) []adminRoute {
+	var routes []adminRoute
+	for _, route := range m.Routes() {
+		routes = append(routes, adminRoute{
+			Route:   route.Template,
+			Name:    route.Name,
+			Methods: route.Methods,
+		})
+	}
+	return routes
+}
+
+// admin holds the options for an admin Mux.
+type admin struct {
+	liveness  http.Handler
+	readiness http.Handler
+	limits    http.Handler
+	slos      *SLOTracker
+}
+
+// AdminOption is an admin Mux option.
+type AdminOption func(*admin)
+
+// WithAdminHealth is an admin Mux option to set the liveness and readiness
+// handlers, such as those of a health.Checker.
+func WithAdminHealth(liveness, readiness http.Handler) AdminOption {
+	return func(a *admin) {
+		a.liveness, a.readiness = liveness, readiness
+	}
+}
+
+// WithAdminLimits is an admin Mux option to serve limiter introspection with
+// the handler, such as middleware.DefaultLimits.
+func WithAdminLimits(limits http.Handler) AdminOption {
+	return func(a *admin) {
+		a.limits = limits
+	}
+}
+
+// WithAdminSLOs is an admin Mux option to serve the SLO tracker's compliance
+// report.
+func WithAdminSLOs(slos *SLOTracker) AdminOption {
+	return func(a *admin) {
+		a.slos = slos
+	}
+}
@@ -0,0 +1,62 @@
+package goji
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAdminMux(t *testing.T) {
+	m := New()
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(Get("/users/:id", WithName("user")), h)
+	m.HandleFunc(NewPathSpec("/static/*"), h)
+	limits := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _ = res.Write([]byte("limits"))
+	})
+	admin := AdminMux(m, WithAdminLimits(limits))
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/healthz", http.StatusOK, "ok\n"},
+		{"/readyz", http.StatusOK, "ok\n"},
+		{"/limits", http.StatusOK, "limits"},
+		{"/metrics", http.StatusOK, ""},
+		{"/debug/pprof/", http.StatusOK, ""},
+		{"/debug/pprof/cmdline", http.StatusOK, ""},
+		{"/nope", http.StatusNotFound, ""},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		admin.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if test.body != "" && res.Body.String() != test.body {
+			t.Errorf("test %d expected %q, got: %q", i, test.body, res.Body.String())
+		}
+	}
+	m.Drain()
+	res := httptest.NewRecorder()
+	admin.ServeHTTP(res, httptest.NewRequest("GET", "/readyz", nil))
+	if res.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got: %d", http.StatusServiceUnavailable, res.Code)
+	}
+	res = httptest.NewRecorder()
+	admin.ServeHTTP(res, httptest.NewRequest("GET", "/routes", nil))
+	var routes []adminRoute
+	if err := json.Unmarshal(res.Body.Bytes(), &routes); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := []adminRoute{
+		{Route: "/users/:id", Name: "user", Methods: []string{"GET", "HEAD"}},
+		{Route: "/static/*"},
+	}
+	if !reflect.DeepEqual(routes, exp) {
+		t.Errorf("expected %v, got: %v", exp, routes)
+	}
+}
@@ -0,0 +1,78 @@
+package goji
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Attachment serves the content as a download with the filename, setting a
+// RFC 6266 Content-Disposition header with both an ASCII fallback filename
+// and a UTF-8 encoded filename* parameter.
+//
+// The content is served with http.ServeContent, which determines the
+// Content-Type from the filename's extension (unless already set), handles
+// Range and conditional requests using the modification time (when not
+// zero), and correctly handles HEAD requests. For example, for a report
+// export endpoint:
+//
+//	goji.Attachment(res, req, "rapport-été.csv", bytes.NewReader(buf), time.Now())
+func Attachment(res http.ResponseWriter, req *http.Request, filename string, content io.ReadSeeker, modtime time.Time) {
+	res.Header().Set("Content-Disposition", contentDisposition(filename))
+	http.ServeContent(res, req, filename, modtime, content)
+}
+
+// contentDisposition returns the attachment Content-Disposition header value
+// for the filename.
+func contentDisposition(filename string) string {
+	var ascii, ext strings.Builder
+	encode := false
+	for _, r := range filename {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			// drop control characters
+			encode = true
+		case r > 0x7f:
+			ascii.WriteByte('_')
+			encode = true
+		case r == '"' || r == '\\':
+			ascii.WriteByte('_')
+		default:
+			ascii.WriteRune(r)
+		}
+	}
+	s := `attachment; filename="` + ascii.String() + `"`
+	if !encode {
+		return s
+	}
+	// RFC 5987 attr-char
+	const hex = "0123456789ABCDEF"
+	for _, c := range []byte(filename) {
+		switch {
+		case c < 0x20 || c == 0x7f:
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			strings.IndexByte("!#$&+-.^_`|~", c) != -1:
+			ext.WriteByte(c)
+		default:
+			ext.WriteByte('%')
+			ext.WriteByte(hex[c>>4])
+			ext.WriteByte(hex[c&0xf])
+		}
+	}
+	return s + "; filename*=UTF-8''" + ext.String()
+}
+
+// ServeReaderAt serves dynamically generated content of a known size from
+// the io.ReaderAt, supporting Range and If-Range requests so that large
+// exports can be resumed without buffering the content. Only the requested
+// ranges are read from the content.
+//
+// As with http.ServeContent, the Content-Type is determined from the name's
+// extension (unless already set), and If-Range requests are validated using
+// the modification time (when not zero) or an ETag header set prior to
+// calling ServeReaderAt. Use with Attachment's Content-Disposition by
+// setting the header, or see Attachment for io.ReadSeeker content.
+func ServeReaderAt(res http.ResponseWriter, req *http.Request, name string, content io.ReaderAt, size int64, modtime time.Time) {
+	http.ServeContent(res, req, name, modtime, io.NewSectionReader(content, 0, size))
+}
@@ -0,0 +1,118 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		filename string
+		exp      string
+	}{
+		{"report.csv", `attachment; filename="report.csv"`},
+		{`a "quoted" \ name.txt`, `attachment; filename="a _quoted_ _ name.txt"`},
+		{"rapport-été.csv", `attachment; filename="rapport-_t_.csv"; filename*=UTF-8''rapport-%C3%A9t%C3%A9.csv`},
+		{"a\nb.txt", `attachment; filename="ab.txt"; filename*=UTF-8''ab.txt`},
+		{"100% done.txt", `attachment; filename="100% done.txt"`},
+	}
+	for i, test := range tests {
+		if s := contentDisposition(test.filename); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestAttachment(t *testing.T) {
+	modtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		method  string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{"GET", nil, http.StatusOK, "a,b,c\n"},
+		{"HEAD", nil, http.StatusOK, ""},
+		{"GET", map[string]string{"Range": "bytes=2-3"}, http.StatusPartialContent, "b,"},
+		{"GET", map[string]string{"If-Modified-Since": modtime.Format(http.TimeFormat)}, http.StatusNotModified, ""},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, "/", nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		res := httptest.NewRecorder()
+		Attachment(res, req, "export.csv", strings.NewReader("a,b,c\n"), modtime)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.body {
+			t.Errorf("test %d expected %q, got: %q", i, test.body, s)
+		}
+		if s := res.Header().Get("Content-Disposition"); s != `attachment; filename="export.csv"` {
+			t.Errorf("test %d expected content disposition, got: %q", i, s)
+		}
+		if test.status == http.StatusOK && res.Header().Get("Content-Type") == "" {
+			t.Errorf("test %d expected content type", i)
+		}
+	}
+}
+
+// countReaderAt is a io.ReaderAt that counts the bytes read.
+type countReaderAt struct {
+	n int
+}
+
+func (r *countReaderAt) ReadAt(buf []byte, off int64) (int, error) {
+	for i := range buf {
+		buf[i] = byte('a' + (off+int64(i))%26)
+	}
+	r.n += len(buf)
+	return len(buf), nil
+}
+
+func TestServeReaderAt(t *testing.T) {
+	modtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{map[string]string{"Range": "bytes=26-28"}, http.StatusPartialContent, "abc"},
+		{map[string]string{"Range": "bytes=-2"}, http.StatusPartialContent, "wx"},
+		{map[string]string{"Range": "bytes=1-2", "If-Range": modtime.Format(http.TimeFormat)}, http.StatusPartialContent, "bc"},
+		{map[string]string{"Range": "bytes=2000000-"}, http.StatusRequestedRangeNotSatisfiable, ""},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		r := new(countReaderAt)
+		res := httptest.NewRecorder()
+		ServeReaderAt(res, req, "export.json", r, 1<<20+2, modtime)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if test.status == http.StatusPartialContent {
+			if s := res.Body.String(); s != test.body {
+				t.Errorf("test %d expected %q, got: %q", i, test.body, s)
+			}
+			if r.n != len(test.body) {
+				t.Errorf("test %d expected %d bytes read, got: %d", i, len(test.body), r.n)
+			}
+		}
+	}
+	// stale If-Range serves the full content
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Range", "bytes=1-2")
+	req.Header.Set("If-Range", modtime.Add(-time.Hour).Format(http.TimeFormat))
+	res := httptest.NewRecorder()
+	ServeReaderAt(res, req, "export.json", new(countReaderAt), 100, modtime)
+	if res.Code != http.StatusOK || res.Body.Len() != 100 {
+		t.Errorf("expected %d with 100 bytes, got: %d %d", http.StatusOK, res.Code, res.Body.Len())
+	}
+}
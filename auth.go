@@ -0,0 +1,159 @@
+package goji
+
+import (
+	"context"
+	"net/http"
+)
+
+// AuthPolicy is a route authentication policy (see WithAuth).
+type AuthPolicy struct {
+	// Required is whether or not the route requires an authenticated
+	// principal.
+	Required bool
+	// Roles are the roles of which the principal must have at least one.
+	Roles []string
+}
+
+// Authenticator is the interface for authenticating requests, consulted by
+// the Mux before dispatching to routes with an authentication policy (see
+// WithAuthenticator). The authenticated principal is attached to the request
+// prior to the Mux's middleware.
+//
+// Principals may implement the interface { HasRole(string) bool } to be
+// checked against the roles of the route's policy.
+type Authenticator interface {
+	// Authenticate returns the principal for the request, or an error when
+	// the request could not be authenticated.
+	Authenticate(*http.Request) (interface{}, error)
+}
+
+// Challenger is the interface for Authenticators providing the
+// WWW-Authenticate challenge sent with 401 Unauthorized responses (default
+// "Bearer").
+type Challenger interface {
+	// Challenge returns the WWW-Authenticate challenge.
+	Challenge() string
+}
+
+// AuthenticatorFunc is an Authenticator func.
+type AuthenticatorFunc func(*http.Request) (interface{}, error)
+
+// Authenticate satisfies the Authenticator interface.
+func (f AuthenticatorFunc) Authenticate(req *http.Request) (interface{}, error) {
+	return f(req)
+}
+
+// WithPrincipal returns a child context with the passed authenticated
+// principal. Authentication middleware (JWT, OIDC, API keys, ...) can use
+// WithPrincipal to make the principal available to the Mux and handlers.
+func WithPrincipal(ctx context.Context, principal interface{}) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// Principal returns the authenticated principal for the request, or nil when
+// the request has not been authenticated.
+func Principal(req *http.Request) interface{} {
+	return req.Context().Value(principalKey)
+}
+
+// RouteAuth returns the authentication policy of the matched route for the
+// request, or nil when the route has no policy (see WithAuth). When the
+// matched route combines several Matchers with policies (see And), the first
+// policy is returned.
+func RouteAuth(req *http.Request) *AuthPolicy {
+	return firstAuth(routeTree(req))
+}
+
+// matcherAuth returns the first authentication policy of the matcher or the
+// Matchers it wraps or combines.
+func matcherAuth(matcher Matcher) *AuthPolicy {
+	return firstAuth(matcherTree(matcher))
+}
+
+// firstAuth returns the first authentication policy of the matcher tree.
+func firstAuth(tree []Matcher) *AuthPolicy {
+	for _, m := range policies[interface{ Auth() *AuthPolicy }](tree) {
+		if policy := m.Auth(); policy != nil {
+			return policy
+		}
+	}
+	return nil
+}
+
+// routeAuths returns the authentication policies of the matched route for
+// the request, including those of wrapped and combined Matchers.
+func routeAuths(req *http.Request) []*AuthPolicy {
+	var v []*AuthPolicy
+	for _, m := range policies[interface{ Auth() *AuthPolicy }](routeTree(req)) {
+		if policy := m.Auth(); policy != nil && (policy.Required || len(policy.Roles) != 0) {
+			v = append(v, policy)
+		}
+	}
+	return v
+}
+
+// authenticate returns the request with the principal authenticated by the
+// Authenticator, when the matched route has an authentication policy and
+// the request does not already carry a principal (see WithPrincipal).
+func authenticate(req *http.Request, auth Authenticator) *http.Request {
+	if auth == nil || Principal(req) != nil || len(routeAuths(req)) == 0 {
+		return req
+	}
+	if p, err := auth.Authenticate(req); err == nil && p != nil {
+		return req.WithContext(WithPrincipal(req.Context(), p))
+	}
+	return req
+}
+
+// authPolicy enforces the matched route's authentication policies (see
+// WithAuth), returning false when the request was rejected. The policies of
+// all Matchers wrapped or combined by the matched route are enforced.
+//
+// Requests to routes requiring authentication without a principal are
+// rejected with 401 Unauthorized and a WWW-Authenticate challenge (see
+// Challenger), and requests with a principal lacking the route's roles are
+// rejected with 403 Forbidden.
+func authPolicy(res http.ResponseWriter, req *http.Request, auth Authenticator) bool {
+	principal := Principal(req)
+	for _, policy := range routeAuths(req) {
+		switch {
+		case principal == nil:
+			challenge := "Bearer"
+			if c, ok := auth.(Challenger); ok {
+				challenge = c.Challenge()
+			}
+			res.Header().Set("WWW-Authenticate", challenge)
+			http.Error(res, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return false
+		case !hasRole(principal, policy.Roles):
+			http.Error(res, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// hasRole returns whether or not the principal has any of the roles.
+func hasRole(principal interface{}, roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	p, ok := principal.(interface{ HasRole(string) bool })
+	if !ok {
+		return false
+	}
+	for _, role := range roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAuthenticator is a mux option to set the Authenticator consulted
+// before dispatching requests.
+func WithAuthenticator(auth Authenticator) MuxOption {
+	return func(m *Mux) {
+		m.auth = auth
+	}
+}
@@ -0,0 +1,162 @@
+package goji
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// user is a test principal.
+type user struct {
+	name  string
+	roles []string
+}
+
+func (u user) HasRole(role string) bool {
+	return slices.Contains(u.roles, role)
+}
+
+func TestAuth(t *testing.T) {
+	auth := AuthenticatorFunc(func(req *http.Request) (interface{}, error) {
+		switch req.Header.Get("Authorization") {
+		case "admin":
+			return user{"alice", []string{"admin"}}, nil
+		case "user":
+			return user{"bob", []string{"user"}}, nil
+		}
+		return nil, errors.New("unauthenticated")
+	})
+	m := New(WithAuthenticator(auth))
+	h := func(res http.ResponseWriter, req *http.Request) {
+		if p, ok := Principal(req).(user); ok {
+			res.Write([]byte(p.name))
+		}
+	}
+	m.HandleFunc(Get("/public"), h)
+	m.HandleFunc(Get("/private", WithAuth(true)), h)
+	m.HandleFunc(Get("/admin", WithAuth(true, "admin")), h)
+	tests := []struct {
+		path   string
+		auth   string
+		status int
+		body   string
+	}{
+		{"/public", "", 200, ""},
+		{"/public", "user", 200, ""},
+		{"/private", "", 401, "Unauthorized\n"},
+		{"/private", "bad", 401, "Unauthorized\n"},
+		{"/private", "user", 200, "bob"},
+		{"/admin", "user", 403, "Forbidden\n"},
+		{"/admin", "admin", 200, "alice"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		if test.auth != "" {
+			req.Header.Set("Authorization", test.auth)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected status %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.body {
+			t.Errorf("test %d expected body %q, got: %q", i, test.body, s)
+		}
+	}
+}
+
+func TestAuthPrincipal(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/admin", WithAuth(true, "admin")), func(res http.ResponseWriter, req *http.Request) {
+		if p := RouteAuth(req); p == nil || !p.Required || len(p.Roles) != 1 {
+			t.Errorf("expected policy, got: %v", p)
+		}
+	})
+	// principal set by middleware
+	m.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") != "" {
+				req = req.WithContext(WithPrincipal(req.Context(), user{"alice", []string{"admin"}}))
+			}
+			h.ServeHTTP(res, req)
+		})
+	})
+	for _, test := range []struct {
+		auth   string
+		status int
+	}{
+		{"", 401},
+		{"token", 200},
+	} {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		if test.auth != "" {
+			req.Header.Set("Authorization", test.auth)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("expected status %d, got: %d", test.status, res.Code)
+		}
+	}
+}
+
+func TestAuthWrapped(t *testing.T) {
+	auth := AuthenticatorFunc(func(req *http.Request) (interface{}, error) {
+		if req.Header.Get("Authorization") == "admin" {
+			return user{"alice", []string{"admin"}}, nil
+		}
+		return nil, errors.New("unauthenticated")
+	})
+	var principal interface{}
+	m := New(WithAuthenticator(auth), WithMatcherIsolation(0))
+	m.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			principal = Principal(req)
+			h.ServeHTTP(res, req)
+		})
+	})
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(And(Header("X-A", ""), Get("/a", WithAuth(true, "admin"))), h)
+	m.HandleFunc(Or(Get("/b", WithAuth(true)), Get("/c")), h)
+	m.HandleFunc(Weighted(1, Get("/e", WithAuth(true))), h)
+	m.HandleFunc(Host("example.com", And(Header("X-F", ""), Get("/f", WithAuth(true)))), h)
+	m.HandleFunc(Not(Get("/d", WithAuth(true))), h)
+	tests := []struct {
+		path   string
+		auth   string
+		status int
+	}{
+		{"/a", "", 401},
+		{"/a", "admin", 200},
+		{"/b", "", 401},
+		{"/b", "admin", 200},
+		{"/c", "", 200},
+		{"/x", "", 200},
+		{"/e", "", 401},
+		{"/e", "admin", 200},
+		{"/f", "", 401},
+		{"/f", "admin", 200},
+	}
+	for i, test := range tests {
+		principal = nil
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("X-A", "a")
+		req.Header.Set("X-F", "f")
+		if test.auth != "" {
+			req.Header.Set("Authorization", test.auth)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected status %d, got: %d", i, test.status, res.Code)
+		}
+		if res.Code == 401 && res.Header().Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("test %d expected WWW-Authenticate %q, got: %q", i, "Bearer", res.Header().Get("WWW-Authenticate"))
+		}
+		if exp := test.auth != ""; (principal != nil) != exp {
+			t.Errorf("test %d expected principal in middleware %t, got: %v", i, exp, principal)
+		}
+	}
+}
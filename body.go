@@ -0,0 +1,59 @@
+package goji
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// bodyPolicy enforces the matched route's request body policy (see
+// WithMaxBody and WithContentTypes), returning false when the request was
+// rejected.
+func bodyPolicy(res http.ResponseWriter, req *http.Request) bool {
+	tree := routeTree(req)
+	if hasBody(req) {
+		for _, m := range policies[interface{ ContentTypes() []string }](tree) {
+			if types := m.ContentTypes(); len(types) != 0 && !matchContentType(req.Header.Get("Content-Type"), types) {
+				http.Error(res, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+				return false
+			}
+		}
+	}
+	var n int64
+	for _, m := range policies[interface{ MaxBody() int64 }](tree) {
+		if v := m.MaxBody(); v > 0 && (n == 0 || v < n) {
+			n = v
+		}
+	}
+	if n > 0 {
+		if req.ContentLength > n {
+			http.Error(res, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return false
+		}
+		if req.Body != nil {
+			req.Body = http.MaxBytesReader(res, req.Body, n)
+		}
+	}
+	return true
+}
+
+// hasBody returns whether or not the request has a body.
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0
+}
+
+// matchContentType returns whether or not the content type matches any of
+// the media types, where media types may have a "*" subtype.
+func matchContentType(contentType string, types []string) bool {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range types {
+		t = strings.ToLower(t)
+		if t == typ || strings.HasSuffix(t, "/*") && strings.HasPrefix(typ, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package goji
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyPolicy(t *testing.T) {
+	m := New()
+	h := func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.ReadAll(req.Body); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+		}
+	}
+	m.HandleFunc(Post("/upload", WithMaxBody(4), WithContentTypes("application/json", "image/*")), h)
+	m.HandleFunc(Host("example.com", Put("/upload", WithMaxBody(4))), h)
+	tests := []struct {
+		method string
+		ctype  string
+		body   string
+		chunk  bool
+		status int
+	}{
+		{"POST", "application/json", "{}", false, http.StatusOK},
+		{"POST", "application/json; charset=utf-8", "{}", false, http.StatusOK},
+		{"POST", "image/png", "png", false, http.StatusOK},
+		{"POST", "text/plain", "{}", false, http.StatusUnsupportedMediaType},
+		{"POST", "", "{}", false, http.StatusUnsupportedMediaType},
+		{"POST", "text/plain", "", false, http.StatusOK},
+		{"POST", "application/json", "[1,2,3]", false, http.StatusRequestEntityTooLarge},
+		{"POST", "application/json", "[1,2,3]", true, http.StatusBadRequest},
+		{"PUT", "text/plain", "abcde", false, http.StatusRequestEntityTooLarge},
+		{"PUT", "text/plain", "abcd", false, http.StatusOK},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, "http://example.com/upload", strings.NewReader(test.body))
+		if test.ctype != "" {
+			req.Header.Set("Content-Type", test.ctype)
+		}
+		if test.chunk {
+			req.ContentLength = -1
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+}
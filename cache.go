@@ -0,0 +1,29 @@
+package goji
+
+import "net/http"
+
+// cacheControl returns the response writer applying the matched route's
+// Cache-Control header (see WithCacheControl) to successful responses to GET
+// and HEAD requests.
+func cacheControl(res http.ResponseWriter, req *http.Request) http.ResponseWriter {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		return res
+	}
+	var cacheControl string
+	for _, m := range policies[interface{ CacheControl() string }](routeTree(req)) {
+		if cacheControl = m.CacheControl(); cacheControl != "" {
+			break
+		}
+	}
+	if cacheControl == "" {
+		return res
+	}
+	return &HeaderWriter{
+		ResponseWriter: res,
+		Func: func(h http.Header, status int) {
+			if status >= 200 && status < 300 && h.Get("Cache-Control") == "" {
+				h.Set("Cache-Control", cacheControl)
+			}
+		},
+	}
+}
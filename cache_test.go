@@ -0,0 +1,71 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheControl(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/:status", WithCacheControl("public, max-age=300")), func(res http.ResponseWriter, req *http.Request) {
+		switch Param(req, "status") {
+		case "404":
+			http.NotFound(res, req)
+		case "201":
+			res.WriteHeader(http.StatusCreated)
+		case "custom":
+			res.Header().Set("Cache-Control", "no-store")
+			res.Write([]byte("custom"))
+		case "flush":
+			res.(http.Flusher).Flush()
+		default:
+			res.Write([]byte("ok"))
+		}
+	})
+	m.HandleFunc(Get("/none/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("ok"))
+	})
+	m.HandleFunc(Post("/:status", WithCacheControl("public, max-age=300")), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("ok"))
+	})
+	tests := []struct {
+		method string
+		path   string
+		exp    string
+	}{
+		{"GET", "/ok", "public, max-age=300"},
+		{"HEAD", "/ok", "public, max-age=300"},
+		{"GET", "/201", "public, max-age=300"},
+		{"GET", "/flush", "public, max-age=300"},
+		{"GET", "/404", ""},
+		{"GET", "/custom", "no-store"},
+		{"GET", "/none/", ""},
+		{"POST", "/ok", ""},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if s := res.Header().Get("Cache-Control"); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestCacheControlHijack(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/ws", WithCacheControl("no-cache")), func(res http.ResponseWriter, req *http.Request) {
+		conn, _, err := http.NewResponseController(res).Hijack()
+		if err != nil {
+			t.Errorf("expected no error, got: %v", err)
+			return
+		}
+		conn.Close()
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+	if res, err := http.Get(s.URL + "/ws"); err == nil {
+		res.Body.Close()
+		t.Errorf("expected error for hijacked connection")
+	}
+}
@@ -0,0 +1,93 @@
+package goji
+
+import (
+	"crypto/x509"
+	"net/http"
+	"strings"
+)
+
+// ClientCertSpec provides a Matcher that matches requests based on the
+// request's verified TLS client certificate.
+//
+// ClientCertSpec is intended for use with And, allowing mTLS-only routes to
+// be registered on the same Mux as regular routes, when the server requests
+// (but does not require) client certificates:
+//
+//	server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+//	mux.HandleFunc(goji.And(goji.NewPathSpec("/admin/*"), goji.ClientCert("ops.example.com").OU("SRE")), admin)
+type ClientCertSpec struct {
+	names []string
+	units []string
+}
+
+// ClientCert returns a ClientCertSpec that matches requests with a verified
+// TLS client certificate. When names are provided, the certificate must have
+// a subject alternative name (DNS name, email address, or URI) equal to one
+// of the names.
+func ClientCert(names ...string) *ClientCertSpec {
+	return &ClientCertSpec{
+		names: names,
+	}
+}
+
+// OU sets the organizational units of the spec, returning the spec. The
+// certificate's subject must have one of the organizational units.
+func (s *ClientCertSpec) OU(units ...string) *ClientCertSpec {
+	s.units = append(s.units, units...)
+	return s
+}
+
+// Match satisfies the Matcher interface.
+func (s *ClientCertSpec) Match(req *http.Request) *http.Request {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := req.TLS.PeerCertificates[0]
+	if len(s.names) != 0 && !containsAny(s.names, certNames(cert)) {
+		return nil
+	}
+	if len(s.units) != 0 && !containsAny(s.units, cert.Subject.OrganizationalUnit) {
+		return nil
+	}
+	return req
+}
+
+// Methods satisfies the Matcher interface.
+func (s *ClientCertSpec) Methods() map[string]struct{} {
+	return nil
+}
+
+// Prefix satisfies the Matcher interface.
+func (s *ClientCertSpec) Prefix() string {
+	return ""
+}
+
+// String satisfies the fmt.Stringer interface.
+func (s *ClientCertSpec) String() string {
+	v := append([]string(nil), s.names...)
+	if len(s.units) != 0 {
+		v = append(v, "OU="+strings.Join(s.units, "|"))
+	}
+	return "ClientCert(" + strings.Join(v, ", ") + ")"
+}
+
+// certNames returns the subject alternative names of the certificate.
+func certNames(cert *x509.Certificate) []string {
+	names := append(append([]string(nil), cert.DNSNames...), cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+	return names
+}
+
+// containsAny returns whether or not any of the values is in v.
+func containsAny(v, values []string) bool {
+	for _, value := range values {
+		for _, s := range v {
+			if s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package goji
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientCert(t *testing.T) {
+	u, _ := url.Parse("spiffe://example.com/ops")
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{OrganizationalUnit: []string{"SRE"}},
+		DNSNames:       []string{"ops.example.com"},
+		EmailAddresses: []string{"carl@example.com"},
+		URIs:           []*url.URL{u},
+	}
+	verified := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+	unverified := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+	tests := []struct {
+		m   *ClientCertSpec
+		tls *tls.ConnectionState
+		exp bool
+	}{
+		{ClientCert(), nil, false},
+		{ClientCert(), new(tls.ConnectionState), false},
+		{ClientCert(), unverified, false},
+		{ClientCert(), verified, true},
+		{ClientCert("ops.example.com"), verified, true},
+		{ClientCert("carl@example.com"), verified, true},
+		{ClientCert("spiffe://example.com/ops"), verified, true},
+		{ClientCert("www.example.com"), verified, false},
+		{ClientCert().OU("SRE"), verified, true},
+		{ClientCert().OU("Sales"), verified, false},
+		{ClientCert("ops.example.com").OU("Sales", "SRE"), verified, true},
+		{ClientCert("www.example.com").OU("SRE"), verified, false},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.TLS = test.tls
+		if ok := test.m.Match(req) != nil; ok != test.exp {
+			t.Errorf("test %d expected %t, got: %t", i, test.exp, ok)
+		}
+	}
+}
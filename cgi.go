@@ -0,0 +1,58 @@
+package goji
+
+import (
+	"net"
+	"net/http"
+	"net/http/cgi"
+	"net/http/fcgi"
+	"os"
+	"strings"
+)
+
+// ServeCGI serves the current CGI request with the handler (see
+// net/http/cgi.Serve), for shared hosting and legacy integrations.
+//
+// The CGI script's path (the SCRIPT_NAME environment variable) is used as
+// the request's forwarded prefix (see ForwardedPrefix), so that a Mux routes
+// the path following the script's path (the PATH_INFO environment variable),
+// and builds URLs including the script's path (see Mux.URLFor and
+// AbsoluteURL):
+//
+//	func main() {
+//		mux := goji.New()
+//		mux.HandleFunc(goji.Get("/hello/:name"), hello)
+//		if err := goji.ServeCGI(mux); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func ServeCGI(h http.Handler) error {
+	return cgi.Serve(WithScriptName(h, os.Getenv("SCRIPT_NAME")))
+}
+
+// ServeFCGI serves FastCGI requests accepted on the listener with the
+// handler (see net/http/fcgi.Serve). When the listener is nil, requests are
+// accepted on standard input.
+//
+// As net/http/fcgi does not expose the SCRIPT_NAME parameter, the path the
+// application is mounted at by the web server is passed as the script name
+// (see WithScriptName).
+func ServeFCGI(l net.Listener, h http.Handler, scriptName string) error {
+	return fcgi.Serve(l, WithScriptName(h, scriptName))
+}
+
+// WithScriptName wraps the handler, setting the script name (the path the
+// handler is mounted at by a CGI or FastCGI web server) as the request's
+// forwarded prefix (see ForwardedPrefix) for requests with paths having the
+// script name as a prefix.
+func WithScriptName(h http.Handler, scriptName string) http.Handler {
+	scriptName = cleanPrefix(scriptName)
+	if scriptName == "" {
+		return h
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if path := req.URL.EscapedPath(); path == scriptName || strings.HasPrefix(path, scriptName+"/") {
+			req = req.WithContext(WithForwardedPrefix(req.Context(), scriptName))
+		}
+		h.ServeHTTP(res, req)
+	})
+}
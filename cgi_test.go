@@ -0,0 +1,37 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithScriptName(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/hello/:name"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(Param(req, "name") + " " + m.URLFor(req, "/hello/carl")))
+	})
+	h := WithScriptName(m, "/cgi-bin/app.cgi/")
+	tests := []struct {
+		path   string
+		status int
+		exp    string
+	}{
+		{"/cgi-bin/app.cgi/hello/alice", http.StatusOK, "alice /cgi-bin/app.cgi/hello/carl"},
+		{"/hello/alice", http.StatusOK, "alice /hello/carl"},
+		{"/cgi-bin/app.cgix/hello/alice", http.StatusNotFound, "404 page not found\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	if WithScriptName(m, "") != http.Handler(m) {
+		t.Errorf("expected handler to be unwrapped")
+	}
+}
@@ -0,0 +1,140 @@
+// Command gojilint checks goji route tables for problems (see
+// goji.Mux.Validate), such as conflicting, unreachable, and duplicate named
+// routes, printing diagnostics and exiting with a non-zero status when any
+// problems are found.
+//
+// Routes are loaded from declarative route configs (see the config package):
+//
+//	gojilint routes.json admin.json
+//
+// Alternatively, routes registered in code are loaded from a package
+// following the registration convention, that is, a package exporting a
+// Register func that registers the package's routes on a Mux:
+//
+//	package routes
+//
+//	func Register(mux *goji.Mux) {
+//		mux.HandleFunc(goji.Get("/users/:id"), user)
+//	}
+//
+// The package is specified by its import path, and gojilint must be run from
+// within the package's module:
+//
+//	gojilint -pkg example.com/app/routes
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+
+	"github.com/kenshaw/goji/config"
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command, returning the exit status.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gojilint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	pkg := fs.String("pkg", "", "import path of a package exporting a Register(*goji.Mux) func")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *pkg == "" && fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: gojilint [-pkg <import path>] [<config>...]")
+		return 2
+	}
+	status := 0
+	if *pkg != "" {
+		if err := lintPackage(ctx, *pkg, stdout, stderr); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				fmt.Fprintf(stderr, "%s: %v\n", *pkg, err)
+			}
+			status = 1
+		}
+	}
+	for _, name := range fs.Args() {
+		n, err := lintConfig(name, stdout)
+		switch {
+		case err != nil:
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			status = 1
+		case n != 0:
+			status = 1
+		}
+	}
+	return status
+}
+
+// lintConfig checks the route config file, returning the number of problems.
+func lintConfig(name string, w io.Writer) (int, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	cfg, err := config.Load(f)
+	if err != nil {
+		return 0, err
+	}
+	problems := config.Validate(cfg)
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s: %s\n", name, p)
+	}
+	return len(problems), nil
+}
+
+// lintPackage checks the routes registered by the package, by running a
+// generated main package that calls the package's Register func.
+func lintPackage(ctx context.Context, pkg string, stdout, stderr io.Writer) error {
+	dir, err := os.MkdirTemp(".", ".gojilint")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	var buf bytes.Buffer
+	if err := mainTemplate.Execute(&buf, pkg); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	return cmd.Run()
+}
+
+// mainTemplate is the template for the generated main package.
+var mainTemplate = template.Must(template.New("main.go").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kenshaw/goji"
+	routes {{ printf "%q" . }}
+)
+
+func main() {
+	mux := goji.New()
+	routes.Register(mux)
+	problems := mux.Validate()
+	for _, p := range problems {
+		fmt.Printf("%s: %s\n", {{ printf "%q" . }}, p)
+	}
+	if len(problems) != 0 {
+		os.Exit(1)
+	}
+}
+`))
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(valid, []byte(`{"routes": [{"method": "GET", "path": "/", "handler": "index"}]}`), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := os.WriteFile(invalid, []byte(`{"routes": [
+		{"method": "GET", "path": "/api/*", "handler": "api"},
+		{"method": "GET", "path": "/api/users", "handler": "users"}
+	]}`), 0o644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		args   []string
+		status int
+		out    string
+	}{
+		{nil, 2, ""},
+		{[]string{valid}, 0, ""},
+		{[]string{valid, invalid}, 1, invalid + ": Unreachable: /api/users: shadowed by /api/*\n"},
+		{[]string{filepath.Join(dir, "missing.json")}, 1, ""},
+	}
+	for i, test := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(context.Background(), test.args, &stdout, &stderr); status != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, status)
+		}
+		if s := stdout.String(); s != test.out {
+			t.Errorf("test %d expected %q, got: %q", i, test.out, s)
+		}
+	}
+}
+
+func TestMainTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := mainTemplate.Execute(&buf, "example.com/app/routes"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`routes "example.com/app/routes"`)) {
+		t.Errorf("expected import, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,230 @@
+package goji
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// and is a Matcher that matches when all of its Matchers match.
+type and []Matcher
+
+// And returns a Matcher that matches requests matching all of the matchers,
+// with each matcher passed the request returned by the previous matcher.
+// The methods and prefix are merged from the matchers, route metadata (see
+// Meta) is that of the first matcher carrying it, and the route policies
+// (see WithAuth) of all of the matchers are enforced. For example:
+//
+//	mux.Handle(goji.And(goji.Get("/admin/*"), goji.Header("X-Role", "admin")), admin)
+func And(matchers ...Matcher) Matcher {
+	return and(matchers)
+}
+
+// Match satisfies the Matcher interface.
+func (a and) Match(req *http.Request) *http.Request {
+	for _, m := range a {
+		if req = m.Match(req); req == nil {
+			return nil
+		}
+	}
+	return req
+}
+
+// Methods satisfies the Matcher interface, returning the intersection of
+// the matchers' methods.
+func (a and) Methods() map[string]struct{} {
+	var methods map[string]struct{}
+	for _, m := range a {
+		ms := m.Methods()
+		switch {
+		case ms == nil:
+		case methods == nil:
+			methods = make(map[string]struct{}, len(ms))
+			for method := range ms {
+				methods[method] = struct{}{}
+			}
+		default:
+			for method := range methods {
+				if _, ok := ms[method]; !ok {
+					delete(methods, method)
+				}
+			}
+		}
+	}
+	return methods
+}
+
+// Prefix satisfies the Matcher interface, returning the longest of the
+// matchers' prefixes.
+func (a and) Prefix() string {
+	var prefix string
+	for _, m := range a {
+		if p := m.Prefix(); len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+	return prefix
+}
+
+// matchers returns the combined Matchers.
+func (a and) matchers() []Matcher {
+	return a
+}
+
+// String satisfies the fmt.Stringer interface.
+func (a and) String() string {
+	return join("And", a)
+}
+
+// or is a Matcher that matches when any of its Matchers match.
+type or []Matcher
+
+// Or returns a Matcher that matches requests matching any of the matchers,
+// returning the request returned by the first matching matcher. The methods
+// and prefix are merged from the matchers, and only the route policies (see
+// WithAuth) of the matching matcher are enforced.
+func Or(matchers ...Matcher) Matcher {
+	return or(matchers)
+}
+
+// orKey is the context key type for the index of the matching Matcher of an
+// Or, identified by its first Matcher.
+type orKey struct {
+	m *Matcher
+}
+
+// Match satisfies the Matcher interface.
+func (o or) Match(req *http.Request) *http.Request {
+	for i, m := range o {
+		if r := m.Match(req); r != nil {
+			return r.WithContext(context.WithValue(r.Context(), orKey{o.key()}, i))
+		}
+	}
+	return nil
+}
+
+// key returns the Or's identity.
+func (o or) key() *Matcher {
+	if len(o) == 0 {
+		return nil
+	}
+	return &o[0]
+}
+
+// Methods satisfies the Matcher interface, returning the union of the
+// matchers' methods, or nil when any matcher matches all methods.
+func (o or) Methods() map[string]struct{} {
+	methods := make(map[string]struct{})
+	for _, m := range o {
+		ms := m.Methods()
+		if ms == nil {
+			return nil
+		}
+		for method := range ms {
+			methods[method] = struct{}{}
+		}
+	}
+	return methods
+}
+
+// Prefix satisfies the Matcher interface, returning the common prefix of
+// the matchers' prefixes.
+func (o or) Prefix() string {
+	if len(o) == 0 {
+		return ""
+	}
+	prefix := o[0].Prefix()
+	for _, m := range o[1:] {
+		prefix = commonPrefix(prefix, m.Prefix())
+	}
+	return prefix
+}
+
+// String satisfies the fmt.Stringer interface.
+func (o or) String() string {
+	return join("Or", o)
+}
+
+// not is a Matcher that matches when its Matcher does not match.
+type not struct {
+	m Matcher
+}
+
+// Not returns a Matcher that matches requests not matching the matcher. The
+// route policies (see WithAuth) of the matcher are not enforced.
+func Not(matcher Matcher) Matcher {
+	return not{matcher}
+}
+
+// Match satisfies the Matcher interface.
+func (n not) Match(req *http.Request) *http.Request {
+	if n.m.Match(req) != nil {
+		return nil
+	}
+	return req
+}
+
+// Methods satisfies the Matcher interface.
+func (not) Methods() map[string]struct{} {
+	return nil
+}
+
+// Prefix satisfies the Matcher interface.
+func (not) Prefix() string {
+	return ""
+}
+
+// String satisfies the fmt.Stringer interface.
+func (n not) String() string {
+	return join("Not", []Matcher{n.m})
+}
+
+// header is a Matcher for a request header.
+type header struct {
+	name, value string
+}
+
+// Header returns a Matcher that matches requests with the header value, or
+// with the header present when the value is empty. Header is intended for
+// use with And, Or, and Not.
+func Header(name, value string) Matcher {
+	return header{http.CanonicalHeaderKey(name), value}
+}
+
+// Match satisfies the Matcher interface.
+func (h header) Match(req *http.Request) *http.Request {
+	v, ok := req.Header[h.name]
+	if !ok || h.value != "" && (len(v) == 0 || v[0] != h.value) {
+		return nil
+	}
+	return req
+}
+
+// Methods satisfies the Matcher interface.
+func (header) Methods() map[string]struct{} {
+	return nil
+}
+
+// Prefix satisfies the Matcher interface.
+func (header) Prefix() string {
+	return ""
+}
+
+// String satisfies the fmt.Stringer interface.
+func (h header) String() string {
+	return fmt.Sprintf("Header(%s=%s)", h.name, h.value)
+}
+
+// join returns the string form of the named combinator.
+func join(name string, matchers []Matcher) string {
+	s := make([]string, len(matchers))
+	for i, m := range matchers {
+		if v, ok := m.(fmt.Stringer); ok {
+			s[i] = v.String()
+		} else {
+			s[i] = fmt.Sprintf("%T", m)
+		}
+	}
+	return name + "(" + strings.Join(s, ", ") + ")"
+}
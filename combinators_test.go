@@ -0,0 +1,84 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	m := New()
+	h := func(s string) http.HandlerFunc {
+		return func(res http.ResponseWriter, _ *http.Request) {
+			_, _ = res.Write([]byte(s))
+		}
+	}
+	m.Handle(And(Get("/admin/*"), Header("X-Role", "admin")), h("admin"))
+	m.Handle(Or(Get("/a"), Post("/b")), h("or"))
+	m.Handle(And(NewPathSpec("/c/*"), Not(Header("X-Debug", ""))), h("c"))
+	tests := []struct {
+		method string
+		path   string
+		header string
+		exp    string
+	}{
+		{"GET", "/admin/users", "admin", "admin"},
+		{"GET", "/admin/users", "user", "404 page not found\n"},
+		{"POST", "/admin/users", "admin", "404 page not found\n"},
+		{"GET", "/a", "", "or"},
+		{"POST", "/b", "", "or"},
+		{"POST", "/a", "", "404 page not found\n"},
+		{"GET", "/c/d", "", "c"},
+		{"GET", "/c/d", "admin", "404 page not found\n"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		if test.header != "" {
+			req.Header.Set("X-Role", test.header)
+			req.Header.Set("X-Debug", "1")
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestCombinatorsMerge(t *testing.T) {
+	tests := []struct {
+		m       Matcher
+		methods map[string]struct{}
+		prefix  string
+	}{
+		{And(Get("/a/*"), Header("X", "")), map[string]struct{}{"GET": {}, "HEAD": {}}, "/a/"},
+		{And(NewPathSpec("/a/b/*"), Get("/a/*")), map[string]struct{}{"GET": {}, "HEAD": {}}, "/a/b/"},
+		{And(Get("/a"), Post("/a")), map[string]struct{}{}, "/a"},
+		{Or(Get("/a/b"), Post("/a/c")), map[string]struct{}{"GET": {}, "HEAD": {}, "POST": {}}, "/a/"},
+		{Or(Get("/a"), NewPathSpec("/b")), nil, "/"},
+		{Not(Get("/a")), nil, ""},
+	}
+	for i, test := range tests {
+		if methods := test.m.Methods(); !reflect.DeepEqual(methods, test.methods) {
+			t.Errorf("test %d expected methods %v, got: %v", i, test.methods, methods)
+		}
+		if prefix := test.m.Prefix(); prefix != test.prefix {
+			t.Errorf("test %d expected prefix %q, got: %q", i, test.prefix, prefix)
+		}
+	}
+}
+
+func TestCombinatorsMatched(t *testing.T) {
+	m := New()
+	var template string
+	m.HandleFunc(And(Get("/user/:name", WithName("user")), Header("X-Role", "")), func(_ http.ResponseWriter, req *http.Request) {
+		template = RouteTemplate(req) + " " + RouteName(req) + " " + Param(req, "name")
+	})
+	req := httptest.NewRequest("GET", "/user/carl", nil)
+	req.Header.Set("X-Role", "admin")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	if exp := "/user/:name user carl"; template != exp {
+		t.Errorf("expected %q, got: %q", exp, template)
+	}
+}
@@ -0,0 +1,188 @@
+// Package config provides declarative route configuration for goji.Mux.
+//
+// Routes, along with their per-route policy (timeouts, body limits, and rate
+// tiers), are declared as data, and are registered on a Mux with the policy
+// applied automatically via the corresponding middleware:
+//
+//	{
+//	  "routes": [
+//	    {"method": "GET", "path": "/users/:name", "handler": "user", "timeout": "5s"},
+//	    {"method": "POST", "path": "/upload", "handler": "upload", "max_body": 1048576, "rate_tier": "low"}
+//	  ]
+//	}
+//
+// Configs are decoded from JSON with Load. Route definitions also carry yaml
+// struct tags, allowing configs to be decoded from YAML with a third-party
+// YAML package.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kenshaw/goji"
+	"github.com/kenshaw/goji/middleware"
+)
+
+// Config is a declarative route config.
+type Config struct {
+	Routes []RouteDef `json:"routes" yaml:"routes"`
+}
+
+// RouteDef is a declarative route definition.
+type RouteDef struct {
+	// Method is the HTTP method for the route. GET routes also match HEAD
+	// requests. When empty, all methods are matched.
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// Path is the route's path spec (see goji.PathSpec).
+	Path string `json:"path" yaml:"path"`
+	// Handler is the name of the route's handler.
+	Handler string `json:"handler" yaml:"handler"`
+	// Timeout is the total time allowed to handle a request for the route
+	// (see middleware.Timeout).
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// MaxBody is the maximum request body size, in bytes, for the route (see
+	// middleware.MaxBody).
+	MaxBody int64 `json:"max_body,omitempty" yaml:"max_body,omitempty"`
+	// RateTier is the name of the rate tier for the route (see WithRateTier).
+	RateTier string `json:"rate_tier,omitempty" yaml:"rate_tier,omitempty"`
+}
+
+// Duration is a time.Duration that is encoded as a string (for example,
+// "1m30s").
+type Duration time.Duration
+
+// MarshalText satisfies the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(buf []byte) error {
+	v, err := time.ParseDuration(string(buf))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Load decodes a JSON route config from the reader.
+func Load(r io.Reader) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Error values.
+var (
+	// ErrUnknownHandler is the unknown handler error.
+	ErrUnknownHandler = errors.New("unknown handler")
+	// ErrUnknownRateTier is the unknown rate tier error.
+	ErrUnknownRateTier = errors.New("unknown rate tier")
+)
+
+// Apply registers the config's routes on the Mux, using the named handlers,
+// and wrapping each handler with the middleware for the route's policy.
+// Routes are validated prior to registration, and no routes are registered
+// if any route is invalid.
+func Apply(m *goji.Mux, cfg Config, handlers map[string]http.Handler, opts ...Option) error {
+	a := &applier{
+		tiers: make(map[string]func(http.Handler) http.Handler),
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	type route struct {
+		spec    *goji.PathSpec
+		handler http.Handler
+	}
+	var routes []route
+	for i, def := range cfg.Routes {
+		h, err := a.build(def, handlers)
+		if err != nil {
+			return fmt.Errorf("route %d (%s %s): %w", i, def.Method, def.Path, err)
+		}
+		routes = append(routes, route{def.Spec(), h})
+	}
+	for _, r := range routes {
+		m.Handle(r.spec, r.handler)
+	}
+	return nil
+}
+
+// Validate checks the config's route table (see goji.Mux.Validate) without
+// the route handlers, returning the problems found.
+func Validate(cfg Config) []goji.Problem {
+	m := goji.New()
+	for _, def := range cfg.Routes {
+		m.Handle(def.Spec(), http.NotFoundHandler())
+	}
+	return m.Validate()
+}
+
+// Spec returns the route definition's path spec.
+func (def RouteDef) Spec() *goji.PathSpec {
+	switch method := strings.ToUpper(def.Method); method {
+	case "":
+		return goji.NewPathSpec(def.Path)
+	case "GET":
+		return goji.Get(def.Path)
+	default:
+		return goji.NewPathSpec(def.Path, goji.WithMethod(method))
+	}
+}
+
+// applier applies route configs.
+type applier struct {
+	tiers map[string]func(http.Handler) http.Handler
+}
+
+// build builds the handler for the route definition.
+func (a *applier) build(def RouteDef, handlers map[string]http.Handler) (http.Handler, error) {
+	h, ok := handlers[def.Handler]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownHandler, def.Handler)
+	}
+	// applied innermost first
+	if def.Timeout > 0 {
+		h = middleware.Timeout(time.Duration(def.Timeout))(h)
+	}
+	if def.MaxBody > 0 {
+		h = middleware.MaxBody(def.MaxBody)(h)
+	}
+	if def.RateTier != "" {
+		tier, ok := a.tiers[def.RateTier]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownRateTier, def.RateTier)
+		}
+		h = tier(h)
+	}
+	return h, nil
+}
+
+// Option is a config apply option.
+type Option func(*applier)
+
+// WithRateTier is a config apply option to define the rate limiting
+// middleware for the named rate tier, for example:
+//
+//	config.WithRateTier("low", middleware.RateLimit(1, 5))
+//
+// Note that the middleware is shared by all routes with the rate tier, and
+// per-route rate limiting middleware (such as middleware.RateLimit) tracks
+// state per route.
+func WithRateTier(name string, mw func(http.Handler) http.Handler) Option {
+	return func(a *applier) {
+		a.tiers[name] = mw
+	}
+}
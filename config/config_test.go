@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+	"github.com/kenshaw/goji/middleware"
+)
+
+func TestApply(t *testing.T) {
+	cfg, err := Load(strings.NewReader(`{
+		"routes": [
+			{"method": "GET", "path": "/slow", "handler": "slow", "timeout": "10ms"},
+			{"method": "post", "path": "/upload", "handler": "upload", "max_body": 5},
+			{"path": "/limited", "handler": "ok", "rate_tier": "low"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if d := time.Duration(cfg.Routes[0].Timeout); d != 10*time.Millisecond {
+		t.Errorf("expected 10ms, got: %v", d)
+	}
+	handlers := map[string]http.Handler{
+		"slow": http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			<-req.Context().Done()
+		}),
+		"upload": http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}),
+		"ok":     http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}),
+	}
+	m := goji.New()
+	if err := Apply(m, cfg, handlers, WithRateTier("low", middleware.RateLimit(0.001, 1))); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"GET", "/slow", "", http.StatusServiceUnavailable},
+		{"HEAD", "/slow", "", http.StatusServiceUnavailable},
+		{"POST", "/upload", "hello", http.StatusOK},
+		{"POST", "/upload", "hello world", http.StatusRequestEntityTooLarge},
+		{"GET", "/upload", "", http.StatusNotFound},
+		{"PUT", "/limited", "", http.StatusOK},
+		{"PUT", "/limited", "", http.StatusTooManyRequests},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+		if res.Code != test.status {
+			t.Errorf("test %d %s %s expected %d, got: %d", i, test.method, test.path, test.status, res.Code)
+		}
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		def RouteDef
+		err error
+	}{
+		{RouteDef{Path: "/", Handler: "nope"}, ErrUnknownHandler},
+		{RouteDef{Path: "/", Handler: "ok", RateTier: "nope"}, ErrUnknownRateTier},
+	}
+	for i, test := range tests {
+		m := goji.New()
+		cfg := Config{Routes: []RouteDef{{Path: "/first", Handler: "ok"}, test.def}}
+		err := Apply(m, cfg, map[string]http.Handler{"ok": http.NotFoundHandler()})
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected %v, got: %v", i, test.err, err)
+		}
+		if n := m.Stats().Routes; n != 0 {
+			t.Errorf("test %d expected no routes, got: %d", i, n)
+		}
+	}
+	if _, err := Load(strings.NewReader(`{"routes": [{"path": "/", "timeout": "nope"}]}`)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg, err := Load(strings.NewReader(`{
+		"routes": [
+			{"method": "GET", "path": "/api/*", "handler": "api"},
+			{"method": "GET", "path": "/api/users", "handler": "users"},
+			{"method": "POST", "path": "/api/users", "handler": "create"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	problems := Validate(cfg)
+	if len(problems) != 1 || problems[0].Type != goji.ProblemUnreachable || problems[0].Route != "/api/users" {
+		t.Errorf("expected unreachable /api/users, got: %v", problems)
+	}
+}
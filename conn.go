@@ -0,0 +1,157 @@
+package goji
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// ConnStats is a gauge of a server's connections by state, suitable for
+// capacity planning and for exposing via metrics.
+//
+// A ConnStats is safe for concurrent use, and can be shared by multiple
+// servers.
+type ConnStats struct {
+	mu     sync.Mutex
+	conns  map[net.Conn]http.ConnState
+	counts map[http.ConnState]int64
+	total  int64
+}
+
+// NewConnStats creates a new connection gauge.
+func NewConnStats() *ConnStats {
+	return &ConnStats{
+		conns:  make(map[net.Conn]http.ConnState),
+		counts: make(map[http.ConnState]int64),
+	}
+}
+
+// ConnState tracks the connection state change. ConnState satisfies the
+// http.Server.ConnState signature.
+func (cs *ConnStats) ConnState(conn net.Conn, state http.ConnState) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if prev, ok := cs.conns[conn]; ok {
+		cs.counts[prev]--
+	}
+	switch state {
+	case http.StateNew:
+		cs.total++
+		fallthrough
+	case http.StateActive, http.StateIdle:
+		cs.conns[conn] = state
+		cs.counts[state]++
+	default:
+		delete(cs.conns, conn)
+	}
+}
+
+// Count returns the number of connections currently in the state. Closed and
+// hijacked connections are not tracked.
+func (cs *ConnStats) Count(state http.ConnState) int64 {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.counts[state]
+}
+
+// Active returns the number of active connections.
+func (cs *ConnStats) Active() int64 {
+	return cs.Count(http.StateActive)
+}
+
+// Idle returns the number of idle connections.
+func (cs *ConnStats) Idle() int64 {
+	return cs.Count(http.StateIdle)
+}
+
+// Open returns the number of open (new, active, or idle) connections.
+func (cs *ConnStats) Open() int64 {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return int64(len(cs.conns))
+}
+
+// Total returns the total number of accepted connections.
+func (cs *ConnStats) Total() int64 {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.total
+}
+
+// Stats returns a snapshot of the connection counts.
+func (cs *ConnStats) Stats() map[string]int64 {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return map[string]int64{
+		"new":    cs.counts[http.StateNew],
+		"active": cs.counts[http.StateActive],
+		"idle":   cs.counts[http.StateIdle],
+		"open":   int64(len(cs.conns)),
+		"total":  cs.total,
+	}
+}
+
+// ConnHooks are typed connection state callbacks. Nil callbacks are ignored.
+type ConnHooks struct {
+	New      func(net.Conn)
+	Active   func(net.Conn)
+	Idle     func(net.Conn)
+	Hijacked func(net.Conn)
+	Closed   func(net.Conn)
+}
+
+// ConnState dispatches the connection state change to the corresponding
+// callback. ConnState satisfies the http.Server.ConnState signature.
+func (h ConnHooks) ConnState(conn net.Conn, state http.ConnState) {
+	var f func(net.Conn)
+	switch state {
+	case http.StateNew:
+		f = h.New
+	case http.StateActive:
+		f = h.Active
+	case http.StateIdle:
+		f = h.Idle
+	case http.StateHijacked:
+		f = h.Hijacked
+	case http.StateClosed:
+		f = h.Closed
+	}
+	if f != nil {
+		f(conn)
+	}
+}
+
+// WithConnState is a server option to add a connection state callback.
+// Multiple callbacks are invoked in the order added.
+//
+// The callback is applied to every server started by Run, including servers
+// added with WithAddr and the ACME redirect server (see WithACME).
+func WithConnState(f func(net.Conn, http.ConnState)) ServerOption {
+	return func(s *server) {
+		s.connState = append(s.connState, f)
+	}
+}
+
+// connState returns the connection state callback invoking prev (when not
+// nil) followed by the callbacks.
+func connState(prev func(net.Conn, http.ConnState), callbacks []func(net.Conn, http.ConnState)) func(net.Conn, http.ConnState) {
+	return func(conn net.Conn, state http.ConnState) {
+		if prev != nil {
+			prev(conn, state)
+		}
+		for _, f := range callbacks {
+			f(conn, state)
+		}
+	}
+}
+
+// WithConnHooks is a server option to add typed connection state callbacks.
+func WithConnHooks(h ConnHooks) ServerOption {
+	return WithConnState(h.ConnState)
+}
+
+// WithConnStats is a server option to track the server's connections with the
+// connection gauge.
+func WithConnStats(cs *ConnStats) ServerOption {
+	return WithConnState(cs.ConnState)
+}
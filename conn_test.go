@@ -0,0 +1,87 @@
+package goji
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConnStats(t *testing.T) {
+	cs := NewConnStats()
+	a, b := &net.TCPConn{}, &net.TCPConn{}
+	cs.ConnState(a, http.StateNew)
+	cs.ConnState(b, http.StateNew)
+	cs.ConnState(a, http.StateActive)
+	cs.ConnState(b, http.StateActive)
+	cs.ConnState(b, http.StateIdle)
+	if active, idle, open := cs.Active(), cs.Idle(), cs.Open(); active != 1 || idle != 1 || open != 2 {
+		t.Errorf("expected active=1 idle=1 open=2, got: %d %d %d", active, idle, open)
+	}
+	cs.ConnState(a, http.StateHijacked)
+	cs.ConnState(b, http.StateClosed)
+	exp := map[string]int64{"new": 0, "active": 0, "idle": 0, "open": 0, "total": 2}
+	if stats := cs.Stats(); !reflect.DeepEqual(stats, exp) {
+		t.Errorf("expected %v, got: %v", exp, stats)
+	}
+}
+
+func TestWithConnHooks(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	cs := NewConnStats()
+	var mu sync.Mutex
+	var states []string
+	hook := func(name string) func(net.Conn) {
+		return func(net.Conn) {
+			mu.Lock()
+			defer mu.Unlock()
+			states = append(states, name)
+		}
+	}
+	m := New()
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		if active := cs.Active(); active != 1 {
+			t.Errorf("expected 1 active connection, got: %d", active)
+		}
+		res.Write([]byte("hello"))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	started, done := make(chan bool), make(chan error)
+	go func() {
+		done <- Run(ctx, "", m, WithListener(l), WithConnStats(cs), WithConnHooks(ConnHooks{
+			New:    hook("new"),
+			Active: hook("active"),
+			Idle:   hook("idle"),
+			Closed: hook("closed"),
+		}), OnStart(func(context.Context) error {
+			close(started)
+			return nil
+		}))
+	}()
+	<-started
+	cl := &http.Client{Transport: new(http.Transport)}
+	expectBody(t, cl, "http://"+l.Addr().String()+"/", "hello")
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	// closed state is set asynchronously after shutdown
+	for i := 0; i < 100 && cs.Open() != 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if total, open := cs.Total(), cs.Open(); total != 1 || open != 0 {
+		t.Errorf("expected total=1 open=0, got: %d %d", total, open)
+	}
+	exp := []string{"new", "active", "idle", "closed"}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(states, exp) {
+		t.Errorf("expected %v, got: %v", exp, states)
+	}
+}
@@ -0,0 +1,232 @@
+package goji
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidCookie is the invalid cookie error, returned when a cookie value
+// cannot be verified or decrypted.
+var ErrInvalidCookie = errors.New("invalid cookie")
+
+// ErrCookieExpired is the cookie expired error, returned when a cookie value
+// was issued before the codec's max age (see WithCookieMaxAge).
+var ErrCookieExpired = errors.New("cookie expired")
+
+// ErrCookieTooLarge is the cookie too large error, returned when an encoded
+// cookie exceeds the maximum cookie size.
+var ErrCookieTooLarge = errors.New("cookie too large")
+
+// maxCookieSize is the maximum size of an encoded cookie.
+const maxCookieSize = 4096
+
+// CookieCodec encodes and decodes HMAC-signed and/or AES-GCM encrypted
+// cookie values, bound to the cookie name. Encoded values include the time
+// they were issued, allowing the codec to reject values older than its max
+// age (see WithCookieMaxAge), regardless of the cookie's expiry sent to the
+// client.
+//
+// Multiple keys may be provided to support key rotation: values are signed
+// (or encrypted) with the first key, and are verified (or decrypted) with any
+// of the keys. For example, to rotate a signing key, prepend the new key,
+// and remove the old key once cookies signed with it have expired:
+//
+//	codec := goji.NewCookieCodec(goji.WithCookieSigning(newKey, oldKey))
+type CookieCodec struct {
+	signKeys [][]byte
+	aeads    []cipher.AEAD
+	maxAge   time.Duration
+	now      func() time.Time
+}
+
+// NewCookieCodec returns a new cookie codec. At least one signing or
+// encryption key must be provided (see WithCookieSigning and
+// WithCookieEncryption). A codec without keys, or with an invalid encryption
+// key, is considered a programmer error and will trigger a panic.
+func NewCookieCodec(opts ...CookieOption) *CookieCodec {
+	c := &CookieCodec{
+		now: time.Now,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if len(c.signKeys) == 0 && len(c.aeads) == 0 {
+		panic("goji: cookie codec requires a signing or encryption key")
+	}
+	return c
+}
+
+// Encode encodes the value for the named cookie.
+func (c *CookieCodec) Encode(name, value string) (string, error) {
+	buf := binary.BigEndian.AppendUint64(nil, uint64(c.now().Unix()))
+	buf = append(buf, value...)
+	if len(c.aeads) != 0 {
+		nonce := make([]byte, c.aeads[0].NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		buf = c.aeads[0].Seal(nonce, nonce, buf, []byte(name))
+	}
+	if len(c.signKeys) != 0 {
+		buf = append(buf, cookieMAC(c.signKeys[0], name, buf)...)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Decode decodes the value of the named cookie, returning ErrInvalidCookie
+// when the value cannot be verified or decrypted, and ErrCookieExpired when
+// the value was issued before the codec's max age.
+func (c *CookieCodec) Decode(name, value string) (string, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	if len(c.signKeys) != 0 {
+		if len(buf) < sha256.Size {
+			return "", ErrInvalidCookie
+		}
+		var mac []byte
+		buf, mac = buf[:len(buf)-sha256.Size], buf[len(buf)-sha256.Size:]
+		if !c.verify(name, buf, mac) {
+			return "", ErrInvalidCookie
+		}
+	}
+	if len(c.aeads) != 0 {
+		if buf, err = c.decrypt(name, buf); err != nil {
+			return "", err
+		}
+	}
+	if len(buf) < 8 {
+		return "", ErrInvalidCookie
+	}
+	issued := time.Unix(int64(binary.BigEndian.Uint64(buf)), 0)
+	if c.maxAge > 0 && c.now().Sub(issued) > c.maxAge {
+		return "", ErrCookieExpired
+	}
+	return string(buf[8:]), nil
+}
+
+// verify returns whether or not the mac is valid for any of the signing
+// keys.
+func (c *CookieCodec) verify(name string, buf, mac []byte) bool {
+	for _, key := range c.signKeys {
+		if hmac.Equal(mac, cookieMAC(key, name, buf)) {
+			return true
+		}
+	}
+	return false
+}
+
+// decrypt decrypts the value with any of the encryption keys.
+func (c *CookieCodec) decrypt(name string, buf []byte) ([]byte, error) {
+	for _, aead := range c.aeads {
+		if len(buf) < aead.NonceSize() {
+			continue
+		}
+		nonce, ciphertext := buf[:aead.NonceSize()], buf[aead.NonceSize():]
+		if plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, ErrInvalidCookie
+}
+
+// cookieMAC returns the HMAC-SHA256 of the cookie name and value.
+func cookieMAC(key []byte, name string, buf []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write(buf)
+	return h.Sum(nil)
+}
+
+// CookieOption is a cookie codec option.
+type CookieOption func(*CookieCodec)
+
+// WithCookieSigning is a cookie codec option to sign cookie values with
+// HMAC-SHA256 using the keys (see CookieCodec for key rotation).
+func WithCookieSigning(keys ...[]byte) CookieOption {
+	return func(c *CookieCodec) {
+		c.signKeys = append(c.signKeys, keys...)
+	}
+}
+
+// WithCookieEncryption is a cookie codec option to encrypt cookie values
+// with AES-GCM using the keys, which must be 16, 24, or 32 bytes (see
+// CookieCodec for key rotation).
+func WithCookieEncryption(keys ...[]byte) CookieOption {
+	return func(c *CookieCodec) {
+		for _, key := range keys {
+			block, err := aes.NewCipher(key)
+			if err != nil {
+				panic(fmt.Sprintf("goji: invalid cookie encryption key: %v", err))
+			}
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				panic(fmt.Sprintf("goji: invalid cookie encryption key: %v", err))
+			}
+			c.aeads = append(c.aeads, aead)
+		}
+	}
+}
+
+// WithCookieMaxAge is a cookie codec option to reject values issued longer
+// ago than the max age with ErrCookieExpired. Values are accepted
+// regardless of age when the max age is 0 (the default).
+func WithCookieMaxAge(maxAge time.Duration) CookieOption {
+	return func(c *CookieCodec) {
+		c.maxAge = maxAge
+	}
+}
+
+// SetCookie adds a Set-Cookie header to the response for the cookie, with
+// the cookie's value encoded by the codec. When the codec is nil, the
+// cookie's value is not encoded.
+//
+// For example:
+//
+//	err := goji.SetCookie(res, &http.Cookie{Name: "session", Value: id, HttpOnly: true}, codec)
+func SetCookie(res http.ResponseWriter, cookie *http.Cookie, codec *CookieCodec) error {
+	if codec != nil {
+		value, err := codec.Encode(cookie.Name, cookie.Value)
+		if err != nil {
+			return err
+		}
+		c := *cookie
+		c.Value, cookie = value, &c
+	}
+	v := cookie.String()
+	switch {
+	case v == "":
+		return ErrInvalidCookie
+	case len(v) > maxCookieSize:
+		return ErrCookieTooLarge
+	}
+	res.Header().Add("Set-Cookie", v)
+	return nil
+}
+
+// GetCookie returns the value of the named cookie of the request, decoded by
+// the codec. When the codec is nil, the cookie's value is not decoded.
+// Returns http.ErrNoCookie when the cookie is not present, ErrInvalidCookie
+// when the cookie's value cannot be decoded, and ErrCookieExpired when the
+// cookie's value has expired.
+func GetCookie(req *http.Request, name string, codec *CookieCodec) (string, error) {
+	cookie, err := req.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if codec == nil {
+		return cookie.Value, nil
+	}
+	return codec.Decode(name, cookie.Value)
+}
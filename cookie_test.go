@@ -0,0 +1,132 @@
+package goji
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieCodec(t *testing.T) {
+	key1, key2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 16)
+	tests := []struct {
+		enc, dec *CookieCodec
+		name     string
+		err      error
+	}{
+		{NewCookieCodec(WithCookieSigning(key1)), NewCookieCodec(WithCookieSigning(key1)), "a", nil},
+		{NewCookieCodec(WithCookieSigning(key1)), NewCookieCodec(WithCookieSigning(key2, key1)), "a", nil},
+		{NewCookieCodec(WithCookieSigning(key1)), NewCookieCodec(WithCookieSigning(key2)), "a", ErrInvalidCookie},
+		{NewCookieCodec(WithCookieSigning(key1)), NewCookieCodec(WithCookieSigning(key1)), "b", ErrInvalidCookie},
+		{NewCookieCodec(WithCookieEncryption(key1)), NewCookieCodec(WithCookieEncryption(key1)), "a", nil},
+		{NewCookieCodec(WithCookieEncryption(key1)), NewCookieCodec(WithCookieEncryption(key2, key1)), "a", nil},
+		{NewCookieCodec(WithCookieEncryption(key1)), NewCookieCodec(WithCookieEncryption(key2)), "a", ErrInvalidCookie},
+		{NewCookieCodec(WithCookieEncryption(key1)), NewCookieCodec(WithCookieEncryption(key1)), "b", ErrInvalidCookie},
+		{
+			NewCookieCodec(WithCookieSigning(key1), WithCookieEncryption(key2)),
+			NewCookieCodec(WithCookieSigning(key1), WithCookieEncryption(key2)),
+			"a", nil,
+		},
+	}
+	for i, test := range tests {
+		value, err := test.enc.Encode("a", "hello; world")
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if len(test.enc.aeads) != 0 && strings.Contains(value, "hello") {
+			t.Errorf("test %d expected encrypted value, got: %q", i, value)
+		}
+		v, err := test.dec.Decode(test.name, value)
+		switch {
+		case !errors.Is(err, test.err):
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		case err == nil && v != "hello; world":
+			t.Errorf("test %d expected %q, got: %q", i, "hello; world", v)
+		}
+	}
+}
+
+func TestCookieCodecTampered(t *testing.T) {
+	codec := NewCookieCodec(WithCookieSigning([]byte("secret")))
+	value, _ := codec.Encode("a", "admin=false")
+	for i, v := range []string{"", "!!!", "AAAA", value[:len(value)-1] + "A", "x" + value[1:]} {
+		if _, err := codec.Decode("a", v); !errors.Is(err, ErrInvalidCookie) {
+			t.Errorf("test %d expected ErrInvalidCookie, got: %v", i, err)
+		}
+	}
+}
+
+func TestSetGetCookie(t *testing.T) {
+	codec := NewCookieCodec(WithCookieSigning([]byte("secret")), WithCookieEncryption(bytes.Repeat([]byte{1}, 32)))
+	res := httptest.NewRecorder()
+	cookie := &http.Cookie{Name: "session", Value: "carl", Path: "/"}
+	if err := SetCookie(res, cookie, codec); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cookie.Value != "carl" {
+		t.Errorf("expected cookie to be unmodified, got: %q", cookie.Value)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Cookie", res.Result().Cookies()[0].String())
+	if v, err := GetCookie(req, "session", codec); err != nil || v != "carl" {
+		t.Errorf("expected carl, got: %q, %v", v, err)
+	}
+	if _, err := GetCookie(req, "session", nil); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if _, err := GetCookie(req, "missing", codec); !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("expected http.ErrNoCookie, got: %v", err)
+	}
+	if err := SetCookie(res, &http.Cookie{Name: "big", Value: strings.Repeat("a", 5000)}, codec); !errors.Is(err, ErrCookieTooLarge) {
+		t.Errorf("expected ErrCookieTooLarge, got: %v", err)
+	}
+	if err := SetCookie(res, &http.Cookie{Name: "bad name"}, nil); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf("expected ErrInvalidCookie, got: %v", err)
+	}
+}
+
+func TestCookieCodecMaxAge(t *testing.T) {
+	now := time.Unix(time.Now().Unix(), 0)
+	codec := NewCookieCodec(WithCookieSigning([]byte("secret")), WithCookieMaxAge(time.Hour))
+	codec.now = func() time.Time { return now }
+	value, _ := codec.Encode("a", "carl")
+	tests := []struct {
+		d   time.Duration
+		err error
+	}{
+		{0, nil},
+		{time.Hour, nil},
+		{time.Hour + time.Second, ErrCookieExpired},
+	}
+	for i, test := range tests {
+		codec.now = func() time.Time { return now.Add(test.d) }
+		v, err := codec.Decode("a", value)
+		switch {
+		case !errors.Is(err, test.err):
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		case err == nil && v != "carl":
+			t.Errorf("test %d expected %q, got: %q", i, "carl", v)
+		}
+	}
+}
+
+func TestCookieCodecNoKeys(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	NewCookieCodec()
+}
+
+func TestCookieEncryptionInvalidKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	NewCookieCodec(WithCookieEncryption([]byte("short")))
+}
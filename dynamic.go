@@ -0,0 +1,189 @@
+package goji
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Dynamic is a mux option to use a copy-on-write router, allowing routes to
+// be safely registered concurrently with requests.
+//
+// Each registration builds a new immutable router snapshot that is atomically
+// swapped in, so routing never takes a lock and has no additional read-path
+// cost. Registration is comparatively expensive, as the router's tries are
+// copied for each added route.
+func Dynamic(m *Mux) {
+	r := new(dynamicRouter)
+	r.cur.Store(new(router))
+	m.router = r
+}
+
+// dynamicRouter is a copy-on-write router.
+type dynamicRouter struct {
+	mu   sync.Mutex
+	cur  atomic.Pointer[router]
+	next uint64
+}
+
+// Handle satisfies the Router interface.
+func (d *dynamicRouter) Handle(matcher Matcher, handler http.Handler) {
+	d.update(func(r *router) {
+		r.Handle(matcher, handler)
+	})
+}
+
+// handle adds the route, returning the route's id.
+func (d *dynamicRouter) handle(matcher Matcher, handler http.Handler) (id uint64) {
+	d.update(func(r *router) {
+		id = r.handle(matcher, handler)
+	})
+	return id
+}
+
+// remove removes the route with the id.
+func (d *dynamicRouter) remove(id uint64) (rt route, ok bool) {
+	d.update(func(r *router) {
+		rt, ok = r.remove(id)
+	})
+	return rt, ok
+}
+
+// replace replaces the matcher and handler of the route with the id.
+func (d *dynamicRouter) replace(id uint64, matcher Matcher, handler http.Handler) (rt route, ok bool) {
+	d.update(func(r *router) {
+		rt, ok = r.replace(id, matcher, handler)
+	})
+	return rt, ok
+}
+
+// Route satisfies the Router interface.
+func (d *dynamicRouter) Route(req *http.Request) *http.Request {
+	return d.cur.Load().Route(req)
+}
+
+// Allowed returns the methods of the routes matching the request's path.
+func (d *dynamicRouter) Allowed(req *http.Request) []string {
+	return d.cur.Load().Allowed(req)
+}
+
+// Compile compacts the current router snapshot.
+func (d *dynamicRouter) Compile() {
+	d.update(func(r *router) {
+		r.Compile()
+	})
+}
+
+// Stats returns statistics for the current router snapshot.
+func (d *dynamicRouter) Stats() RouterStats {
+	return d.cur.Load().Stats()
+}
+
+// list returns the routes of the current router snapshot.
+func (d *dynamicRouter) list() []route {
+	return d.cur.Load().list()
+}
+
+// update applies f to a copy of the current router snapshot, and swaps it
+// in.
+func (d *dynamicRouter) update(f func(*router)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r := d.cur.Load().copy()
+	f(r)
+	d.next++
+	r.version = d.next
+	d.cur.Store(r)
+}
+
+// RouteVersion is an immutable snapshot of a Mux's route table, including
+// the Mux's route names (see WithName).
+type RouteVersion struct {
+	m     *Mux
+	r     *router
+	names map[string]Matcher
+}
+
+// Version returns the snapshot's version, which is incremented for each
+// change to the route table. Returns 0 for the zero RouteVersion.
+func (v RouteVersion) Version() uint64 {
+	if v.r == nil {
+		return 0
+	}
+	return v.r.version
+}
+
+// Routes returns the number of routes in the snapshot.
+func (v RouteVersion) Routes() int {
+	if v.r == nil {
+		return 0
+	}
+	return len(v.r.routes)
+}
+
+// ErrNotDynamic is the not dynamic error.
+var ErrNotDynamic = errors.New("mux is not dynamic")
+
+// ErrInvalidRouteVersion is the invalid route version error.
+var ErrInvalidRouteVersion = errors.New("invalid route version")
+
+// Snapshot returns a snapshot of the Mux's current route table, that can
+// later be used to atomically roll back changes to the route table with
+// Restore. Snapshots are only available when the Mux was created with the
+// Dynamic option, otherwise the zero RouteVersion is returned.
+//
+// Snapshot is safe to call concurrently with requests and registration.
+func (m *Mux) Snapshot() RouteVersion {
+	d, ok := m.router.(*dynamicRouter)
+	if !ok {
+		return RouteVersion{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return RouteVersion{
+		m:     m,
+		r:     d.cur.Load(),
+		names: maps.Clone(m.names),
+	}
+}
+
+// Restore atomically restores the Mux's route table and route names to the
+// snapshot, for example to roll back a bad dynamic route change. Returns
+// ErrInvalidRouteVersion when the snapshot was not taken from the Mux.
+//
+// Restore is safe to call concurrently with requests and registration.
+func (m *Mux) Restore(v RouteVersion) error {
+	d, ok := m.router.(*dynamicRouter)
+	switch {
+	case !ok:
+		return ErrNotDynamic
+	case v.r == nil, v.m != m:
+		return ErrInvalidRouteVersion
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cur.Store(v.r)
+	m.names = maps.Clone(v.names)
+	return nil
+}
+
+// copy returns a deep copy of the router.
+func (r *router) copy() *router {
+	c := &router{
+		routes:   append([]route(nil), r.routes...),
+		wildcard: *r.wildcard.clone(),
+		version:  r.version,
+		next:     r.next,
+	}
+	if r.methods != nil {
+		c.methods = make(map[string]*trieNode, len(r.methods))
+		for method, tn := range r.methods {
+			c.methods[method] = tn.clone()
+		}
+	}
+	return c
+}
@@ -0,0 +1,173 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDynamic(t *testing.T) {
+	m := New(Dynamic)
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("index"))
+	})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			s := strconv.Itoa(i)
+			m.HandleFunc(Get("/"+s), func(res http.ResponseWriter, req *http.Request) {
+				res.Write([]byte(s))
+			})
+			if i == 50 {
+				m.Compile()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			res := httptest.NewRecorder()
+			m.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+			if body := res.Body.String(); body != "index" {
+				t.Errorf("expected index, got: %q", body)
+			}
+		}
+	}()
+	wg.Wait()
+	for i := 0; i < 100; i++ {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", "/"+strconv.Itoa(i), nil))
+		if body := res.Body.String(); body != strconv.Itoa(i) {
+			t.Errorf("expected %d, got: %q", i, body)
+		}
+	}
+	if stats := m.Stats(); stats.Routes != 101 {
+		t.Errorf("expected 101 routes, got: %d", stats.Routes)
+	}
+}
+
+func TestRouterCopy(t *testing.T) {
+	r := new(router)
+	r.Handle(Get("/a"), intHandler(0))
+	c := r.copy()
+	c.Handle(Post("/b"), intHandler(1))
+	if n, exp := len(r.routes), 1; n != exp {
+		t.Errorf("expected %d routes, got: %d", exp, n)
+	}
+	if _, ok := r.methods["POST"]; ok {
+		t.Error("expected original router to be unmodified")
+	}
+	if h := c.Route(reqPath("POST", "/b")).Context().Value(handlerKey); h != intHandler(1) {
+		t.Errorf("expected %v, got: %v", intHandler(1), h)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	m := New(Dynamic)
+	m.Handle(Get("/a"), intHandler(1))
+	v1 := m.Snapshot()
+	m.Handle(Get("/b"), intHandler(2))
+	v2 := m.Snapshot()
+	if v1.Version() != 1 || v2.Version() != 2 || v1.Routes() != 1 || v2.Routes() != 2 {
+		t.Errorf("expected versions 1 and 2, got: %d (%d routes) %d (%d routes)", v1.Version(), v1.Routes(), v2.Version(), v2.Routes())
+	}
+	route := func(path string) interface{} {
+		return m.router.Route(reqPath("GET", path)).Context().Value(handlerKey)
+	}
+	if err := m.Restore(v1); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if h := route("/b"); h != nil {
+		t.Errorf("expected no handler after rollback, got: %v", h)
+	}
+	if h := route("/a"); h != intHandler(1) {
+		t.Errorf("expected %v, got: %v", intHandler(1), h)
+	}
+	m.Handle(Get("/c"), intHandler(3))
+	if v := m.Snapshot(); v.Version() != 3 || v.Routes() != 2 {
+		t.Errorf("expected version 3 with 2 routes, got: %d %d", v.Version(), v.Routes())
+	}
+	if err := m.Restore(v2); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if h := route("/b"); h != intHandler(2) {
+		t.Errorf("expected %v, got: %v", intHandler(2), h)
+	}
+
+	if err := New(Dynamic).Restore(v1); err != ErrInvalidRouteVersion {
+		t.Errorf("expected %v, got: %v", ErrInvalidRouteVersion, err)
+	}
+	if err := m.Restore(RouteVersion{}); err != ErrInvalidRouteVersion {
+		t.Errorf("expected %v, got: %v", ErrInvalidRouteVersion, err)
+	}
+	if v := New().Snapshot(); v.Version() != 0 {
+		t.Errorf("expected version 0, got: %d", v.Version())
+	}
+	if err := New().Restore(v1); err != ErrNotDynamic {
+		t.Errorf("expected %v, got: %v", ErrNotDynamic, err)
+	}
+}
+
+func TestSnapshotRestoreNames(t *testing.T) {
+	m := New(Dynamic)
+	m.Handle(Get("/a", WithName("a")), intHandler(1))
+	v := m.Snapshot()
+	m.Handle(Get("/b", WithName("b")), intHandler(2))
+	if _, err := m.URL("b"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := m.Restore(v); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := m.URL("b"); err != ErrUnknownRoute {
+		t.Errorf("expected %v, got: %v", ErrUnknownRoute, err)
+	}
+	if path, err := m.URL("a"); err != nil || path != "/a" {
+		t.Errorf("expected /a, got: %q %v", path, err)
+	}
+}
+
+func TestDynamicNames(t *testing.T) {
+	var n atomic.Int64
+	m := New(Dynamic, WithEvents(func(ev Event) {
+		if ev.Type == RouteAdded {
+			n.Add(1)
+		}
+	}))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				s := strconv.Itoa(i*25 + j)
+				m.HandleNamed("r"+s, Get("/"+s), intHandler(i))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				m.URL("r" + strconv.Itoa(j))
+				m.Snapshot()
+				m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/"+strconv.Itoa(j), nil))
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 100; i++ {
+		if path, err := m.URL("r" + strconv.Itoa(i)); err != nil || path != "/"+strconv.Itoa(i) {
+			t.Errorf("test %d expected /%d, got: %q %v", i, i, path, err)
+		}
+	}
+	if v := m.Snapshot(); v.Routes() != 100 || len(v.names) != 100 {
+		t.Errorf("expected 100 routes and names, got: %d %d", v.Routes(), len(v.names))
+	}
+	if n := n.Load(); n != 100 {
+		t.Errorf("expected 100 events, got: %d", n)
+	}
+}
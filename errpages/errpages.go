@@ -0,0 +1,347 @@
+// Package errpages provides templated HTML error pages for use with
+// goji.Mux.
+//
+// Templates are registered per status code, with a minimal default template
+// used for statuses without a registered template. Pages are only rendered
+// for clients accepting HTML, other clients are sent a plain text error:
+//
+//	errpages.Parse(http.StatusNotFound, `<h1>{{.Title}}</h1><p>{{.Message}}</p>`)
+//	mux := goji.New(errpages.MuxOption())
+//	mux.Use(errpages.Recover)
+//
+// Messages are localized using a message catalog keyed by language, resolved
+// from the request context (see WithLanguage) or the Accept-Language header:
+//
+//	errpages.Messages("fr", map[int]string{
+//		http.StatusNotFound: "La page demandée est introuvable.",
+//	})
+package errpages
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kenshaw/goji"
+)
+
+// Data is the data passed to error page templates.
+type Data struct {
+	// Status is the response status code.
+	Status int
+	// Title is the status text (for example, "Not Found").
+	Title string
+	// Message is a human readable, localized message for the status.
+	Message string
+	// Lang is the language of the message.
+	Lang string
+	// Request is the request.
+	Request *http.Request
+}
+
+// defaultTemplate is the default error page template.
+var defaultTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Status}} {{.Title}}</title></head>
+<body>
+<h1>{{.Status}} {{.Title}}</h1>
+<p>{{.Message}}</p>
+</body>
+</html>
+`))
+
+// DefaultLanguage is the default message catalog language.
+const DefaultLanguage = "en"
+
+// messages are the default messages for common statuses.
+var messages = map[int]string{
+	http.StatusNotFound:            "The requested page could not be found.",
+	http.StatusMethodNotAllowed:    "The requested method is not allowed for this page.",
+	http.StatusInternalServerError: "An unexpected error occurred.",
+}
+
+// Pages is a set of error page templates.
+type Pages struct {
+	mu        sync.RWMutex
+	templates map[int]*template.Template
+	catalog   map[string]map[int]string
+}
+
+// New creates a new set of error pages.
+func New() *Pages {
+	p := &Pages{
+		templates: make(map[int]*template.Template),
+		catalog:   make(map[string]map[int]string),
+	}
+	p.Messages(DefaultLanguage, messages)
+	return p
+}
+
+// Messages adds the messages for the language (for example, "fr" or
+// "pt-BR") to the message catalog.
+func (p *Pages) Messages(lang string, msgs map[int]string) {
+	lang = strings.ToLower(lang)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.catalog[lang] == nil {
+		p.catalog[lang] = make(map[int]string, len(msgs))
+	}
+	for status, msg := range msgs {
+		p.catalog[lang][status] = msg
+	}
+}
+
+// Language returns the catalog language for the request, resolved from the
+// request context (see WithLanguage) or the Accept-Language header, falling
+// back to the DefaultLanguage.
+func (p *Pages) Language(req *http.Request) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if lang, ok := req.Context().Value(languageKey).(string); ok {
+		if lang = p.match(lang); lang != "" {
+			return lang
+		}
+	}
+	for _, lang := range acceptLanguages(req) {
+		if lang = p.match(lang); lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// match returns the catalog language matching the language tag, or its base
+// language.
+func (p *Pages) match(lang string) string {
+	lang = strings.ToLower(lang)
+	if _, ok := p.catalog[lang]; ok {
+		return lang
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if _, ok := p.catalog[base]; ok {
+			return base
+		}
+	}
+	return ""
+}
+
+// Message returns the localized message for the status, falling back to the
+// DefaultLanguage message and the status text.
+func (p *Pages) Message(lang string, status int) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if msg, ok := p.catalog[strings.ToLower(lang)][status]; ok {
+		return msg
+	}
+	if msg, ok := p.catalog[DefaultLanguage][status]; ok {
+		return msg
+	}
+	return http.StatusText(status)
+}
+
+// Register registers the template for the status.
+func (p *Pages) Register(status int, tmpl *template.Template) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.templates[status] = tmpl
+}
+
+// Parse parses and registers the template text for the status.
+func (p *Pages) Parse(status int, text string) error {
+	tmpl, err := template.New(http.StatusText(status)).Parse(text)
+	if err != nil {
+		return err
+	}
+	p.Register(status, tmpl)
+	return nil
+}
+
+// Error writes the error page for the status to the response when the
+// client accepts HTML, and the plain text localized message otherwise.
+func (p *Pages) Error(res http.ResponseWriter, req *http.Request, status int) {
+	data := p.data(req, status)
+	res.Header().Set("Content-Language", data.Lang)
+	if !AcceptsHTML(req) {
+		http.Error(res, data.Message, status)
+		return
+	}
+	p.mu.RLock()
+	tmpl, ok := p.templates[status]
+	p.mu.RUnlock()
+	if !ok {
+		tmpl = defaultTemplate
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		slog.Error("unable to render error page", "status", status, "error", err)
+		http.Error(res, data.Message, status)
+		return
+	}
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	res.WriteHeader(status)
+	res.Write(buf.Bytes())
+}
+
+// data returns the template data for the request and status.
+func (p *Pages) data(req *http.Request, status int) Data {
+	lang := p.Language(req)
+	return Data{
+		Status:  status,
+		Title:   http.StatusText(status),
+		Message: p.Message(lang, status),
+		Lang:    lang,
+		Request: req,
+	}
+}
+
+// Handler returns a handler that writes the error page for the status, for
+// use as a Mux's not found handler (see goji.NotFound).
+func (p *Pages) Handler(status int) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		p.Error(res, req, status)
+	})
+}
+
+// MuxOption returns a mux option that sets the Mux's not found and method
+// not allowed handlers (see goji.NotFound and goji.MethodNotAllowed) to the
+// 404 Not Found and 405 Method Not Allowed error pages, so that requests not
+// routed by the Mux are sent the error pages.
+func (p *Pages) MuxOption() goji.MuxOption {
+	return func(m *goji.Mux) {
+		goji.NotFound(p.Handler(http.StatusNotFound))(m)
+		goji.MethodNotAllowed(p.Handler(http.StatusMethodNotAllowed))(m)
+	}
+}
+
+// Recover is a middleware that recovers from panics in downstream handlers,
+// logging the panic and writing the 500 Internal Server Error page.
+//
+// Panics with http.ErrAbortHandler are not recovered.
+func (p *Pages) Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				slog.Error("panic serving request", "method", req.Method, "path", goji.RedactedPath(req), "panic", v)
+				p.Error(res, req, http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(res, req)
+	})
+}
+
+// AcceptsHTML returns whether or not the request's Accept header includes
+// HTML.
+func AcceptsHTML(req *http.Request) bool {
+	for _, v := range req.Header.Values("Accept") {
+		for _, s := range strings.Split(v, ",") {
+			s, _, _ = strings.Cut(s, ";")
+			if s = strings.TrimSpace(s); s == "text/html" || s == "application/xhtml+xml" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptLanguages returns the languages in the request's Accept-Language
+// header, ordered by preference.
+func acceptLanguages(req *http.Request) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, v := range req.Header.Values("Accept-Language") {
+		for _, s := range strings.Split(v, ",") {
+			tag, params, _ := strings.Cut(s, ";")
+			if tag = strings.TrimSpace(tag); tag == "" || tag == "*" {
+				continue
+			}
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				var err error
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if q > 0 {
+				langs = append(langs, lang{tag, q})
+			}
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+// contextKey is the context key type.
+type contextKey int
+
+// languageKey is the context key used for the request language.
+const languageKey contextKey = 0
+
+// WithLanguage returns a child context with the passed language, taking
+// precedence over the Accept-Language header. Used, for example, by locale
+// routing to select the language from the request's path.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey, lang)
+}
+
+// Default is the default set of error pages.
+var Default = New()
+
+// Register registers the template for the status with the default error
+// pages.
+func Register(status int, tmpl *template.Template) {
+	Default.Register(status, tmpl)
+}
+
+// Parse parses and registers the template text for the status with the
+// default error pages.
+func Parse(status int, text string) error {
+	return Default.Parse(status, text)
+}
+
+// Messages adds the messages for the language to the default error pages
+// message catalog.
+func Messages(lang string, msgs map[int]string) {
+	Default.Messages(lang, msgs)
+}
+
+// Error writes the default error page for the status to the response.
+func Error(res http.ResponseWriter, req *http.Request, status int) {
+	Default.Error(res, req, status)
+}
+
+// Handler returns a handler that writes the default error page for the
+// status.
+func Handler(status int) http.Handler {
+	return Default.Handler(status)
+}
+
+// MuxOption returns a mux option that sets the Mux's not found and method
+// not allowed handlers to the default error pages.
+func MuxOption() goji.MuxOption {
+	return Default.MuxOption()
+}
+
+// Recover is a middleware that recovers from panics in downstream handlers,
+// writing the default 500 Internal Server Error page.
+func Recover(next http.Handler) http.Handler {
+	return Default.Recover(next)
+}
@@ -0,0 +1,153 @@
+package errpages
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestPages(t *testing.T) {
+	p := New()
+	if err := p.Parse(http.StatusNotFound, `<p>missing {{.Request.URL.Path}}</p>`); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := p.Parse(http.StatusForbidden, `{{.Missing}`); err == nil {
+		t.Errorf("expected error, got: nil")
+	}
+	tests := []struct {
+		status int
+		accept string
+		ctype  string
+		exp    string
+	}{
+		{http.StatusNotFound, "text/html,application/xhtml+xml;q=0.9", "text/html; charset=utf-8", "<p>missing /a&lt;b&gt;</p>"},
+		{http.StatusNotFound, "application/json", "text/plain; charset=utf-8", "The requested page could not be found.\n"},
+		{http.StatusNotFound, "", "text/plain; charset=utf-8", "The requested page could not be found.\n"},
+		{http.StatusMethodNotAllowed, "text/html", "text/html; charset=utf-8", "<h1>405 Method Not Allowed</h1>"},
+		{http.StatusInternalServerError, "application/xhtml+xml", "text/html; charset=utf-8", "An unexpected error occurred."},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/a%3Cb%3E", nil)
+		req.Header.Set("Accept", test.accept)
+		res := httptest.NewRecorder()
+		p.Handler(test.status).ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Header().Get("Content-Type"); s != test.ctype {
+			t.Errorf("test %d expected %q, got: %q", i, test.ctype, s)
+		}
+		if s := res.Body.String(); !strings.Contains(s, test.exp) {
+			t.Errorf("test %d expected body to contain %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestMuxOption(t *testing.T) {
+	p := New()
+	if err := p.Parse(http.StatusNotFound, `<p>missing {{.Request.URL.Path}}</p>`); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := goji.New(p.MuxOption())
+	m.HandleFunc(goji.Get("/hello"), func(http.ResponseWriter, *http.Request) {})
+	tests := []struct {
+		method string
+		path   string
+		status int
+		exp    string
+	}{
+		{"GET", "/nope", http.StatusNotFound, "<p>missing /nope</p>"},
+		{"POST", "/hello", http.StatusMethodNotAllowed, "<h1>405 Method Not Allowed</h1>"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		req.Header.Set("Accept", "text/html")
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); !strings.Contains(s, test.exp) {
+			t.Errorf("test %d expected body to contain %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	p := New()
+	m := goji.New(goji.NotFound(p.Handler(http.StatusNotFound)))
+	m.Use(p.Recover)
+	m.HandleFunc(goji.Get("/panic"), func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/panic", http.StatusInternalServerError},
+		{"/missing", http.StatusNotFound},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("Accept", "text/html")
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Header().Get("Content-Type"); !strings.HasPrefix(s, "text/html") {
+			t.Errorf("test %d expected html, got: %q", i, s)
+		}
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	p := New()
+	p.Messages("fr", map[int]string{http.StatusNotFound: "Page introuvable."})
+	p.Messages("pt-BR", map[int]string{http.StatusNotFound: "Página não encontrada."})
+	tests := []struct {
+		accept string
+		ctx    string
+		lang   string
+		exp    string
+	}{
+		{"", "", "en", "The requested page could not be found.\n"},
+		{"fr", "", "fr", "Page introuvable.\n"},
+		{"fr-CA, en;q=0.8", "", "fr", "Page introuvable.\n"},
+		{"de, en;q=0.5, fr;q=0.7", "", "fr", "Page introuvable.\n"},
+		{"pt-br", "", "pt-br", "Página não encontrada.\n"},
+		{"pt", "", "en", "The requested page could not be found.\n"},
+		{"fr;q=0, de", "", "en", "The requested page could not be found.\n"},
+		{"en", "fr", "fr", "Page introuvable.\n"},
+		{"fr", "de", "fr", "Page introuvable.\n"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if test.accept != "" {
+			req.Header.Set("Accept-Language", test.accept)
+		}
+		if test.ctx != "" {
+			req = req.WithContext(WithLanguage(req.Context(), test.ctx))
+		}
+		if lang := p.Language(req); lang != test.lang {
+			t.Errorf("test %d expected %q, got: %q", i, test.lang, lang)
+		}
+		res := httptest.NewRecorder()
+		p.Error(res, req, http.StatusNotFound)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+		if s := res.Header().Get("Content-Language"); s != test.lang {
+			t.Errorf("test %d expected Content-Language %q, got: %q", i, test.lang, s)
+		}
+	}
+	if s := p.Message("fr", http.StatusInternalServerError); s != "An unexpected error occurred." {
+		t.Errorf("expected fallback message, got: %q", s)
+	}
+	if s := p.Message("fr", http.StatusTeapot); s != "I'm a teapot" {
+		t.Errorf("expected status text, got: %q", s)
+	}
+}
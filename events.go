@@ -0,0 +1,95 @@
+package goji
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// EventType is a router event type.
+type EventType int
+
+// Event types.
+const (
+	// RouteAdded is the event type for a route added to the Mux.
+	RouteAdded EventType = iota
+	// RouteRemoved is the event type for a route removed from the Mux.
+	RouteRemoved
+	// RequestRouted is the event type for a request matched to a route.
+	RequestRouted
+	// RequestUnmatched is the event type for a request that did not match
+	// any route.
+	RequestUnmatched
+)
+
+// String satisfies the fmt.Stringer interface.
+func (typ EventType) String() string {
+	switch typ {
+	case RouteAdded:
+		return "RouteAdded"
+	case RouteRemoved:
+		return "RouteRemoved"
+	case RequestRouted:
+		return "RequestRouted"
+	case RequestUnmatched:
+		return "RequestUnmatched"
+	}
+	return "EventType(" + strconv.Itoa(int(typ)) + ")"
+}
+
+// Event is a router event, emitted to observability tooling (dashboards,
+// route change auditing, ...) in long-running processes.
+type Event struct {
+	Type EventType
+	Time time.Time
+	// Matcher is the added or removed route's Matcher, or the matched Matcher
+	// of a routed request.
+	Matcher Matcher
+	// Handler is the added or removed route's http.Handler, or the matched
+	// http.Handler of a routed request.
+	Handler http.Handler
+	// Request is the request for RequestRouted and RequestUnmatched events.
+	Request *http.Request
+}
+
+// WithEvents is a mux option to add a router event callback. Callbacks are
+// invoked synchronously, and should not block. Callbacks are invoked after
+// the route table has been updated, and may be invoked concurrently when
+// routes are registered concurrently (see Dynamic).
+func WithEvents(f func(Event)) MuxOption {
+	return func(m *Mux) {
+		if len(m.events) == 0 {
+			m.OnRouted(func(req *http.Request, matcher Matcher) {
+				ev := Event{
+					Type:    RequestUnmatched,
+					Time:    time.Now(),
+					Request: req,
+				}
+				if matcher != nil {
+					ev.Type, ev.Matcher = RequestRouted, matcher
+					ev.Handler, _ = req.Context().Value(handlerKey).(http.Handler)
+				}
+				m.emit(ev)
+			})
+		}
+		m.events = append(m.events, f)
+	}
+}
+
+// WithEventChan is a mux option to send router events to the channel. Events
+// are dropped when the channel is not ready to receive.
+func WithEventChan(ch chan<- Event) MuxOption {
+	return WithEvents(func(ev Event) {
+		select {
+		case ch <- ev:
+		default:
+		}
+	})
+}
+
+// emit emits the event to the mux's event callbacks.
+func (m *Mux) emit(ev Event) {
+	for _, f := range m.events {
+		f(ev)
+	}
+}
@@ -0,0 +1,64 @@
+package goji
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	ch := make(chan Event, 10)
+	var types []EventType
+	m := New(WithEventChan(ch), WithEvents(func(ev Event) {
+		types = append(types, ev.Type)
+	}))
+	p, h := Get("/hello"), intHandler(1)
+	m.Handle(p, h)
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/hello", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nope", nil))
+
+	tests := []struct {
+		typ     EventType
+		matcher Matcher
+		path    string
+	}{
+		{RouteAdded, p, ""},
+		{RequestRouted, p, "/hello"},
+		{RequestUnmatched, nil, "/nope"},
+	}
+	for i, test := range tests {
+		ev := <-ch
+		if ev.Type != test.typ || ev.Matcher != test.matcher || ev.Time.IsZero() {
+			t.Errorf("test %d expected %v %v, got: %v %v", i, test.typ, test.matcher, ev.Type, ev.Matcher)
+		}
+		if test.matcher != nil && ev.Handler != h {
+			t.Errorf("test %d expected handler %v, got: %v", i, h, ev.Handler)
+		}
+		if test.path != "" && ev.Request.URL.Path != test.path {
+			t.Errorf("test %d expected path %q, got: %q", i, test.path, ev.Request.URL.Path)
+		}
+	}
+	if len(types) != 3 {
+		t.Errorf("expected 3 events, got: %v", types)
+	}
+
+	// full channel drops events
+	ch = make(chan Event, 1)
+	m = New(WithEventChan(ch))
+	m.Handle(p, h)
+	m.Handle(Get("/world"), h)
+	if n := len(ch); n != 1 {
+		t.Fatalf("expected 1 event, got: %d", n)
+	}
+	if ev := <-ch; ev.Type != RouteAdded || ev.Matcher != p {
+		t.Errorf("expected %v %v, got: %v %v", RouteAdded, p, ev.Type, ev.Matcher)
+	}
+}
+
+func TestEventTypeString(t *testing.T) {
+	if s := RequestUnmatched.String(); s != "RequestUnmatched" {
+		t.Errorf("expected RequestUnmatched, got: %q", s)
+	}
+	if s := EventType(42).String(); s != "EventType(42)" {
+		t.Errorf("expected EventType(42), got: %q", s)
+	}
+}
@@ -0,0 +1,54 @@
+package goji
+
+import (
+	"expvar"
+	"net/http"
+	"time"
+)
+
+// PublishExpvar publishes the Mux's router statistics under the "goji"
+// expvar, for environments that already scrape /debug/vars. See
+// PublishExpvarAs.
+func PublishExpvar(m *Mux) *expvar.Map {
+	return PublishExpvarAs("goji", m)
+}
+
+// PublishExpvarAs publishes the Mux's router statistics under the named
+// expvar, returning the published map. The following values are published:
+//
+//	routes              number of registered routes
+//	trie_nodes          number of nodes in the router's tries
+//	hits                per-route hit counters, keyed by method and route
+//	                    (for example, "GET /users/:id")
+//	not_found           number of unmatched requests
+//	method_not_allowed  number of 405 Method Not Allowed responses
+//
+// Like expvar.Publish, PublishExpvarAs panics if the name is already
+// registered. It is not safe to publish concurrently with requests.
+func PublishExpvarAs(name string, m *Mux) *expvar.Map {
+	v := new(expvar.Map).Init()
+	hits, notFound, notAllowed := new(expvar.Map).Init(), new(expvar.Int), new(expvar.Int)
+	v.Set("routes", expvar.Func(func() interface{} {
+		return m.Stats().Routes
+	}))
+	v.Set("trie_nodes", expvar.Func(func() interface{} {
+		return m.Stats().Nodes
+	}))
+	v.Set("hits", hits)
+	v.Set("not_found", notFound)
+	v.Set("method_not_allowed", notAllowed)
+	m.OnRouted(func(req *http.Request, matcher Matcher) {
+		if matcher == nil {
+			notFound.Add(1)
+			return
+		}
+		hits.Add(req.Method+" "+RouteTemplate(req), 1)
+	})
+	m.OnResponse(func(req *http.Request, status int, _ time.Duration) {
+		if status == http.StatusMethodNotAllowed {
+			notAllowed.Add(1)
+		}
+	})
+	expvar.Publish(name, v)
+	return v
+}
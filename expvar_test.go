@@ -0,0 +1,44 @@
+package goji
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/hello"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Post("/hello"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Get("/world"), func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	})
+	v := PublishExpvarAs("goji_test", m)
+	for _, req := range []string{"GET /hello", "GET /hello", "POST /hello", "GET /world", "GET /nope"} {
+		method, path, _ := strings.Cut(req, " ")
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+	}
+	var stats struct {
+		Routes           int            `json:"routes"`
+		TrieNodes        int            `json:"trie_nodes"`
+		Hits             map[string]int `json:"hits"`
+		NotFound         int            `json:"not_found"`
+		MethodNotAllowed int            `json:"method_not_allowed"`
+	}
+	if err := json.Unmarshal([]byte(v.String()), &stats); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if stats.Routes != 3 || stats.NotFound != 1 || stats.MethodNotAllowed != 1 {
+		t.Errorf("expected routes=3 not_found=1 method_not_allowed=1, got: %+v", stats)
+	}
+	if stats.Hits["GET /hello"] != 2 || stats.Hits["POST /hello"] != 1 || stats.Hits["GET /world"] != 1 {
+		t.Errorf("expected GET /hello=2 POST /hello=1 GET /world=1, got: %v", stats.Hits)
+	}
+	// wildcard root, the GET and HEAD roots each with "/", "hello" and
+	// "world" children, plus the POST root with a "/hello" child
+	if stats.TrieNodes != 11 {
+		t.Errorf("expected 11 trie nodes, got: %d", stats.TrieNodes)
+	}
+}
@@ -0,0 +1,137 @@
+package goji
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// FlashCookie is the name of the cookie used for flash messages.
+const FlashCookie = "flash"
+
+// flashKey is the request-scoped value store key for flash messages.
+type flashKey struct{}
+
+// flash holds the flash messages for a request.
+type flash struct {
+	mu   sync.Mutex
+	in   []string
+	read bool
+	out  []string
+}
+
+// WithFlash is a mux option to enable flash messages, for server-rendered
+// form flows. Messages added with Flash are sent to the client in a cookie
+// encoded by the codec, and are available via Flashes on the client's next
+// request, after which they are cleared. When the codec is nil, messages are
+// not signed or encrypted, and are only base64 encoded.
+//
+// For example:
+//
+//	mux := goji.New(goji.WithFlash(codec))
+//	mux.Post("/settings", func(res http.ResponseWriter, req *http.Request) {
+//		// ...
+//		goji.Flash(req.Context(), "Settings saved.")
+//		http.Redirect(res, req, "/settings", http.StatusSeeOther)
+//	})
+//	mux.Get("/settings", func(res http.ResponseWriter, req *http.Request) {
+//		messages := goji.Flashes(req.Context())
+//		// ...
+//	})
+//
+// The flash middleware is added before any middleware added with Use.
+func WithFlash(codec *CookieCodec) MuxOption {
+	return func(m *Mux) {
+		m.middleware = append(m.middleware, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				f := new(flash)
+				if v, err := GetCookie(req, FlashCookie, codec); err == nil {
+					f.in = decodeFlashes(v, codec)
+				}
+				Set(req.Context(), flashKey{}, f)
+				w := &HeaderWriter{
+					ResponseWriter: res,
+					Func: func(http.Header, int) {
+						f.write(res, codec)
+					},
+				}
+				next.ServeHTTP(w, req)
+				// handlers that do not write a response
+				w.before(http.StatusOK)
+			})
+		})
+	}
+}
+
+// write writes the flash cookie to the response, when the messages have
+// changed.
+func (f *flash) write(res http.ResponseWriter, codec *CookieCodec) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cookie := &http.Cookie{
+		Name:     FlashCookie,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	switch {
+	case len(f.out) != 0:
+		cookie.Value = encodeFlashes(f.out, codec)
+	case len(f.in) != 0 && f.read:
+		cookie.MaxAge = -1
+	default:
+		return
+	}
+	_ = SetCookie(res, cookie, codec)
+}
+
+// encodeFlashes encodes the messages as a flash cookie value, base64
+// encoding the value when the cookie is not encoded by a codec.
+func encodeFlashes(messages []string, codec *CookieCodec) string {
+	buf, _ := json.Marshal(messages)
+	if codec == nil {
+		return base64.RawURLEncoding.EncodeToString(buf)
+	}
+	return string(buf)
+}
+
+// decodeFlashes decodes the messages from a flash cookie value (see
+// encodeFlashes).
+func decodeFlashes(value string, codec *CookieCodec) []string {
+	buf := []byte(value)
+	if codec == nil {
+		var err error
+		if buf, err = base64.RawURLEncoding.DecodeString(value); err != nil {
+			return nil
+		}
+	}
+	var messages []string
+	_ = json.Unmarshal(buf, &messages)
+	return messages
+}
+
+// Flash adds a flash message, sent to the client and available via Flashes
+// on the client's next request (see WithFlash). Flash is a no-op when flash
+// messages are not enabled.
+func Flash(ctx context.Context, message string) {
+	if f, ok := Value[*flash](ctx, flashKey{}); ok {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.out = append(f.out, message)
+	}
+}
+
+// Flashes returns the flash messages added by the client's previous request,
+// clearing them (see WithFlash).
+func Flashes(ctx context.Context) []string {
+	f, ok := Value[*flash](ctx, flashKey{})
+	if !ok {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.read = true
+	return f.in
+}
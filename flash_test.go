@@ -0,0 +1,98 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlash(t *testing.T) {
+	codec := NewCookieCodec(WithCookieSigning([]byte("secret")))
+	m := New(WithFlash(codec))
+	var got []string
+	m.Post("/save", func(res http.ResponseWriter, req *http.Request) {
+		Flash(req.Context(), "saved")
+		Flash(req.Context(), "again")
+		http.Redirect(res, req, "/", http.StatusSeeOther)
+	})
+	m.Get("/", func(res http.ResponseWriter, req *http.Request) {
+		got = Flashes(req.Context())
+	})
+	m.Get("/other", func(http.ResponseWriter, *http.Request) {})
+
+	// set
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("POST", "/save", nil))
+	cookies := res.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != FlashCookie {
+		t.Fatalf("expected flash cookie, got: %v", cookies)
+	}
+	cookie := cookies[0]
+
+	// not read
+	req := httptest.NewRequest("GET", "/other", nil)
+	req.AddCookie(cookie)
+	res = httptest.NewRecorder()
+	m.ServeHTTP(res, req)
+	if cookies := res.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("expected no cookies, got: %v", cookies)
+	}
+
+	// read and cleared
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(cookie)
+	res = httptest.NewRecorder()
+	m.ServeHTTP(res, req)
+	if exp := []string{"saved", "again"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got: %v", exp, got)
+	}
+	if cookies := res.Result().Cookies(); len(cookies) != 1 || cookies[0].MaxAge != -1 {
+		t.Errorf("expected cleared cookie, got: %v", cookies)
+	}
+
+	// tampered
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: FlashCookie, Value: strings.ToUpper(cookie.Value)})
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	if got != nil {
+		t.Errorf("expected no messages, got: %v", got)
+	}
+}
+
+func TestFlashNoCodec(t *testing.T) {
+	m := New(WithFlash(nil))
+	var got []string
+	m.Post("/save", func(res http.ResponseWriter, req *http.Request) {
+		Flash(req.Context(), "saved, again")
+	})
+	m.Get("/", func(res http.ResponseWriter, req *http.Request) {
+		got = Flashes(req.Context())
+	})
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("POST", "/save", nil))
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range res.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), req)
+	if exp := []string{"saved, again"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got: %v", exp, got)
+	}
+}
+
+func TestFlashDisabled(t *testing.T) {
+	m := New()
+	m.Get("/", func(res http.ResponseWriter, req *http.Request) {
+		Flash(req.Context(), "ignored")
+		if messages := Flashes(req.Context()); messages != nil {
+			t.Errorf("expected no messages, got: %v", messages)
+		}
+	})
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if cookies := res.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("expected no cookies, got: %v", cookies)
+	}
+}
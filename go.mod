@@ -1,3 +1,7 @@
 module github.com/kenshaw/goji
 
-go 1.13
+go 1.24.0
+
+require golang.org/x/net v0.50.0
+
+require golang.org/x/text v0.34.0 // indirect
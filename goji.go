@@ -4,7 +4,11 @@ package goji
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 // contextKey is a the context key type.
@@ -19,6 +23,22 @@ const (
 
 	// pathKey is the context key used for path prefixes.
 	pathKey
+
+	// forwardedPrefixKey is the context key used for the forwarded prefix.
+	forwardedPrefixKey
+
+	// muxKey is the context key used for the root Mux.
+	muxKey
+
+	// storeKey is the context key used for the request-scoped value store.
+	storeKey
+
+	// principalKey is the context key used for the authenticated principal.
+	principalKey
+
+	// dispatchKey is the context key used for the dispatch state of the Mux
+	// serving the request.
+	dispatchKey
 )
 
 // nameKey is the context key type for names of variables extracted from URLs.
@@ -39,6 +59,240 @@ func WithPath(ctx context.Context, path string) context.Context {
 	return context.WithValue(ctx, pathKey, path)
 }
 
+// WithForwardedPrefix returns a child context with the passed forwarded
+// prefix.
+func WithForwardedPrefix(ctx context.Context, prefix string) context.Context {
+	return context.WithValue(ctx, forwardedPrefixKey, cleanPrefix(prefix))
+}
+
+// Matched returns the Matcher that was matched for the request, or nil when
+// the request has not been routed or no route matched.
+func Matched(req *http.Request) Matcher {
+	m, ok := req.Context().Value(matcherKey).(Matcher)
+	if !ok {
+		return nil
+	}
+	if u, ok := m.(interface{ Unwrap() Matcher }); ok {
+		return u.Unwrap()
+	}
+	return m
+}
+
+// Meta returns the route metadata value for key from the matched Matcher, or
+// nil when the matched Matcher does not carry metadata (see WithMeta). The
+// metadata of Matchers wrapped or combined by the matched Matcher (see And
+// and Weighted) is included, but only that of the matching Matcher of Or.
+func Meta(req *http.Request, key string) interface{} {
+	for _, m := range routeTree(req) {
+		if m, ok := m.(interface {
+			Meta(string) interface{}
+		}); ok {
+			if v := m.Meta(key); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// routeMatcher returns the Matcher of the matched route for the request, as
+// registered with the router.
+func routeMatcher(req *http.Request) Matcher {
+	m, _ := req.Context().Value(matcherKey).(Matcher)
+	return m
+}
+
+// matcherTree returns the matcher followed by the Matchers it wraps (see
+// Unwrap) or combines (see And, Or, and Host), depth first. The Matcher
+// negated by Not is never included.
+func matcherTree(m Matcher) []Matcher {
+	return walkMatchers(m, nil)
+}
+
+// routeTree returns the matcher tree (see matcherTree) of the matched route
+// for the request, including only the matching Matcher of Or.
+func routeTree(req *http.Request) []Matcher {
+	return walkMatchers(routeMatcher(req), req)
+}
+
+// walkMatchers returns the matcher followed by the Matchers it wraps or
+// combines, depth first. When req is not nil, only the matching Matcher of
+// Or (see orKey) is included.
+func walkMatchers(m Matcher, req *http.Request) []Matcher {
+	var tree []Matcher
+	var walk func(Matcher)
+	walk = func(m Matcher) {
+		if m == nil {
+			return
+		}
+		tree = append(tree, m)
+		if u, ok := m.(interface{ Unwrap() Matcher }); ok {
+			walk(u.Unwrap())
+		}
+		switch v := m.(type) {
+		case or:
+			if req == nil {
+				for _, m := range v {
+					walk(m)
+				}
+			} else if i, ok := req.Context().Value(orKey{v.key()}).(int); ok {
+				walk(v[i])
+			}
+		case interface{ matchers() []Matcher }:
+			for _, m := range v.matchers() {
+				walk(m)
+			}
+		}
+	}
+	walk(m)
+	return tree
+}
+
+// policies returns the values of the Matchers in the matcher tree (see
+// matcherTree and routeTree) implementing T, such as the route policies
+// enforced by the Mux.
+func policies[T any](tree []Matcher) []T {
+	var v []T
+	for _, m := range tree {
+		if p, ok := m.(T); ok {
+			v = append(v, p)
+		}
+	}
+	return v
+}
+
+// AllowedMethods returns the methods of the routes registered for the
+// request's path with the Mux serving the request (see Mux.Allowed),
+// including sub-Muxes. The path is that of the request prior to routing, so
+// AllowedMethods may also be used by handlers after a route matched.
+func AllowedMethods(req *http.Request) []string {
+	d, ok := req.Context().Value(dispatchKey).(*dispatchState)
+	if !ok || !d.routed {
+		return nil
+	}
+	return d.mux.Allowed(req.WithContext(context.WithValue(req.Context(), pathKey, d.path)))
+}
+
+// Draining returns whether or not the Mux serving the request is draining
+// (see Mux.Drain).
+func Draining(req *http.Request) bool {
+	if m, ok := req.Context().Value(muxKey).(*Mux); ok {
+		return m.Draining()
+	}
+	return false
+}
+
+// RouteTemplate returns the template of the matched route for the request
+// (for example, "/user/:name"), or an empty string when no route matched.
+// The template is the string form of the matched Matcher (or the first
+// Matcher of And), falling back to its type when the Matcher is not a
+// fmt.Stringer.
+//
+// Logging, metrics, and tracing middleware should use RouteTemplate (or
+// RouteAttrs) to tag records, as the template has low cardinality compared
+// to the request path.
+func RouteTemplate(req *http.Request) string {
+	m := routeMatcher(req)
+	if m == nil {
+		return ""
+	}
+	for _, m := range matcherTree(m) {
+		switch v := m.(type) {
+		case and, interface{ Unwrap() Matcher }:
+			continue
+		case fmt.Stringer:
+			return v.String()
+		}
+		return fmt.Sprintf("%T", m)
+	}
+	return fmt.Sprintf("%T", Matched(req))
+}
+
+// RouteName returns the name of the matched route for the request (see
+// WithName), or an empty string when no route matched or the route is not
+// named.
+func RouteName(req *http.Request) string {
+	return matcherName(routeMatcher(req))
+}
+
+// matcherName returns the first route name of the matcher or the Matchers it
+// wraps or combines.
+func matcherName(matcher Matcher) string {
+	for _, m := range policies[interface{ Name() string }](matcherTree(matcher)) {
+		if name := m.Name(); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// RouteAttrs returns the "route" (see RouteTemplate) and "route_name" (see
+// RouteName, when named) log attributes for the request, allowing
+// middleware to consistently tag records with the matched route.
+//
+// For example:
+//
+//	slog.LogAttrs(ctx, slog.LevelInfo, "request", goji.RouteAttrs(req)...)
+func RouteAttrs(req *http.Request) []slog.Attr {
+	attrs := []slog.Attr{slog.String("route", RouteTemplate(req))}
+	if name := RouteName(req); name != "" {
+		attrs = append(attrs, slog.String("route_name", name))
+	}
+	return attrs
+}
+
+// RouteInfo is information about a route.
+type RouteInfo struct {
+	// Matcher is the route's Matcher.
+	Matcher Matcher
+	// Template is the route template (see RouteTemplate).
+	Template string
+	// Name is the route name (see RouteName).
+	Name string
+	// Methods are the sorted HTTP methods matched by the route, or nil when
+	// the route matches all methods.
+	Methods []string
+	// Params are the bound route params.
+	Params map[string]string
+	// Auth is the route's authentication policy (see WithAuth).
+	Auth *AuthPolicy
+}
+
+// Meta returns the route metadata value for key, or nil when the route does
+// not carry metadata (see WithMeta).
+func (r RouteInfo) Meta(key string) interface{} {
+	for _, m := range policies[interface {
+		Meta(string) interface{}
+	}](matcherTree(r.Matcher)) {
+		if v := m.Meta(key); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// MatchedRoute returns information about the matched route for the request.
+// The returned RouteInfo's Matcher is nil when no route matched.
+func MatchedRoute(req *http.Request) RouteInfo {
+	matcher := Matched(req)
+	if matcher == nil {
+		return RouteInfo{}
+	}
+	var methods []string
+	for method := range matcher.Methods() {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return RouteInfo{
+		Matcher:  matcher,
+		Template: RouteTemplate(req),
+		Name:     RouteName(req),
+		Methods:  methods,
+		Params:   Params(req),
+		Auth:     RouteAuth(req),
+	}
+}
+
 // Path returns the path prefix from the context.
 func Path(ctx context.Context) string {
 	if path := ctx.Value(pathKey); path != nil {
@@ -47,6 +301,25 @@ func Path(ctx context.Context) string {
 	return ""
 }
 
+// ForwardedPrefix returns the forwarded prefix from the context, that is, the
+// path prefix stripped by a reverse proxy (the X-Forwarded-Prefix header).
+// See TrustForwardedPrefix and middleware.Forwarded.
+func ForwardedPrefix(ctx context.Context) string {
+	if prefix, ok := ctx.Value(forwardedPrefixKey).(string); ok {
+		return prefix
+	}
+	return ""
+}
+
+// cleanPrefix cleans a path prefix, ensuring it has a leading and no trailing
+// slash.
+func cleanPrefix(prefix string) string {
+	if prefix = strings.TrimRight(prefix, "/"); prefix != "" && prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
 // Param returns a bound, named variable from the context.
 //
 // For example, given a mux with a single GET route:
@@ -62,7 +335,25 @@ func Path(ctx context.Context) string {
 //
 // Note: caller should ensure that the variable has been bound. Attempts to
 // access variables that have not been set (or which have been invalidly set)
-// are considered programmer errors and will trigger a panic.
+// are considered programmer errors and will trigger a panic. Variables of
+// omitted optional segments (such as "name" in "/user/:name?") are not bound,
+// and should be retrieved with Params.
 func Param(req *http.Request, name string) string {
 	return req.Context().Value(nameKey(name)).(string)
 }
+
+// Params returns all bound, named variables from the request context, or nil
+// if no variables are bound.
+func Params(req *http.Request) map[string]string {
+	vs, ok := req.Context().Value(allNames).(map[nameKey]interface{})
+	if !ok || len(vs) == 0 {
+		return nil
+	}
+	params := make(map[string]string, len(vs))
+	for k, v := range vs {
+		if s, ok := v.(string); ok {
+			params[string(k)] = s
+		}
+	}
+	return params
+}
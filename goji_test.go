@@ -3,6 +3,8 @@ package goji
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -32,3 +34,81 @@ func TestWithPath(t *testing.T) {
 		t.Errorf("expected empty path, got: %q", path)
 	}
 }
+
+func TestMatchedMeta(t *testing.T) {
+	_, req := resreq()
+	if m := Matched(req); m != nil {
+		t.Errorf("expected nil matcher, got: %v", m)
+	}
+	if v := Meta(req, "priority"); v != nil {
+		t.Errorf("expected nil meta, got: %v", v)
+	}
+	p := NewPathSpec("/", WithMeta("priority", "low"))
+	req = req.WithContext(WithMatcher(req.Context(), p))
+	if m := Matched(req); m != p {
+		t.Errorf("expected %v, got: %v", p, m)
+	}
+	if v := Meta(req, "priority"); v != "low" {
+		t.Errorf("expected low, got: %v", v)
+	}
+	req = req.WithContext(WithMatcher(req.Context(), boolMatcher(true)))
+	if v := Meta(req, "priority"); v != nil {
+		t.Errorf("expected nil meta, got: %v", v)
+	}
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		matcher Matcher
+		tmpl    string
+		name    string
+		attrs   int
+	}{
+		{nil, "", "", 1},
+		{boolMatcher(true), "goji.boolMatcher", "", 1},
+		{Get("/user/:name"), "/user/:name", "", 1},
+		{Get("/user/:name", WithName("user_show")), "/user/:name", "user_show", 2},
+	}
+	for i, test := range tests {
+		req, _ := http.NewRequest("GET", "/", nil)
+		if test.matcher != nil {
+			req = req.WithContext(WithMatcher(req.Context(), test.matcher))
+		}
+		if s := RouteTemplate(req); s != test.tmpl {
+			t.Errorf("test %d expected template %q, got: %q", i, test.tmpl, s)
+		}
+		if s := RouteName(req); s != test.name {
+			t.Errorf("test %d expected name %q, got: %q", i, test.name, s)
+		}
+		if attrs := RouteAttrs(req); len(attrs) != test.attrs || attrs[0].Value.String() != test.tmpl {
+			t.Errorf("test %d expected %d attrs with route %q, got: %v", i, test.attrs, test.tmpl, attrs)
+		}
+	}
+}
+
+func TestMatchedRoute(t *testing.T) {
+	m := New()
+	var route RouteInfo
+	m.HandleFunc(Get("/users/:name", WithName("user"), WithAuth(false), WithMeta("k", "v")), func(res http.ResponseWriter, req *http.Request) {
+		route = MatchedRoute(req)
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/bob", nil))
+	if route.Template != "/users/:name" || route.Name != "user" {
+		t.Errorf("expected template and name, got: %q %q", route.Template, route.Name)
+	}
+	if s := strings.Join(route.Methods, ","); s != "GET,HEAD" {
+		t.Errorf("expected methods %q, got: %q", "GET,HEAD", s)
+	}
+	if route.Params["name"] != "bob" {
+		t.Errorf("expected param %q, got: %q", "bob", route.Params["name"])
+	}
+	if route.Auth == nil || route.Auth.Required {
+		t.Errorf("expected auth policy, got: %v", route.Auth)
+	}
+	if v := route.Meta("k"); v != "v" {
+		t.Errorf("expected meta %q, got: %v", "v", v)
+	}
+	if r := MatchedRoute(httptest.NewRequest("GET", "/", nil)); r.Matcher != nil {
+		t.Errorf("expected no matcher, got: %v", r.Matcher)
+	}
+}
@@ -0,0 +1,129 @@
+// Package gojitest provides utilities for testing goji.Mux routing with
+// recorded traffic and API specs.
+//
+// Traffic recorded with middleware.Record can be replayed against a Mux, so
+// that production traffic captures become regression tests:
+//
+//	func TestReplay(t *testing.T) {
+//		f, err := os.Open("testdata/traffic.jsonl")
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		defer f.Close()
+//		gojitest.Replay(t, newMux(), f)
+//	}
+//
+// HAR (HTTP Archive) files, as exported by browsers and proxies, can likewise
+// drive a Mux:
+//
+//	func TestHAR(t *testing.T) {
+//		gojitest.RunHARFile(t, newMux(), "testdata/session.har", gojitest.WithBodies(true))
+//	}
+//
+// Routes can be checked against an OpenAPI document (see CheckOpenAPI):
+//
+//	func TestOpenAPI(t *testing.T) {
+//		gojitest.CheckOpenAPIFile(t, newMux(), "openapi.json")
+//	}
+package gojitest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji/middleware"
+)
+
+// ReadRecordings reads the JSONL encoded recordings (see
+// middleware.JSONLSink).
+func ReadRecordings(r io.Reader) ([]middleware.Recording, error) {
+	var recs []middleware.Recording
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 64<<20)
+	for s.Scan() {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		var rec middleware.Recording
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+// Replay replays the JSONL encoded recordings (see middleware.Record)
+// against the handler, reporting a test error for each recorded response
+// whose status (and optionally body, see WithBodies) differs from the
+// handler's response. Truncated response bodies are not compared.
+func Replay(t testing.TB, h http.Handler, r io.Reader, opts ...Option) {
+	t.Helper()
+	recs, err := ReadRecordings(r)
+	if err != nil {
+		t.Fatalf("unable to read recordings: %v", err)
+	}
+	o := newOptions(opts...)
+	for i, rec := range recs {
+		req := httptest.NewRequest(rec.Method, rec.URL, bytes.NewReader(rec.Body))
+		for k, v := range rec.Header {
+			req.Header[k] = v
+		}
+		if rec.Host != "" {
+			req.Host = rec.Host
+		}
+		if rec.Response == nil {
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			continue
+		}
+		c := *o
+		c.bodies = c.bodies && !rec.Response.Truncated
+		c.check(t, i, h, req, rec.Response.Status, rec.Response.Body)
+	}
+}
+
+// options are the replay options.
+type options struct {
+	bodies bool
+}
+
+// newOptions creates the replay options.
+func newOptions(opts ...Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// check serves the request, comparing the response to the expected status
+// and body.
+func (o *options) check(t testing.TB, i int, h http.Handler, req *http.Request, status int, body []byte) {
+	t.Helper()
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	if res.Code != status {
+		t.Errorf("entry %d %s %s expected status %d, got: %d", i, req.Method, req.URL.RequestURI(), status, res.Code)
+	}
+	if o.bodies && !bytes.Equal(res.Body.Bytes(), body) {
+		t.Errorf("entry %d %s %s expected body %q, got: %q", i, req.Method, req.URL.RequestURI(), body, res.Body.Bytes())
+	}
+}
+
+// Option is a replay option.
+type Option func(*options)
+
+// WithBodies is a replay option to also compare response bodies.
+func WithBodies(bodies bool) Option {
+	return func(o *options) {
+		o.bodies = bodies
+	}
+}
@@ -0,0 +1,58 @@
+package gojitest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+	"github.com/kenshaw/goji/middleware"
+)
+
+// errorsTB is a testing.TB that records errors.
+type errorsTB struct {
+	testing.TB
+	errors []string
+}
+
+func (t *errorsTB) Helper() {}
+
+func (t *errorsTB) Errorf(format string, v ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, v...))
+}
+
+func newMux(greeting string) *goji.Mux {
+	m := goji.New()
+	m.HandleFunc(goji.Get("/hello/:name"), func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(res, "%s, %s!", greeting, goji.Param(req, "name"))
+	})
+	return m
+}
+
+func TestReplay(t *testing.T) {
+	var buf bytes.Buffer
+	m := newMux("hello")
+	m.Use(middleware.Record(middleware.JSONLSink(&buf), middleware.WithRecordSample(1), middleware.WithRecordResponses(true)))
+	for _, path := range []string{"/hello/carl", "/hello/", "/goodbye"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	recs, err := ReadRecordings(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 recordings, got: %d", len(recs))
+	}
+	Replay(t, newMux("hello"), bytes.NewReader(buf.Bytes()), WithBodies(true))
+	tb := &errorsTB{TB: t}
+	Replay(tb, newMux("hi"), bytes.NewReader(buf.Bytes()))
+	if len(tb.errors) != 0 {
+		t.Errorf("expected no errors, got: %v", tb.errors)
+	}
+	Replay(tb, newMux("hi"), bytes.NewReader(buf.Bytes()), WithBodies(true))
+	if len(tb.errors) != 1 {
+		t.Errorf("expected 1 error, got: %v", tb.errors)
+	}
+}
@@ -0,0 +1,95 @@
+package gojitest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// HAR is a HTTP Archive (HAR 1.2), as exported by browsers and proxies.
+// Only the fields used to drive requests are decoded.
+type HAR struct {
+	Log struct {
+		Entries []HAREntry `json:"entries"`
+	} `json:"log"`
+}
+
+// HAREntry is a HAR entry.
+type HAREntry struct {
+	Request struct {
+		Method   string      `json:"method"`
+		URL      string      `json:"url"`
+		Headers  []HARHeader `json:"headers"`
+		PostData *struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+// HARHeader is a HAR header.
+type HARHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// RunHAR drives the handler with each entry of the HAR, reporting a test
+// error for each entry whose response status (and optionally body, see
+// WithBodies) differs from the handler's response.
+func RunHAR(t testing.TB, h http.Handler, r io.Reader, opts ...Option) {
+	t.Helper()
+	var har HAR
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		t.Fatalf("unable to decode HAR: %v", err)
+	}
+	o := newOptions(opts...)
+	for i, entry := range har.Log.Entries {
+		var body io.Reader
+		if entry.Request.PostData != nil {
+			body = strings.NewReader(entry.Request.PostData.Text)
+		}
+		req := httptest.NewRequest(entry.Request.Method, entry.Request.URL, body)
+		for _, header := range entry.Request.Headers {
+			// skip HTTP/2 pseudo headers
+			if !strings.HasPrefix(header.Name, ":") {
+				req.Header.Add(header.Name, header.Value)
+			}
+		}
+		if entry.Request.PostData != nil && entry.Request.PostData.MimeType != "" {
+			req.Header.Set("Content-Type", entry.Request.PostData.MimeType)
+		}
+		content := []byte(entry.Response.Content.Text)
+		if entry.Response.Content.Encoding == "base64" {
+			buf, err := base64.StdEncoding.DecodeString(entry.Response.Content.Text)
+			if err != nil {
+				t.Fatalf("entry %d unable to decode response content: %v", i, err)
+			}
+			content = buf
+		}
+		o.check(t, i, h, req, entry.Response.Status, content)
+	}
+}
+
+// RunHARFile drives the handler with each entry of the HAR file (see
+// RunHAR).
+func RunHARFile(t testing.TB, h http.Handler, name string, opts ...Option) {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("unable to open HAR: %v", err)
+	}
+	defer f.Close()
+	RunHAR(t, h, f, opts...)
+}
@@ -0,0 +1,12 @@
+package gojitest
+
+import "testing"
+
+func TestRunHAR(t *testing.T) {
+	RunHARFile(t, newMux("hello"), "testdata/session.har", WithBodies(true))
+	tb := &errorsTB{TB: t}
+	RunHARFile(tb, newMux("hi"), "testdata/session.har", WithBodies(true))
+	if len(tb.errors) != 2 {
+		t.Errorf("expected 2 errors, got: %v", tb.errors)
+	}
+}
@@ -0,0 +1,38 @@
+package gojitest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+// CheckOpenAPI checks the Mux's routes against the JSON encoded OpenAPI
+// document (see goji.Mux.CheckOpenAPI), reporting a test error for each
+// documented operation that is not routed, and each route that is not
+// documented, preventing drift between code and spec:
+//
+//	func TestOpenAPI(t *testing.T) {
+//		gojitest.CheckOpenAPI(t, newMux(), spec)
+//	}
+func CheckOpenAPI(t testing.TB, m *goji.Mux, doc []byte) {
+	t.Helper()
+	problems, err := m.CheckOpenAPI(doc)
+	if err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	for _, p := range problems {
+		t.Errorf("%s", p)
+	}
+}
+
+// CheckOpenAPIFile checks the Mux's routes against the JSON encoded OpenAPI
+// document in the named file (see CheckOpenAPI).
+func CheckOpenAPIFile(t testing.TB, m *goji.Mux, name string) {
+	t.Helper()
+	doc, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	CheckOpenAPI(t, m, doc)
+}
@@ -0,0 +1,17 @@
+package gojitest
+
+import "testing"
+
+func TestCheckOpenAPI(t *testing.T) {
+	tb := &errorsTB{TB: t}
+	CheckOpenAPIFile(tb, newMux("hi"), "testdata/openapi.json")
+	exp := "Unrouted: /goodbye/{name}: operation GET /goodbye/{name} is not routed"
+	if len(tb.errors) != 1 || tb.errors[0] != exp {
+		t.Errorf("expected %q, got: %v", exp, tb.errors)
+	}
+	tb = &errorsTB{TB: t}
+	CheckOpenAPI(tb, newMux("hi"), []byte(`{"paths": {"/hello/{name}": {"get": {}}}}`))
+	if len(tb.errors) != 0 {
+		t.Errorf("expected no errors, got: %v", tb.errors)
+	}
+}
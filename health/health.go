@@ -0,0 +1,275 @@
+// Package health provides health checking handlers for use with goji.Mux.
+//
+// Distinct liveness (the process is up) and readiness (dependencies are ok,
+// and the service is not draining) handlers are provided, suitable for use
+// with orchestrators such as Kubernetes:
+//
+//	health.Watch(mux)
+//	mux.Handle(goji.Get("/_health/live"), health.Liveness())
+//	mux.Handle(goji.Get("/_health/ready"), health.Readiness())
+//
+// Checks are registered by name, and are run in parallel (each with a
+// timeout) when the handler is served, reporting the aggregate and per-check
+// status as JSON with a 200 OK (when all checks pass) or 503 Service
+// Unavailable status code:
+//
+//	health.Register("db", func(ctx context.Context) error {
+//		return db.PingContext(ctx)
+//	})
+//	mux.Handle(goji.Get("/_health"), health.Handler())
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// DrainCheck is the reserved check name reporting the draining status in
+// readiness reports. Checks cannot be registered with the name.
+const DrainCheck = "goji.drain"
+
+// Status values.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc is a health check func. A check passes when it returns a nil
+// error.
+type CheckFunc func(context.Context) error
+
+// Result is the result of a single health check.
+type Result struct {
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is an aggregate health report.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// OK returns whether or not the report's status is ok.
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker is a set of registered health checks.
+type Checker struct {
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	checks   map[string]CheckFunc
+	drainers []Drainer
+}
+
+// Drainer is the interface for types that can report whether or not they are
+// draining, such as goji.Mux.
+type Drainer interface {
+	Draining() bool
+}
+
+// New creates a new health checker.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		timeout: 5 * time.Second,
+		checks:  make(map[string]CheckFunc),
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// Register registers the check func with the name, replacing any previously
+// registered check with the same name. Register panics when the name is the
+// reserved DrainCheck name.
+func (c *Checker) Register(name string, f CheckFunc) {
+	if name == DrainCheck {
+		panic("health: check name " + DrainCheck + " is reserved")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = f
+}
+
+// Watch adds the Drainer to the drainers watched by the checker's readiness
+// handler. Readiness fails while any watched Drainer is draining.
+func (c *Checker) Watch(d Drainer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.drainers = append(c.drainers, d)
+}
+
+// Draining returns whether or not any watched Drainer is draining.
+func (c *Checker) Draining() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, d := range c.drainers {
+		if d.Draining() {
+			return true
+		}
+	}
+	return false
+}
+
+// Check runs all registered checks in parallel, returning the aggregate
+// report.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]CheckFunc, len(c.checks))
+	for name, f := range c.checks {
+		checks[name] = f
+	}
+	c.mu.RUnlock()
+	return run(ctx, c.timeout, checks)
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (c *Checker) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	write(res, c.Check(req.Context()))
+}
+
+// Liveness returns a handler reporting that the process is up. Registered
+// checks are not run.
+func (c *Checker) Liveness() http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		write(res, Report{Status: StatusOK})
+	})
+}
+
+// Readiness returns a handler reporting whether the process is ready to
+// receive traffic: all registered checks pass, and neither a watched Drainer
+// nor the goji.Mux serving the request is draining (see goji.Draining). As
+// goji.Run drains the Mux on shutdown, readiness fails automatically once
+// the server begins shutting down. The draining status is reported as the
+// DrainCheck check.
+func (c *Checker) Readiness() http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		r := c.Check(req.Context())
+		if c.Draining() || goji.Draining(req) {
+			r.Status = StatusFail
+			r.Checks[DrainCheck] = Result{Status: StatusFail, Error: "draining"}
+		}
+		write(res, r)
+	})
+}
+
+// run runs the checks in parallel, each with the timeout.
+func run(ctx context.Context, timeout time.Duration, checks map[string]CheckFunc) Report {
+	r := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, f := range checks {
+		wg.Add(1)
+		go func(name string, f CheckFunc) {
+			defer wg.Done()
+			res := check(ctx, timeout, f)
+			mu.Lock()
+			defer mu.Unlock()
+			r.Checks[name] = res
+			if res.Status != StatusOK {
+				r.Status = StatusFail
+			}
+		}(name, f)
+	}
+	wg.Wait()
+	return r
+}
+
+// check runs a single check with the timeout. A panicking check fails with
+// the panic value.
+func check(ctx context.Context, timeout time.Duration, f CheckFunc) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	ch := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				ch <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		ch <- f(ctx)
+	}()
+	var err error
+	select {
+	case err = <-ch:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := Result{
+		Status:   StatusOK,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		res.Status, res.Error = StatusFail, err.Error()
+	}
+	return res
+}
+
+// write writes the report as JSON.
+func write(res http.ResponseWriter, r Report) {
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Cache-Control", "no-store")
+	if r.OK() {
+		res.WriteHeader(http.StatusOK)
+	} else {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}
+	enc := json.NewEncoder(res)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(r)
+}
+
+// Option is a health checker option.
+type Option func(*Checker)
+
+// WithTimeout is a health checker option to set the timeout for each check
+// (default 5s).
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Checker) {
+		c.timeout = timeout
+	}
+}
+
+// Default is the default health checker.
+var Default = New()
+
+// Register registers the check func with the name on the default health
+// checker.
+func Register(name string, f CheckFunc) {
+	Default.Register(name, f)
+}
+
+// Watch adds the Drainer to the drainers watched by the default health
+// checker.
+func Watch(d Drainer) {
+	Default.Watch(d)
+}
+
+// Handler returns the default health checker's handler.
+func Handler() http.Handler {
+	return Default
+}
+
+// Liveness returns the default health checker's liveness handler.
+func Liveness() http.Handler {
+	return Default.Liveness()
+}
+
+// Readiness returns the default health checker's readiness handler.
+func Readiness() http.Handler {
+	return Default.Readiness()
+}
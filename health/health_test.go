@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestChecker(t *testing.T) {
+	c := New(WithTimeout(10 * time.Millisecond))
+	c.Register("a", func(context.Context) error { return nil })
+	c.Register("b", func(context.Context) error { return nil })
+	if code, r := serve(t, c); code != http.StatusOK || r.Status != StatusOK || len(r.Checks) != 2 {
+		t.Errorf("expected %d ok with 2 checks, got: %d %+v", http.StatusOK, code, r)
+	}
+
+	c.Register("c", func(context.Context) error { return errors.New("c is broken") })
+	code, r := serve(t, c)
+	if code != http.StatusServiceUnavailable || r.Status != StatusFail {
+		t.Errorf("expected %d fail, got: %d %+v", http.StatusServiceUnavailable, code, r)
+	}
+	if res := r.Checks["c"]; res.Status != StatusFail || res.Error != "c is broken" {
+		t.Errorf("expected c to fail, got: %+v", res)
+	}
+	if res := r.Checks["a"]; res.Status != StatusOK {
+		t.Errorf("expected a to pass, got: %+v", res)
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	c := New(WithTimeout(10 * time.Millisecond))
+	c.Register("slow", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	start := time.Now()
+	code, r := serve(t, c)
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("expected check to time out, took: %v", d)
+	}
+	if code != http.StatusServiceUnavailable || r.Checks["slow"].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected %d with deadline exceeded, got: %d %+v", http.StatusServiceUnavailable, code, r)
+	}
+}
+
+func TestCheckerPanic(t *testing.T) {
+	c := New(WithTimeout(time.Second))
+	c.Register("panic", func(context.Context) error {
+		panic("boom")
+	})
+	code, r := serve(t, c)
+	if res := r.Checks["panic"]; code != http.StatusServiceUnavailable || res.Status != StatusFail || res.Error != "panic: boom" {
+		t.Errorf("expected %d with panic, got: %d %+v", http.StatusServiceUnavailable, code, r)
+	}
+}
+
+func serve(t *testing.T, h http.Handler) (int, Report) {
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/_health", nil))
+	var r Report
+	if err := json.Unmarshal(res.Body.Bytes(), &r); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return res.Code, r
+}
+
+func TestLivenessReadiness(t *testing.T) {
+	c := New()
+	fail := errors.New("down")
+	var err error
+	c.Register("db", func(context.Context) error { return err })
+	d := new(drainer)
+	c.Watch(d)
+
+	if code, r := serve(t, c.Liveness()); code != http.StatusOK || !r.OK() {
+		t.Errorf("expected live, got: %d %+v", code, r)
+	}
+	if code, r := serve(t, c.Readiness()); code != http.StatusOK || !r.OK() {
+		t.Errorf("expected ready, got: %d %+v", code, r)
+	}
+
+	err = fail
+	if code, r := serve(t, c.Liveness()); code != http.StatusOK || !r.OK() {
+		t.Errorf("expected live, got: %d %+v", code, r)
+	}
+	if code, r := serve(t, c.Readiness()); code != http.StatusServiceUnavailable || r.Checks["db"].Error != "down" {
+		t.Errorf("expected not ready, got: %d %+v", code, r)
+	}
+
+	err, *d = nil, true
+	code, r := serve(t, c.Readiness())
+	if code != http.StatusServiceUnavailable || r.Checks[DrainCheck].Error != "draining" {
+		t.Errorf("expected not ready while draining, got: %d %+v", code, r)
+	}
+}
+
+type drainer bool
+
+func (d *drainer) Draining() bool {
+	return bool(*d)
+}
+
+func TestReadinessMux(t *testing.T) {
+	c := New()
+	m := goji.New()
+	m.Handle(goji.Get("/_health/ready"), c.Readiness())
+	for i, draining := range []bool{false, true} {
+		if draining {
+			m.Drain()
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", "/_health/ready", nil))
+		var r Report
+		if err := json.Unmarshal(res.Body.Bytes(), &r); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if _, ok := r.Checks[DrainCheck]; r.OK() == draining || ok != draining {
+			t.Errorf("test %d expected ok=%t, got: %d %+v", i, !draining, res.Code, r)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic registering reserved check name")
+		}
+	}()
+	c.Register(DrainCheck, func(context.Context) error { return nil })
+}
@@ -0,0 +1,142 @@
+package goji
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HostSpec provides a Matcher that matches requests based on the request's
+// host, in addition to a wrapped Matcher, storing matched host labels in the
+// request context.
+//
+// Host specs are dot separated labels, where labels with a leading ":" match
+// (and bind) any non-empty label. Host matching is case-insensitive, and any
+// port in the request's host is ignored. For example, to route requests for
+// tenant subdomains:
+//
+//	mux.HandleFunc(goji.Host(":tenant.example.com", goji.Get("/users")), users)
+//
+// A request for "acme.example.com/users" would bind the "tenant" variable to
+// "acme", available via the Param func. Path variables with the same name
+// take precedence over host variables.
+type HostSpec struct {
+	raw     string
+	labels  []string
+	matcher Matcher
+}
+
+// Host returns a HostSpec that matches requests for the host spec and the
+// matcher. When the matcher is nil, requests for any path and method are
+// matched, allowing a sub-Mux to be mounted for a host:
+//
+//	mux.Handle(goji.Host(":tenant.example.com", nil), tenantMux)
+func Host(spec string, matcher Matcher) *HostSpec {
+	return &HostSpec{
+		raw:     spec,
+		labels:  strings.Split(strings.ToLower(spec), "."),
+		matcher: matcher,
+	}
+}
+
+// Match runs the host spec and the wrapped matcher against the passed
+// request, returning a modified copy of the request when both match.
+func (h *HostSpec) Match(req *http.Request) *http.Request {
+	host := req.Host
+	if s, _, err := net.SplitHostPort(host); err == nil {
+		host = s
+	}
+	labels := strings.Split(strings.ToLower(host), ".")
+	if len(labels) != len(h.labels) {
+		return nil
+	}
+	var names map[nameKey]interface{}
+	for i, label := range h.labels {
+		switch {
+		case strings.HasPrefix(label, ":") && labels[i] != "":
+			if names == nil {
+				names = make(map[nameKey]interface{})
+			}
+			names[nameKey(label[1:])] = labels[i]
+		case label != labels[i]:
+			return nil
+		}
+	}
+	if names != nil {
+		req = req.WithContext(&hostContext{req.Context(), names})
+	}
+	if h.matcher == nil {
+		return req
+	}
+	return h.matcher.Match(req)
+}
+
+// Methods returns the set of HTTP methods that the wrapped matcher matches.
+func (h *HostSpec) Methods() map[string]struct{} {
+	if h.matcher == nil {
+		return nil
+	}
+	return h.matcher.Methods()
+}
+
+// Prefix returns the prefix for requests that the wrapped matcher matches.
+func (h *HostSpec) Prefix() string {
+	if h.matcher == nil {
+		return ""
+	}
+	return h.matcher.Prefix()
+}
+
+// matchers returns the wrapped Matcher.
+func (h *HostSpec) matchers() []Matcher {
+	if h.matcher == nil {
+		return nil
+	}
+	return []Matcher{h.matcher}
+}
+
+// URL builds the path for the wrapped matcher (see PathSpec.URL).
+func (h *HostSpec) URL(params ...string) (string, error) {
+	if m, ok := h.matcher.(interface {
+		URL(...string) (string, error)
+	}); ok {
+		return m.URL(params...)
+	}
+	return "", ErrNotReversible
+}
+
+// String satisfies fmt.Stringer interface.
+func (h *HostSpec) String() string {
+	if s, ok := h.matcher.(interface{ String() string }); ok {
+		return h.raw + s.String()
+	}
+	return h.raw
+}
+
+// hostContext is a context with bound host variables.
+type hostContext struct {
+	context.Context
+	names map[nameKey]interface{}
+}
+
+func (h *hostContext) Value(key interface{}) interface{} {
+	if key == allNames {
+		vs := make(map[nameKey]interface{}, len(h.names))
+		if vsi, ok := h.Context.Value(key).(map[nameKey]interface{}); ok {
+			for k, v := range vsi {
+				vs[k] = v
+			}
+		}
+		for k, v := range h.names {
+			vs[k] = v
+		}
+		return vs
+	}
+	if k, ok := key.(nameKey); ok {
+		if v, ok := h.names[k]; ok {
+			return v
+		}
+	}
+	return h.Context.Value(key)
+}
@@ -0,0 +1,94 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHost(t *testing.T) {
+	m := New()
+	m.HandleFunc(Host(":tenant.example.com", Get("/users/:id")), func(res http.ResponseWriter, req *http.Request) {
+		if !reflect.DeepEqual(Params(req), map[string]string{"tenant": Param(req, "tenant"), "id": Param(req, "id")}) {
+			t.Errorf("expected tenant and id params, got: %v", Params(req))
+		}
+		res.Write([]byte(Param(req, "tenant") + " " + Param(req, "id")))
+	})
+	m.HandleFunc(Host("api.:region.example.com", Get("/")), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(Param(req, "region")))
+	})
+	tests := []struct {
+		host   string
+		path   string
+		status int
+		exp    string
+	}{
+		{"acme.example.com", "/users/1", http.StatusOK, "acme 1"},
+		{"ACME.Example.com:8080", "/users/2", http.StatusOK, "acme 2"},
+		{"example.com", "/users/1", http.StatusNotFound, ""},
+		{"a.b.example.com", "/users/1", http.StatusNotFound, ""},
+		{".example.com", "/users/1", http.StatusNotFound, ""},
+		{"acme.example.org", "/users/1", http.StatusNotFound, ""},
+		{"api.us-east.example.com", "/", http.StatusOK, "us-east"},
+		{"www.us-east.example.com", "/", http.StatusNotFound, ""},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Host = test.host
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if test.status == http.StatusOK && res.Body.String() != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, res.Body.String())
+		}
+	}
+}
+
+func TestHostSpec(t *testing.T) {
+	h := Host(":tenant.example.com", Get("/users", WithMeta("k", "v")))
+	if s := h.String(); s != ":tenant.example.com/users" {
+		t.Errorf("expected %q, got: %q", ":tenant.example.com/users", s)
+	}
+	if s := h.Prefix(); s != "/users" {
+		t.Errorf("expected %q, got: %q", "/users", s)
+	}
+	if _, ok := h.Methods()["GET"]; !ok {
+		t.Errorf("expected GET method")
+	}
+	if v := (RouteInfo{Matcher: h}).Meta("k"); v != "v" {
+		t.Errorf("expected %q, got: %v", "v", v)
+	}
+}
+
+func TestHostMount(t *testing.T) {
+	sub := NewSubMux()
+	sub.HandleFunc(Get("/users/:id"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(Param(req, "tenant") + " " + Param(req, "id")))
+	})
+	m := New()
+	m.Handle(Host(":tenant.example.com", nil), sub)
+	m.HandleFunc(Get("/users/:id"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("default " + Param(req, "id")))
+	})
+	for i, test := range []struct {
+		host string
+		exp  string
+	}{
+		{"acme.example.com", "acme 1"},
+		{"example.com", "default 1"},
+	} {
+		req := httptest.NewRequest("GET", "/users/1", nil)
+		req.Host = test.host
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	if h := Host("example.com", nil); h.Prefix() != "" || h.Methods() != nil || h.String() != "example.com" {
+		t.Errorf("expected host only spec, got: %q %v %q", h.Prefix(), h.Methods(), h.String())
+	}
+}
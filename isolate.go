@@ -0,0 +1,92 @@
+package goji
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// isolatedMatcher is a Matcher that recovers from panics in, and bounds the
+// time spent by, the wrapped Matcher's Match.
+type isolatedMatcher struct {
+	Matcher
+	timeout time.Duration
+}
+
+// isolate wraps the matcher with panic recovery and the time budget, unless
+// it is a built-in matcher.
+func isolate(matcher Matcher, timeout time.Duration) Matcher {
+	if _, ok := matcher.(*PathSpec); ok {
+		return matcher
+	}
+	return isolatedMatcher{Matcher: matcher, timeout: timeout}
+}
+
+// registered returns the Matcher registered with the Mux for the router's
+// matcher, removing any isolation.
+func registered(matcher Matcher) Matcher {
+	if m, ok := matcher.(isolatedMatcher); ok {
+		return m.Matcher
+	}
+	return matcher
+}
+
+// Match satisfies the Matcher interface. Returns nil (skipping the route)
+// when the wrapped Matcher panics or exceeds its time budget.
+func (m isolatedMatcher) Match(req *http.Request) *http.Request {
+	if m.timeout <= 0 {
+		return m.match(req)
+	}
+	ch := make(chan *http.Request, 1)
+	go func() {
+		ch <- m.match(req)
+	}()
+	t := time.NewTimer(m.timeout)
+	defer t.Stop()
+	select {
+	case req := <-ch:
+		return req
+	case <-t.C:
+		slog.Warn("matcher timeout exceeded", "matcher", m.name(), "path", req.URL.Path, "timeout", m.timeout)
+		return nil
+	}
+}
+
+// match invokes the wrapped Matcher's Match, recovering from panics.
+func (m isolatedMatcher) match(req *http.Request) (res *http.Request) {
+	defer func() {
+		if v := recover(); v != nil {
+			slog.Error("matcher panic", "matcher", m.name(), "path", req.URL.Path, "panic", v)
+			res = nil
+		}
+	}()
+	return m.Matcher.Match(req)
+}
+
+// name returns the wrapped Matcher's name for logging.
+func (m isolatedMatcher) name() string {
+	if s, ok := m.Matcher.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", m.Matcher)
+}
+
+// Unwrap returns the wrapped Matcher.
+func (m isolatedMatcher) Unwrap() Matcher {
+	return m.Matcher
+}
+
+// WithMatcherIsolation is a mux option to isolate custom (third-party)
+// Matchers, so that a faulty Matcher cannot take down routing for all
+// requests. Panics in a Matcher's Match are recovered, and when the timeout
+// is greater than 0, Match is bounded by the timeout. Failures are logged
+// using the default slog.Logger, and the route is skipped.
+//
+// Built-in PathSpec Matchers are not isolated. Matchers are isolated when
+// registered, and the option must be set prior to registering routes.
+func WithMatcherIsolation(timeout time.Duration) MuxOption {
+	return func(m *Mux) {
+		m.isolate, m.isolateFor = true, timeout
+	}
+}
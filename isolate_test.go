@@ -0,0 +1,57 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// metaMatcher is a Matcher with metadata.
+type metaMatcher struct {
+	boolMatcher
+}
+
+func (metaMatcher) Meta(key string) interface{} {
+	return key
+}
+
+func TestMatcherIsolation(t *testing.T) {
+	m := New(WithMatcherIsolation(20 * time.Millisecond))
+	m.HandleFunc(slowMatcher{-1}, func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("panic"))
+	})
+	m.HandleFunc(slowMatcher{time.Second}, func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("slow"))
+	})
+	m.HandleFunc(metaMatcher{true}, func(res http.ResponseWriter, req *http.Request) {
+		if _, ok := Matched(req).(metaMatcher); !ok {
+			t.Errorf("expected metaMatcher, got: %T", Matched(req))
+		}
+		if v := Meta(req, "k"); v != "k" {
+			t.Errorf("expected %q, got: %v", "k", v)
+		}
+		res.Write([]byte("ok"))
+	})
+	start := time.Now()
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if s := res.Body.String(); s != "ok" {
+		t.Errorf("expected %q, got: %q", "ok", s)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("expected matching to be bounded, took: %v", d)
+	}
+
+	// without timeout
+	m = New(WithMatcherIsolation(0))
+	m.HandleFunc(slowMatcher{-1}, func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("ok"))
+	})
+	res = httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if s := res.Body.String(); s != "ok" {
+		t.Errorf("expected %q, got: %q", "ok", s)
+	}
+}
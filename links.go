@@ -0,0 +1,107 @@
+package goji
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// Link is a hypermedia link.
+type Link struct {
+	Href string `json:"href"`
+}
+
+// relation is a relation between named routes.
+type relation struct {
+	rel, target string
+}
+
+// Relate declares a relation from the named route to the target named route
+// (see WithName), used to build hypermedia links (see Links). The target's
+// variables are set from the request's route params of the same name.
+//
+// For example:
+//
+//	mux.Handle(goji.Get("/users/:id", goji.WithName("user")), showUser)
+//	mux.Handle(goji.Get("/users/:id/orders", goji.WithName("user_orders")), listOrders)
+//	mux.Relate("user", "orders", "user_orders")
+func (m *Mux) Relate(name, rel, target string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.rels == nil {
+		m.rels = make(map[string][]relation)
+	}
+	m.rels[name] = append(m.rels[name], relation{rel, target})
+}
+
+// Links returns the hypermedia links for the request's matched named route,
+// keyed by relation, including the "self" relation and the route's declared
+// relations (see Mux.Relate). Relations whose targets cannot be built from
+// the request's route params are omitted.
+func Links(req *http.Request) map[string]Link {
+	m, ok := req.Context().Value(muxKey).(*Mux)
+	name := RouteName(req)
+	if !ok || name == "" {
+		return nil
+	}
+	var params []string
+	for k, v := range Params(req) {
+		params = append(params, k, v)
+	}
+	prefix := ForwardedPrefix(req.Context())
+	links := make(map[string]Link)
+	if path, err := m.URL(name, params...); err == nil {
+		links["self"] = Link{Href: prefix + path}
+	}
+	m.mu.RLock()
+	rels := m.rels[name]
+	m.mu.RUnlock()
+	for _, r := range rels {
+		if path, err := m.URL(r.target, params...); err == nil {
+			links[r.rel] = Link{Href: prefix + path}
+		}
+	}
+	return links
+}
+
+// WithLinks returns a json.Marshaler that encodes v (which must encode as a
+// JSON object) with a "_links" object containing the request's hypermedia
+// links (see Links), keeping hypermedia APIs in sync with the route table:
+//
+//	render.JSON(res, req, http.StatusOK, goji.WithLinks(req, user))
+func WithLinks(req *http.Request, v interface{}) json.Marshaler {
+	return linked{v, Links(req)}
+}
+
+// linked is a JSON object with hypermedia links.
+type linked struct {
+	v     interface{}
+	links map[string]Link
+}
+
+// MarshalJSON satisfies the json.Marshaler interface.
+func (l linked) MarshalJSON() ([]byte, error) {
+	buf, err := json.Marshal(l.v)
+	if err != nil || len(l.links) == 0 {
+		return buf, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return nil, err
+	}
+	links, err := json.Marshal(l.links)
+	if err != nil {
+		return nil, err
+	}
+	// append _links, preserving the order of v's fields
+	buf = bytes.TrimRight(buf, " \t\r\n")
+	var b bytes.Buffer
+	b.Write(buf[:len(buf)-1])
+	if len(obj) != 0 {
+		b.WriteByte(',')
+	}
+	b.WriteString(`"_links":`)
+	b.Write(links)
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
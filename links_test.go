@@ -0,0 +1,40 @@
+package goji
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	m := New()
+	var buf []byte
+	m.HandleFunc(Get("/users/:id", WithName("user")), func(res http.ResponseWriter, req *http.Request) {
+		var err error
+		buf, err = json.Marshal(WithLinks(req, struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}{Param(req, "id"), "carl"}))
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+	m.HandleFunc(Get("/users/:id/orders", WithName("user_orders")), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Get("/orgs/:org", WithName("org")), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Get("/empty", WithName("empty")), func(res http.ResponseWriter, req *http.Request) {
+		buf, _ = json.Marshal(WithLinks(req, struct{}{}))
+	})
+	m.Relate("user", "orders", "user_orders")
+	m.Relate("user", "org", "org")
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/7", nil))
+	exp := `{"id":"7","name":"carl","_links":{"orders":{"href":"/users/7/orders"},"self":{"href":"/users/7"}}}`
+	if s := string(buf); s != exp {
+		t.Errorf("expected %s, got: %s", exp, s)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/empty", nil))
+	exp = `{"_links":{"self":{"href":"/empty"}}}`
+	if s := string(buf); s != exp {
+		t.Errorf("expected %s, got: %s", exp, s)
+	}
+}
@@ -0,0 +1,102 @@
+package goji
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+)
+
+// WithUnixSocket is a server option to serve on a unix domain socket at the
+// path, with the file permissions, instead of listening on the address. A
+// stale socket file at the path is removed prior to listening.
+//
+// Unix domain sockets are commonly used when serving behind a reverse proxy
+// such as nginx or caddy on the same host.
+func WithUnixSocket(path string, perm os.FileMode) ServerOption {
+	return func(s *server) {
+		s.server.listenf = func() (net.Listener, error) {
+			return listenUnix(path, perm)
+		}
+	}
+}
+
+// WithSystemdSocket is a server option to serve on the first listener
+// inherited via systemd socket activation (see SystemdListeners), instead of
+// listening on the address.
+func WithSystemdSocket() ServerOption {
+	return func(s *server) {
+		s.server.listenf = func() (net.Listener, error) {
+			listeners, err := SystemdListeners()
+			switch {
+			case err != nil:
+				return nil, err
+			case len(listeners) == 0:
+				return nil, ErrNoSystemdListeners
+			}
+			for _, l := range listeners[1:] {
+				l.Close()
+			}
+			return listeners[0], nil
+		}
+	}
+}
+
+// ErrNoSystemdListeners is the no systemd listeners error.
+var ErrNoSystemdListeners = errors.New("no systemd listeners")
+
+// listenFDsStart is the first file descriptor passed by systemd.
+var listenFDsStart = 3
+
+// SystemdListeners returns the listeners passed to the process via systemd
+// socket activation (the LISTEN_PID and LISTEN_FDS environment variables),
+// in order. The environment variables are unset, so that child processes do
+// not inherit them.
+//
+// Returns no listeners (and no error) when the process was not socket
+// activated.
+func SystemdListeners() ([]net.Listener, error) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+	var listeners []net.Listener
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// listenUnix listens on a unix domain socket at the path, with the file
+// permissions.
+func listenUnix(path string, perm os.FileMode) (net.Listener, error) {
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
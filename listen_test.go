@@ -0,0 +1,133 @@
+package goji
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestWithUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "goji")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "goji.sock")
+
+	// stale socket
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	defer serveTest(t, WithUnixSocket(path, 0660))()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0660 {
+		t.Errorf("expected perm 0660, got: %o", perm)
+	}
+	cl := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return new(net.Dialer).DialContext(ctx, "unix", path)
+			},
+		},
+	}
+	expectBody(t, cl, "http://unix/", "hello")
+}
+
+func TestWithSystemdSocket(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	// ownership of the duplicated descriptor is passed to SystemdListeners
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	addr := l.Addr().String()
+	f.Close()
+	l.Close()
+	defer func(start int) {
+		listenFDsStart = start
+	}(listenFDsStart)
+	listenFDsStart = fd
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	os.Setenv("LISTEN_FDS", "1")
+
+	defer serveTest(t, WithSystemdSocket())()
+	if v := os.Getenv("LISTEN_FDS"); v != "" {
+		t.Errorf("expected LISTEN_FDS to be unset, got: %q", v)
+	}
+	expectBody(t, http.DefaultClient, "http://"+addr+"/", "hello")
+}
+
+func TestSystemdListenersNotActivated(t *testing.T) {
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	os.Setenv("LISTEN_FDS", "1")
+	listeners, err := SystemdListeners()
+	if err != nil || len(listeners) != 0 {
+		t.Errorf("expected no listeners and no error, got: %v %v", listeners, err)
+	}
+	err = Run(context.Background(), "", New(), WithSystemdSocket())
+	if err == nil || err.Error() != ErrNoSystemdListeners.Error() {
+		t.Errorf("expected %v, got: %v", ErrNoSystemdListeners, err)
+	}
+}
+
+// serveTest runs a test server with the options, returning a func that
+// shuts down the server and waits for Run to return.
+func serveTest(t *testing.T, opts ...ServerOption) func() {
+	m := New()
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("hello"))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	started, done := make(chan bool), make(chan error, 1)
+	go func() {
+		done <- Run(ctx, "", m, append(opts, OnStart(func(context.Context) error {
+			close(started)
+			return nil
+		}))...)
+	}()
+	select {
+	case <-started:
+	case err := <-done:
+		cancel()
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	}
+}
+
+// expectBody checks that the url returns the expected body.
+func expectBody(t *testing.T, cl *http.Client, url, exp string) {
+	res, err := cl.Get(url)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if string(body) != exp {
+		t.Errorf("expected %q, got: %q", exp, body)
+	}
+}
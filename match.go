@@ -2,9 +2,13 @@ package goji
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -40,14 +44,19 @@ type matchContext struct {
 	context.Context
 	spec    *PathSpec
 	matches []string
+	typed   []interface{}
 }
 
+// typedKey is the context key type for the parsed values of typed named
+// matches.
+type typedKey nameKey
+
 func (m matchContext) Value(key interface{}) interface{} {
 	switch key {
 	case allNames:
 		var vs map[nameKey]interface{}
 		if vsi := m.Context.Value(key); vsi == nil {
-			if len(m.spec.specs) == 0 {
+			if len(m.spec.specs) == 0 && m.spec.wildcardName == "" {
 				return nil
 			}
 			vs = make(map[nameKey]interface{}, len(m.matches))
@@ -58,6 +67,9 @@ func (m matchContext) Value(key interface{}) interface{} {
 		for _, p := range m.spec.specs {
 			vs[p.name] = m.matches[p.idx]
 		}
+		if m.spec.wildcardName != "" {
+			vs[m.spec.wildcardName] = m.remainder()
+		}
 		return vs
 
 	case pathKey:
@@ -68,20 +80,51 @@ func (m matchContext) Value(key interface{}) interface{} {
 	}
 
 	if k, ok := key.(nameKey); ok {
-		i := sort.Search(len(m.spec.specs), func(i int) bool {
-			return m.spec.specs[i].name >= k
-		})
-		if i < len(m.spec.specs) && m.spec.specs[i].name == k {
-			return m.matches[m.spec.specs[i].idx]
+		if k == m.spec.wildcardName && k != "" {
+			return m.remainder()
+		}
+		if i, ok := m.spec.specs.index(k); ok {
+			return m.matches[i]
+		}
+	}
+
+	if k, ok := key.(typedKey); ok {
+		if i, ok := m.spec.specs.index(nameKey(k)); ok {
+			if m.typed == nil || m.typed[i] == nil {
+				return nil
+			}
+			return m.typed[i]
 		}
 	}
 
 	return m.Context.Value(key)
 }
 
+// remainder returns the unescaped unmatched suffix of a wildcard match,
+// without the leading slash.
+func (m matchContext) remainder() string {
+	s := strings.TrimPrefix(m.matches[len(m.matches)-1], "/")
+	if v, err := unescape(s); err == nil {
+		return v
+	}
+	return s
+}
+
 type pathSpecNames []struct {
 	name nameKey
 	idx  int
+	typ  string
+}
+
+// index returns the match index of the name.
+func (p pathSpecNames) index(name nameKey) (int, bool) {
+	i := sort.Search(len(p), func(i int) bool {
+		return p[i].name >= name
+	})
+	if i < len(p) && p[i].name == name {
+		return p[i].idx, true
+	}
+	return 0, false
 }
 
 func (p pathSpecNames) Len() int {
@@ -122,6 +165,9 @@ func (p pathSpecNames) Swap(i, j int) {
 // 				/user/carl
 // 				/user/carl/photos
 //
+// 	/user/:name?		/user			/user/
+// 				/user/carl
+//
 // Static Paths
 //
 // Most URL paths may be specified directly: the pattern "/hello" matches URLs with
@@ -169,9 +215,56 @@ func (p pathSpecNames) Swap(i, j int) {
 // leaving the path "/carl/photos" for subsequent patterns to handle. A subrouter
 // pattern for "/:name/photos" would match this remaining path segment, for
 // instance.
+//
+// Typed Matches
+//
+// Named matches may declare a type, which both restricts the match and records
+// the parsed value. For instance, the pattern "/orders/:id:int" matches
+// "/orders/42" but not "/orders/abc", and the parsed value is retrieved
+// (without re-parsing) with the ParamInt function. The "int", "uint", and
+// "uuid" types are supported (see ParamInt, ParamUint, and ParamUUID). For
+// compatibility, a name with an unknown type is treated as a literal name:
+// the pattern "/:a:b" binds the variable "a:b".
+//
+// Named Wildcards
+//
+// Prefix wildcards may be named, such as "*path" in the pattern
+// "/files/*path", binding the unmatched suffix, without the leading slash, to
+// the named variable. For instance, a request for "/files/docs/readme.txt"
+// binds "path" to "docs/readme.txt", which can be retrieved with the Param
+// function. The unmatched suffix is also placed into the request context, as
+// with unnamed wildcards.
+//
+// Optional Segments
+//
+// A named match followed by a "?" is optional, along with its preceding
+// delimiter: the pattern "/user/:name?" matches both "/user" and
+// "/user/carl", and the pattern "/:file.:ext?" matches both "/data" and
+// "/data.json". Parentheses are matched literally. Variables for omitted
+// segments are not bound, and as such Param panics for them (use Params to
+// check whether an optional variable was bound).
 type PathSpec struct {
 	raw     string
+	name    string
 	methods map[string]struct{}
+	meta    map[string]interface{}
+
+	// maxBody and contentTypes are the request body policy enforced by the
+	// Mux.
+	maxBody      int64
+	contentTypes []string
+
+	// cacheControl is the Cache-Control header applied by the Mux.
+	cacheControl string
+
+	// protos are the matching HTTP protocol major versions.
+	protos []int
+
+	// auth is the authentication policy enforced by the Mux.
+	auth *AuthPolicy
+
+	// warmUp is the warm-up gating the route.
+	warmUp *WarmUp
 
 	// specs are parallel arrays of each pattern string (sans ":"), the breaks
 	// each expect afterwords (used to support e.g., "." dividers), and the
@@ -180,11 +273,29 @@ type PathSpec struct {
 	// <pattern> <literal> <pattern> <literal> etc...
 	specs pathSpecNames
 
-	breaks   []byte
-	literals []string
-	wildcard bool
+	breaks       []byte
+	literals     []string
+	wildcard     bool
+	wildcardName nameKey
+
+	// delims are the delimiters, in addition to "/".
+	delims string
+
+	// nonGreedy is whether or not the last named match in a path segment ends
+	// at the first delimiter (see NonGreedy).
+	nonGreedy bool
+
+	// values are the allowed values of named matches.
+	values map[nameKey][]string
+
+	// alts are the path specs for each combination of optional segments,
+	// longest first.
+	alts []*PathSpec
 }
 
+// defaultDelims are the default delimiters, in addition to "/".
+const defaultDelims = ".;,"
+
 // breaksRE is a regexp for "Break characters" that can end patterns. They are
 // not allowed to appear in pattern names. "/" was chosen because it is the
 // standard path separator, and "." was chosen because it often delimits file
@@ -192,16 +303,59 @@ type PathSpec struct {
 // their use.
 var breaksRE = regexp.MustCompile(`[/.;,]:([^/.;,]+)`)
 
+// optionalRE is a regexp for named matches with a "?" suffix.
+var optionalRE = regexp.MustCompile(`([/.;,]:[^/.;,()?]+)\?`)
+
+// delimsRE returns the break and optional regexps for the delimiters (see
+// WithDelimiters).
+func delimsRE(delims string) (*regexp.Regexp, *regexp.Regexp) {
+	if delims == defaultDelims {
+		return breaksRE, optionalRE
+	}
+	// escape all delimiters, as "-", "^", and "]" are special in classes
+	set := `/`
+	for _, c := range delims {
+		if c != '/' {
+			set += `\x{` + strconv.FormatInt(int64(c), 16) + `}`
+		}
+	}
+	return regexp.MustCompile(`[` + set + `]:([^` + set + `]+)`),
+		regexp.MustCompile(`([` + set + `]:[^` + set + `()?]+)\?`)
+}
+
 // NewPathSpec returns a new PathSpec from the given path spec and options.
 func NewPathSpec(spec string, opts ...PathSpecOption) *PathSpec {
-	p := &PathSpec{raw: spec}
+	p := &PathSpec{
+		raw:    spec,
+		delims: defaultDelims,
+	}
 	for _, o := range opts {
 		o(p)
 	}
+	breaksRE, optionalRE := delimsRE(p.delims)
+
+	if alts := expandOptional(spec, optionalRE); len(alts) > 1 {
+		p.alts = make([]*PathSpec, len(alts))
+		prefix := ""
+		for i, alt := range alts {
+			p.alts[i] = NewPathSpec(alt, WithDelimiters(p.delims))
+			p.alts[i].nonGreedy, p.alts[i].values = p.nonGreedy, p.values
+			if i == 0 {
+				prefix = p.alts[i].Prefix()
+			} else {
+				prefix = commonPrefix(prefix, p.alts[i].Prefix())
+			}
+		}
+		p.literals = []string{prefix}
+		return p
+	}
 
 	if strings.HasSuffix(spec, "/*") {
 		spec = spec[:len(spec)-1]
 		p.wildcard = true
+	} else if i := strings.LastIndex(spec, "/*"); i != -1 && !strings.ContainsAny(spec[i+2:], "/:"+p.delims) {
+		spec, p.wildcardName = spec[:i+1], nameKey(spec[i+2:])
+		p.wildcard = true
 	}
 
 	matches := breaksRE.FindAllStringSubmatchIndex(spec, -1)
@@ -215,6 +369,11 @@ func NewPathSpec(spec string, opts ...PathSpecOption) *PathSpec {
 		a, b := match[2], match[3]
 		p.literals[i] = spec[n : a-1] // Need to leave off the colon
 		p.specs[i].name = nameKey(spec[a:b])
+		if name, typ, ok := strings.Cut(spec[a:b], ":"); ok && isIdent(name) {
+			if _, ok := paramTypes[typ]; ok {
+				p.specs[i].name, p.specs[i].typ = nameKey(name), typ
+			}
+		}
 		p.specs[i].idx = i
 		if b == len(spec) {
 			p.breaks[i] = '/'
@@ -238,6 +397,17 @@ func (p *PathSpec) Match(req *http.Request) *http.Request {
 			return nil
 		}
 	}
+	if p.protos != nil && !slices.Contains(p.protos, req.ProtoMajor) {
+		return nil
+	}
+	if p.alts != nil {
+		for _, alt := range p.alts {
+			if r := alt.Match(req); r != nil {
+				return r
+			}
+		}
+		return nil
+	}
 
 	// Check Path
 	ctx := req.Context()
@@ -258,8 +428,11 @@ func (p *PathSpec) Match(req *http.Request) *http.Request {
 
 		m := 0
 		bc := p.breaks[i]
+		if p.nonGreedy && bc != '/' {
+			m = p.lastBreak(path, i)
+		}
 		for ; m < len(path); m++ {
-			if path[m] == bc || path[m] == '/' {
+			if path[m] == bc || path[m] == '/' || p.nonGreedy && strings.IndexByte(p.delims, path[m]) != -1 {
 				break
 			}
 		}
@@ -294,8 +467,25 @@ func (p *PathSpec) Match(req *http.Request) *http.Request {
 			return nil
 		}
 	}
+	var typed []interface{}
+	for _, spec := range p.specs {
+		if values, ok := p.values[spec.name]; ok && !slices.Contains(values, scratch[spec.idx]) {
+			return nil
+		}
+		if spec.typ == "" {
+			continue
+		}
+		v, ok := paramTypes[spec.typ](scratch[spec.idx])
+		if !ok {
+			return nil
+		}
+		if typed == nil {
+			typed = make([]interface{}, len(scratch))
+		}
+		typed[spec.idx] = v
+	}
 
-	return req.WithContext(&matchContext{ctx, p, scratch})
+	return req.WithContext(&matchContext{ctx, p, scratch, typed})
 }
 
 // Methods returns the set of HTTP methods that this PathSpec matches.
@@ -308,6 +498,152 @@ func (p *PathSpec) Prefix() string {
 	return p.literals[0]
 }
 
+// Meta returns the route metadata value for key, or nil if not set.
+func (p *PathSpec) Meta(key string) interface{} {
+	return p.meta[key]
+}
+
+// MaxBody returns the maximum request body size for the path spec (see
+// WithMaxBody), or 0 when not limited.
+func (p *PathSpec) MaxBody() int64 {
+	return p.maxBody
+}
+
+// ContentTypes returns the allowed request body content types for the path
+// spec (see WithContentTypes), or nil when not restricted.
+func (p *PathSpec) ContentTypes() []string {
+	return p.contentTypes
+}
+
+// CacheControl returns the Cache-Control header value for the path spec (see
+// WithCacheControl).
+func (p *PathSpec) CacheControl() string {
+	return p.cacheControl
+}
+
+// Auth returns the authentication policy for the path spec (see WithAuth),
+// or nil when not set.
+func (p *PathSpec) Auth() *AuthPolicy {
+	return p.auth
+}
+
+// WarmUp returns the warm-up gating the path spec (see WithWarmUp), or nil
+// when not set.
+func (p *PathSpec) WarmUp() *WarmUp {
+	return p.warmUp
+}
+
+// Name returns the route name for the path spec (see WithName).
+func (p *PathSpec) Name() string {
+	return p.name
+}
+
+// URL builds the path for the path spec from the name and value pairs of the
+// path spec's named variables, escaping the values. For wildcard path specs,
+// the "*" name (or the wildcard's name) can be used to set the unmatched
+// suffix, escaping each of its segments.
+//
+// For example:
+//
+//	goji.Get("/user/:name").URL("name", "carl") // "/user/carl"
+func (p *PathSpec) URL(params ...string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", ErrInvalidParams
+	}
+	if p.alts != nil {
+		var err error
+		for _, alt := range p.alts {
+			var s string
+			if s, err = alt.URL(params...); err == nil {
+				return s, nil
+			}
+		}
+		return "", err
+	}
+	vals := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		vals[params[i]] = params[i+1]
+	}
+	names := make([]nameKey, len(p.specs))
+	for _, spec := range p.specs {
+		names[spec.idx] = spec.name
+	}
+	var sb strings.Builder
+	for i, name := range names {
+		v, ok := vals[string(name)]
+		if !ok || v == "" {
+			return "", fmt.Errorf("%w %q", ErrMissingParam, name)
+		}
+		sb.WriteString(p.literals[i])
+		sb.WriteString(url.PathEscape(v))
+	}
+	sb.WriteString(p.literals[len(names)])
+	if p.wildcard {
+		v, ok := vals[string(p.wildcardName)]
+		if !ok || p.wildcardName == "" {
+			v = vals["*"]
+		}
+		for i, seg := range strings.Split(strings.TrimPrefix(v, "/"), "/") {
+			if i != 0 {
+				sb.WriteByte('/')
+			}
+			sb.WriteString(url.PathEscape(seg))
+		}
+	}
+	return sb.String(), nil
+}
+
+// lastBreak returns the end of the non-greedy named match i in the path,
+// ending at the last break character in the path segment that leaves a break
+// character for each following named match in the segment with the same
+// break character, or 0 when there is no such break character.
+func (p *PathSpec) lastBreak(path string, i int) int {
+	bc, n := p.breaks[i], 1
+	for j := i + 1; j < len(p.specs) && !strings.Contains(p.literals[j], "/"); j++ {
+		if p.breaks[j] == bc {
+			n++
+		}
+	}
+	if end := strings.IndexByte(path, '/'); end != -1 {
+		path = path[:end]
+	}
+	for m := len(path) - 1; m > 0; m-- {
+		if path[m] == bc {
+			if n--; n == 0 {
+				return m
+			}
+		}
+	}
+	return 0
+}
+
+// expandOptional expands the optional named matches (see optionalRE) of the
+// spec, returning the specs for each combination of optional segments,
+// longest first.
+func expandOptional(spec string, optionalRE *regexp.Regexp) []string {
+	loc := optionalRE.FindStringSubmatchIndex(spec)
+	if loc == nil {
+		return []string{spec}
+	}
+	before, segment, after := spec[:loc[0]], spec[loc[2]:loc[3]], expandOptional(spec[loc[1]:], optionalRE)
+	var specs []string
+	for _, a := range after {
+		specs = append(specs, before+segment+a)
+	}
+	for _, a := range after {
+		specs = append(specs, before+a)
+	}
+	return specs
+}
+
+// commonPrefix returns the common prefix of a and b.
+func commonPrefix(a, b string) string {
+	i := 0
+	for ; i < len(a) && i < len(b) && a[i] == b[i]; i++ {
+	}
+	return a[:i]
+}
+
 // String satisfies fmt.Stringer interface.
 func (p *PathSpec) String() string {
 	return p.raw
@@ -327,38 +663,229 @@ func WithMethod(methods ...string) PathSpecOption {
 	}
 }
 
+// WithName is a path spec option to set the route name, used to build URLs
+// for the route (see Mux.URL and AbsoluteURL).
+func WithName(name string) PathSpecOption {
+	return func(p *PathSpec) {
+		p.name = name
+	}
+}
+
+// WithMaxBody is a path spec option to limit the size of request bodies to n
+// bytes. The Mux rejects requests with a Content-Length exceeding the limit
+// with 413 Request Entity Too Large, and reads beyond the limit return an
+// error.
+func WithMaxBody(n int64) PathSpecOption {
+	return func(p *PathSpec) {
+		p.maxBody = n
+	}
+}
+
+// WithContentTypes is a path spec option to restrict the media types (for
+// example, "application/json" or "image/*") of request bodies. The Mux
+// rejects requests with a body of any other type with 415 Unsupported Media
+// Type.
+func WithContentTypes(types ...string) PathSpecOption {
+	return func(p *PathSpec) {
+		p.contentTypes = types
+	}
+}
+
+// WithCacheControl is a path spec option to set the Cache-Control header
+// (for example, "public, max-age=300") applied by the Mux to successful (2xx)
+// responses to GET and HEAD requests, unless already set by the handler.
+func WithCacheControl(cacheControl string) PathSpecOption {
+	return func(p *PathSpec) {
+		p.cacheControl = cacheControl
+	}
+}
+
+// WithWarmUp is a path spec option to gate the route on the warm-up. Until
+// the warm-up has completed, the Mux responds to requests for the route with
+// 503 Service Unavailable and a Retry-After header.
+func WithWarmUp(w *WarmUp) PathSpecOption {
+	return func(p *PathSpec) {
+		p.warmUp = w
+	}
+}
+
+// WithProto is a path spec option to set the matching HTTP protocol major
+// versions (1 for HTTP/1.x, 2 for HTTP/2, and 3 for HTTP/3), allowing
+// handlers to be selected by protocol at the routing layer. For example, to
+// serve a long-polling fallback to HTTP/1.x clients:
+//
+//	mux.Handle(goji.Get("/events", goji.WithProto(2, 3)), stream)
+//	mux.Handle(goji.Get("/events", goji.WithProto(1)), poll)
+func WithProto(majors ...int) PathSpecOption {
+	return func(p *PathSpec) {
+		p.protos = majors
+	}
+}
+
+// WithAuth is a path spec option to declare the route's authentication
+// policy, enforced by the Mux before dispatch (see WithAuthenticator). When
+// required, requests without an authenticated principal are rejected with
+// 401 Unauthorized. When roles are specified, requests with a principal that
+// has none of the roles are rejected with 403 Forbidden.
+//
+// For example:
+//
+//	mux.Handle(goji.Delete("/users/:id", goji.WithAuth(true, "admin")), deleteUser)
+func WithAuth(required bool, roles ...string) PathSpecOption {
+	return func(p *PathSpec) {
+		p.auth = &AuthPolicy{
+			Required: required,
+			Roles:    roles,
+		}
+	}
+}
+
+// WithDelimiters is a path spec option to set the characters, in addition
+// to "/", that end named matches in the path spec (default ".;,"). Characters
+// that are not delimiters are part of the preceding name. For example, to
+// split "/:from-:to" on dashes:
+//
+//	goji.Get("/:from-:to", goji.WithDelimiters("-"))
+func WithDelimiters(delims string) PathSpecOption {
+	return func(p *PathSpec) {
+		p.delims = delims
+	}
+}
+
+// WithParamValues is a path spec option to restrict the named match to the
+// allowed values. Requests with any other value do not match, allowing
+// sibling routes to match on the value. For example:
+//
+//	mux.Handle(goji.Get("/export.:format", goji.WithParamValues("format", "json", "csv")), export)
+func WithParamValues(name string, values ...string) PathSpecOption {
+	return func(p *PathSpec) {
+		if p.values == nil {
+			p.values = make(map[nameKey][]string)
+		}
+		p.values[nameKey(name)] = values
+	}
+}
+
+// NonGreedy is a path spec option to make the path spec's named matches
+// non-greedy, so that the last named match in a path segment ends at the
+// first delimiter (see WithDelimiters), and preceding named matches in the
+// segment extend to the last possible delimiter. For instance, the pattern
+// "/:file.:ext" ordinarily matches "/data.tar.gz", binding "file" to "data"
+// and "ext" to "tar.gz", whereas with NonGreedy "ext" is always exactly the
+// last dot-suffix, binding "file" to "data.tar" and "ext" to "gz":
+//
+//	mux.Handle(goji.Get("/:file.:ext", goji.NonGreedy), h)
+func NonGreedy(p *PathSpec) {
+	p.nonGreedy = true
+}
+
+// WithMeta is a path spec option to attach a route metadata value to the path
+// spec. Metadata is available to middleware via the Meta func after routing.
+func WithMeta(key string, value interface{}) PathSpecOption {
+	return func(p *PathSpec) {
+		if p.meta == nil {
+			p.meta = make(map[string]interface{})
+		}
+		p.meta[key] = value
+	}
+}
+
+// Any returns a PathSpec that matches requests for all HTTP methods.
+func Any(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, opts...)
+}
+
+// Connect returns a PathSpec that matches requests for CONNECT HTTP method.
+func Connect(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("CONNECT")}, opts...)...)
+}
+
+// Copy returns a PathSpec that matches requests for COPY WebDAV method.
+func Copy(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("COPY")}, opts...)...)
+}
+
 // Delete returns a PathSpec that matches requests for DELETE HTTP method.
-func Delete(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("DELETE"))
+func Delete(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("DELETE")}, opts...)...)
 }
 
 // Get returns a PathSpec that matches requests for GET and HEAD HTTP method. HEAD
 // requests are handled transparently by net/http.
-func Get(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("GET", "HEAD"))
+func Get(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("GET", "HEAD")}, opts...)...)
 }
 
 // Head returns a PathSpec that matches requests for HEAD HTTP method.
-func Head(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("HEAD"))
+func Head(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("HEAD")}, opts...)...)
+}
+
+// Lock returns a PathSpec that matches requests for LOCK WebDAV method.
+func Lock(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("LOCK")}, opts...)...)
+}
+
+// Methods returns a PathSpec that matches requests for the HTTP methods. As
+// with Get, HEAD requests are also matched when the methods include GET.
+func Methods(methods []string, spec string, opts ...PathSpecOption) *PathSpec {
+	if slices.Contains(methods, "GET") && !slices.Contains(methods, "HEAD") {
+		methods = append(methods[:len(methods):len(methods)], "HEAD")
+	}
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod(methods...)}, opts...)...)
+}
+
+// Mkcol returns a PathSpec that matches requests for MKCOL WebDAV method.
+func Mkcol(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("MKCOL")}, opts...)...)
+}
+
+// Move returns a PathSpec that matches requests for MOVE WebDAV method.
+func Move(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("MOVE")}, opts...)...)
 }
 
 // Options returns a PathSpec that matches requests for OPTIONS HTTP method.
-func Options(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("OPTIONS"))
+func Options(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("OPTIONS")}, opts...)...)
 }
 
 // Patch returns a PathSpec that matches requests for PATCH HTTP method.
-func Patch(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("PATCH"))
+func Patch(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("PATCH")}, opts...)...)
 }
 
 // Post returns a PathSpec that matches requests for POST HTTP method.
-func Post(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("POST"))
+func Post(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("POST")}, opts...)...)
+}
+
+// Propfind returns a PathSpec that matches requests for PROPFIND WebDAV method.
+func Propfind(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("PROPFIND")}, opts...)...)
+}
+
+// Proppatch returns a PathSpec that matches requests for PROPPATCH WebDAV method.
+func Proppatch(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("PROPPATCH")}, opts...)...)
 }
 
 // Put returns a PathSpec that matches requests for PUT HTTP method.
-func Put(spec string) *PathSpec {
-	return NewPathSpec(spec, WithMethod("PUT"))
+func Put(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("PUT")}, opts...)...)
+}
+
+// Report returns a PathSpec that matches requests for REPORT WebDAV method.
+func Report(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("REPORT")}, opts...)...)
+}
+
+// Trace returns a PathSpec that matches requests for TRACE HTTP method.
+func Trace(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("TRACE")}, opts...)...)
+}
+
+// Unlock returns a PathSpec that matches requests for UNLOCK WebDAV method.
+func Unlock(spec string, opts ...PathSpecOption) *PathSpec {
+	return NewPathSpec(spec, append([]PathSpecOption{WithMethod("UNLOCK")}, opts...)...)
 }
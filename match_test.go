@@ -2,8 +2,11 @@ package goji
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -162,6 +165,279 @@ func TestNewWithMethod(t *testing.T) {
 	}
 }
 
+func TestWithProto(t *testing.T) {
+	p := Get("/", WithProto(2, 3))
+	for _, test := range []struct {
+		major int
+		exp   bool
+	}{
+		{1, false},
+		{2, true},
+		{3, true},
+	} {
+		req := reqPath("GET", "/")
+		req.ProtoMajor = test.major
+		if matched := p.Match(req) != nil; matched != test.exp {
+			t.Errorf("expected HTTP/%d match %t, got: %t", test.major, test.exp, matched)
+		}
+	}
+}
+
+func TestOptionalSegments(t *testing.T) {
+	tests := []struct {
+		spec   string
+		path   string
+		prefix string
+		params map[string]string
+	}{
+		{"/articles/:year/:month?/:day?", "/articles/2024", "/articles/", map[string]string{"year": "2024"}},
+		{"/articles/:year/:month?/:day?", "/articles/2024/05", "/articles/", map[string]string{"year": "2024", "month": "05"}},
+		{"/articles/:year/:month?/:day?", "/articles/2024/05/17", "/articles/", map[string]string{"year": "2024", "month": "05", "day": "17"}},
+		{"/articles/:year/:month?/:day?", "/articles/2024/", "/articles/", nil},
+		{"/articles/:year/:month?/:day?", "/articles/2024/05/17/x", "/articles/", nil},
+		{"/user/:name?", "/user", "/user", map[string]string{}},
+		{"/user/:name?", "/user/carl", "/user", map[string]string{"name": "carl"}},
+		{"/user/:name?", "/user/", "/user", nil},
+		{"/:file.:ext?", "/data.json", "/", map[string]string{"file": "data", "ext": "json"}},
+		{"/:file.:ext?", "/data", "/", map[string]string{"file": "data"}},
+		{"/wiki/Foo_(bar)", "/wiki/Foo_(bar)", "/wiki/Foo_(bar)", map[string]string{}},
+		{"/wiki/Foo_(bar)", "/wiki/Foo_", "/wiki/Foo_(bar)", nil},
+	}
+	for i, test := range tests {
+		p := NewPathSpec(test.spec)
+		if s := p.Prefix(); s != test.prefix {
+			t.Errorf("test %d expected prefix %q, got: %q", i, test.prefix, s)
+		}
+		r := p.Match(reqPath("GET", test.path))
+		if (r != nil) != (test.params != nil) {
+			t.Errorf("test %d expected match %t, got: %t", i, test.params != nil, r != nil)
+			continue
+		}
+		if r == nil {
+			continue
+		}
+		params := Params(r)
+		if len(params) != len(test.params) {
+			t.Errorf("test %d expected params %v, got: %v", i, test.params, params)
+		}
+		for k, v := range test.params {
+			if params[k] != v {
+				t.Errorf("test %d expected param %q to be %q, got: %q", i, k, v, params[k])
+			}
+		}
+	}
+	p := Get("/articles/:year/:month?")
+	if p.Match(reqPath("POST", "/articles/2024")) != nil {
+		t.Errorf("pattern was GET, but matched POST")
+	}
+	for _, test := range []struct {
+		params []string
+		exp    string
+	}{
+		{[]string{"year", "2024"}, "/articles/2024"},
+		{[]string{"year", "2024", "month", "05"}, "/articles/2024/05"},
+	} {
+		if s, err := p.URL(test.params...); err != nil || s != test.exp {
+			t.Errorf("expected %q, got: %q (%v)", test.exp, s, err)
+		}
+	}
+	if _, err := p.URL("month", "05"); !errors.Is(err, ErrMissingParam) {
+		t.Errorf("expected ErrMissingParam, got: %v", err)
+	}
+}
+
+func TestNamedWildcard(t *testing.T) {
+	tests := []struct {
+		spec string
+		path string
+		exp  string
+		rest string
+	}{
+		{"/files/*path", "/files/docs/readme.txt", "docs/readme.txt", "/docs/readme.txt"},
+		{"/files/*path", "/files/", "", "/"},
+		{"/files/*path", "/files/a%20b", "a b", "/a%20b"},
+		{"/users/:id/*rest", "/users/7/photos/1", "photos/1", "/photos/1"},
+	}
+	for i, test := range tests {
+		r := NewPathSpec(test.spec).Match(reqPath("GET", test.path))
+		if r == nil {
+			t.Errorf("test %d expected match", i)
+			continue
+		}
+		name := test.spec[strings.LastIndex(test.spec, "*")+1:]
+		if s := Param(r, name); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+		if s := Params(r)[name]; s != test.exp {
+			t.Errorf("test %d expected params %q, got: %q", i, test.exp, s)
+		}
+		if s := Path(r.Context()); s != test.rest {
+			t.Errorf("test %d expected path %q, got: %q", i, test.rest, s)
+		}
+	}
+	if r := NewPathSpec("/files/*path").Match(reqPath("GET", "/files")); r != nil {
+		t.Errorf("expected no match")
+	}
+	if s, err := NewPathSpec("/files/*path").URL("path", "docs/readme.txt"); err != nil || s != "/files/docs/readme.txt" {
+		t.Errorf("expected %q, got: %q (%v)", "/files/docs/readme.txt", s, err)
+	}
+}
+
+func TestWithDelimiters(t *testing.T) {
+	tests := []struct {
+		spec   string
+		delims string
+		path   string
+		params map[string]string
+	}{
+		{"/:from-:to", "-", "/a-b", map[string]string{"from": "a", "to": "b"}},
+		{"/:from-:to", "-.", "/a.x-b.y", map[string]string{"from": "a.x", "to": "b.y"}},
+		{"/:from-:to", "", "/a-b", map[string]string{"from-:to": "a-b"}},
+		{"/:file.:ext", "", "/a.json", map[string]string{"file.:ext": "a.json"}},
+		{"/:a~:b", "~", "/x~y", map[string]string{"a": "x", "b": "y"}},
+		{"/:from-:to?", "-", "/a", map[string]string{"from": "a"}},
+	}
+	for i, test := range tests {
+		r := NewPathSpec(test.spec, WithDelimiters(test.delims)).Match(reqPath("GET", test.path))
+		if r == nil {
+			t.Errorf("test %d expected match", i)
+			continue
+		}
+		if params := Params(r); !reflect.DeepEqual(params, test.params) {
+			t.Errorf("test %d expected %v, got: %v", i, test.params, params)
+		}
+	}
+	// special characters in classes
+	breaks, _ := delimsRE("^]-")
+	if s := breaks.FindAllString("/:a^:b]:c-:d", -1); len(s) != 4 {
+		t.Errorf("expected 4 names, got: %v", s)
+	}
+}
+
+func TestNonGreedy(t *testing.T) {
+	tests := []struct {
+		spec   string
+		opts   []PathSpecOption
+		path   string
+		params map[string]string
+	}{
+		{"/:file.:ext", nil, "/data.tar.gz", map[string]string{"file": "data", "ext": "tar.gz"}},
+		{"/:file.:ext", []PathSpecOption{NonGreedy}, "/data.tar.gz", map[string]string{"file": "data.tar", "ext": "gz"}},
+		{"/:file.:ext/raw", []PathSpecOption{NonGreedy}, "/data.tar.gz/raw", map[string]string{"file": "data.tar", "ext": "gz"}},
+		{"/:file.:ext", []PathSpecOption{NonGreedy}, "/data", nil},
+		{"/:a.:b.:c", []PathSpecOption{NonGreedy}, "/x.y.z", map[string]string{"a": "x", "b": "y", "c": "z"}},
+		{"/:a.:b.:c", []PathSpecOption{NonGreedy}, "/w.x.y.z", map[string]string{"a": "w.x", "b": "y", "c": "z"}},
+		{"/:from-:to.:ext", []PathSpecOption{NonGreedy, WithDelimiters("-.")}, "/a-b-c.tar.gz", map[string]string{"from": "a-b", "to": "c.tar", "ext": "gz"}},
+		{"/:file.:ext", []PathSpecOption{NonGreedy}, "/data.json", map[string]string{"file": "data", "ext": "json"}},
+		{"/:a;:b", []PathSpecOption{NonGreedy}, "/x;y,z", nil},
+		{"/:name", []PathSpecOption{NonGreedy}, "/data.json", nil},
+		{"/:name", []PathSpecOption{NonGreedy, WithDelimiters("")}, "/data.json", map[string]string{"name": "data.json"}},
+		{"/:from-:to.:ext?", []PathSpecOption{NonGreedy, WithDelimiters("-.")}, "/a-b.json", map[string]string{"from": "a", "to": "b", "ext": "json"}},
+	}
+	for i, test := range tests {
+		r := NewPathSpec(test.spec, test.opts...).Match(reqPath("GET", test.path))
+		if (r != nil) != (test.params != nil) {
+			t.Errorf("test %d expected match %t, got: %t", i, test.params != nil, r != nil)
+			continue
+		}
+		if r == nil {
+			continue
+		}
+		if params := Params(r); !reflect.DeepEqual(params, test.params) {
+			t.Errorf("test %d expected %v, got: %v", i, test.params, params)
+		}
+	}
+}
+
+func TestWithParamValues(t *testing.T) {
+	p := Get("/export.:format", WithParamValues("format", "json", "csv"))
+	for _, test := range []struct {
+		path string
+		exp  bool
+	}{
+		{"/export.json", true},
+		{"/export.csv", true},
+		{"/export.xml", false},
+		{"/export.", false},
+	} {
+		if matched := p.Match(reqPath("GET", test.path)) != nil; matched != test.exp {
+			t.Errorf("expected %q match %t, got: %t", test.path, test.exp, matched)
+		}
+	}
+	// sibling routes
+	m := New()
+	m.HandleFunc(p, func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("export"))
+	})
+	m.HandleFunc(Get("/export.:format"), func(res http.ResponseWriter, req *http.Request) {
+		http.Error(res, "unsupported format", http.StatusBadRequest)
+	})
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, reqPath("GET", "/export.xml"))
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got: %d", http.StatusBadRequest, res.Code)
+	}
+}
+
+func TestAny(t *testing.T) {
+	p := Any("/", WithName("any"))
+	for _, method := range []string{"GET", "POST", "LOCK"} {
+		if p.Match(reqPath(method, "/")) == nil {
+			t.Errorf("pattern didn't match %s", method)
+		}
+	}
+	if name := p.Name(); name != "any" {
+		t.Errorf("expected any, got: %q", name)
+	}
+}
+
+func TestMethodsSpec(t *testing.T) {
+	tests := []struct {
+		methods []string
+		exp     map[string]struct{}
+	}{
+		{[]string{"POST", "PUT"}, map[string]struct{}{"POST": {}, "PUT": {}}},
+		{[]string{"GET", "POST"}, map[string]struct{}{"GET": {}, "HEAD": {}, "POST": {}}},
+		{[]string{"GET", "HEAD"}, map[string]struct{}{"GET": {}, "HEAD": {}}},
+	}
+	for i, test := range tests {
+		p := Methods(test.methods, "/")
+		if methods := p.Methods(); !reflect.DeepEqual(methods, test.exp) {
+			t.Errorf("test %d expected %v, got: %v", i, test.exp, methods)
+		}
+		if p.Match(reqPath("DELETE", "/")) != nil {
+			t.Errorf("test %d pattern matched DELETE", i)
+		}
+	}
+}
+
+func TestMethodSpecs(t *testing.T) {
+	tests := []struct {
+		f      func(string, ...PathSpecOption) *PathSpec
+		method string
+	}{
+		{Connect, "CONNECT"},
+		{Copy, "COPY"},
+		{Lock, "LOCK"},
+		{Mkcol, "MKCOL"},
+		{Move, "MOVE"},
+		{Propfind, "PROPFIND"},
+		{Proppatch, "PROPPATCH"},
+		{Report, "REPORT"},
+		{Trace, "TRACE"},
+		{Unlock, "UNLOCK"},
+	}
+	for i, test := range tests {
+		p := test.f("/")
+		if p.Match(reqPath("GET", "/")) != nil {
+			t.Errorf("test %d pattern was %s, but matched GET", i, test.method)
+		}
+		if p.Match(reqPath(test.method, "/")) == nil {
+			t.Errorf("test %d pattern didn't match %s", i, test.method)
+		}
+	}
+}
+
 func TestDelete(t *testing.T) {
 	p := Delete("/")
 	if p.Match(reqPath("GET", "/")) != nil {
@@ -290,3 +566,27 @@ func reqPath(method, path string) *http.Request {
 	}
 	return req.WithContext(WithPath(context.Background(), req.URL.EscapedPath()))
 }
+
+func TestWithMeta(t *testing.T) {
+	p := NewPathSpec("/", WithMeta("a", 1), WithMeta("b", "two"))
+	if v := p.Meta("a"); v != 1 {
+		t.Errorf("expected a=1, got: %v", v)
+	}
+	if v := p.Meta("b"); v != "two" {
+		t.Errorf("expected b=two, got: %v", v)
+	}
+	if v := NewPathSpec("/").Meta("a"); v != nil {
+		t.Errorf("expected nil, got: %v", v)
+	}
+}
+
+func TestParams(t *testing.T) {
+	if params := Params(reqPath("GET", "/")); params != nil {
+		t.Errorf("expected nil, got: %v", params)
+	}
+	req := NewPathSpec("/:file.:ext").Match(reqPath("GET", "/data.json"))
+	exp := map[string]string{"file": "data", "ext": "json"}
+	if params := Params(req); !reflect.DeepEqual(params, exp) {
+		t.Errorf("expected %v, got: %v", exp, params)
+	}
+}
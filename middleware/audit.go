@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// AuditKey is the route metadata key used to tag routes for auditing (see
+// Audit). The value must be true.
+//
+// For example:
+//
+//	mux.Handle(goji.Delete("/users/:id", goji.WithMeta(middleware.AuditKey, true)), h)
+const AuditKey = "audit"
+
+// Redacted is the value substituted for redacted params in audit records.
+const Redacted = goji.Redacted
+
+// AuditRecord is an audit record.
+type AuditRecord struct {
+	// Time is the time the request started.
+	Time time.Time
+	// Principal is the authenticated principal (see goji.Principal).
+	Principal interface{}
+	// Method is the request method.
+	Method string
+	// Path is the request path.
+	Path string
+	// Route is the matched route template (see goji.RouteTemplate).
+	Route string
+	// Name is the matched route name (see goji.RouteName).
+	Name string
+	// Params are the bound route params, with sensitive params redacted
+	// (see WithAuditRedact and goji.RedactParams).
+	Params map[string]string
+	// Status is the response status.
+	Status int
+	// Duration is the duration of the request.
+	Duration time.Duration
+}
+
+// AuditSink is the interface for audit record sinks.
+type AuditSink interface {
+	// Audit records the audit record.
+	Audit(context.Context, AuditRecord)
+}
+
+// AuditSinkFunc is an AuditSink func.
+type AuditSinkFunc func(context.Context, AuditRecord)
+
+// Audit satisfies the AuditSink interface.
+func (f AuditSinkFunc) Audit(ctx context.Context, r AuditRecord) {
+	f(ctx, r)
+}
+
+// Audit returns a middleware that records an audit record to the sink for
+// each request to a route tagged for auditing (see AuditKey), without
+// per-handler code. When the sink is nil, audit records are logged using the
+// default slog.Logger.
+//
+// Audit must be used with Mux.Use, as it relies on the matched route, and
+// after any authentication middleware, for the principal to be recorded.
+func Audit(sink AuditSink, opts ...AuditOption) func(http.Handler) http.Handler {
+	a := &audit{
+		sink:   sink,
+		redact: make(map[string]bool),
+	}
+	if a.sink == nil {
+		a.sink = AuditSinkFunc(LogAudit)
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	return a.handler
+}
+
+// audit is the audit logger.
+type audit struct {
+	sink   AuditSink
+	redact map[string]bool
+}
+
+// handler satisfies the middleware signature.
+func (a *audit) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if v, _ := goji.Meta(req, AuditKey).(bool); !v {
+			next.ServeHTTP(res, req)
+			return
+		}
+		start := time.Now()
+		w := &goji.StatusWriter{ResponseWriter: res}
+		defer func() {
+			// record panicking requests as failed, and continue panicking
+			v := recover()
+			status := w.Status()
+			if v != nil && !w.Written() {
+				status = http.StatusInternalServerError
+			}
+			params, path := goji.Params(req), req.URL.Path
+			for name := range params {
+				if a.redact[name] || goji.IsRedacted(name) {
+					params[name], path = Redacted, goji.RouteTemplate(req)
+				}
+			}
+			a.sink.Audit(req.Context(), AuditRecord{
+				Time:      start,
+				Principal: goji.Principal(req),
+				Method:    req.Method,
+				Path:      path,
+				Route:     goji.RouteTemplate(req),
+				Name:      goji.RouteName(req),
+				Params:    params,
+				Status:    status,
+				Duration:  time.Since(start),
+			})
+			if v != nil {
+				panic(v)
+			}
+		}()
+		next.ServeHTTP(w, req)
+	})
+}
+
+// LogAudit logs the audit record using the default slog.Logger.
+func LogAudit(ctx context.Context, r AuditRecord) {
+	attrs := []any{
+		"principal", r.Principal,
+		"method", r.Method,
+		"path", r.Path,
+		"route", r.Route,
+		"params", r.Params,
+		"status", r.Status,
+		"duration", r.Duration,
+	}
+	if r.Name != "" {
+		attrs = append(attrs, "route_name", r.Name)
+	}
+	slog.InfoContext(ctx, "audit", attrs...)
+}
+
+// AuditOption is an audit logger option.
+type AuditOption func(*audit)
+
+// WithAuditRedact is an audit logger option to redact the values of the
+// named route params (such as tokens or personal identifiers) in audit
+// records, in addition to the params registered with goji.RedactParams. As
+// the request path contains the param values, the path of requests with
+// redacted params is replaced with the route template.
+func WithAuditRedact(names ...string) AuditOption {
+	return func(a *audit) {
+		for _, name := range names {
+			a.redact[name] = true
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestAudit(t *testing.T) {
+	var records []AuditRecord
+	sink := AuditSinkFunc(func(_ context.Context, r AuditRecord) {
+		records = append(records, r)
+	})
+	m := goji.New()
+	m.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			h.ServeHTTP(res, req.WithContext(goji.WithPrincipal(req.Context(), "alice")))
+		})
+	})
+	m.Use(Audit(sink, WithAuditRedact("token")))
+	m.HandleFunc(goji.Get("/public"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(goji.Delete("/users/:id", goji.WithMeta(AuditKey, true), goji.WithName("user")), func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusNoContent)
+	})
+	m.HandleFunc(goji.Post("/reset/:token", goji.WithMeta(AuditKey, true)), func(http.ResponseWriter, *http.Request) {
+		panic("reset failed")
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/public", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/users/42", nil))
+	func() {
+		defer func() {
+			if v := recover(); v != "reset failed" {
+				t.Errorf("expected panic, got: %v", v)
+			}
+		}()
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/reset/secret", nil))
+	}()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got: %d", len(records))
+	}
+	r := records[0]
+	if r.Principal != "alice" || r.Route != "/users/:id" || r.Name != "user" || r.Path != "/users/42" || r.Params["id"] != "42" || r.Status != http.StatusNoContent {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	r = records[1]
+	if r.Path != "/reset/:token" || r.Params["token"] != Redacted || r.Status != http.StatusInternalServerError {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
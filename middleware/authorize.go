@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kenshaw/goji"
+)
+
+// AuthorizationError is the structured error written by Authorize when a
+// request is denied.
+type AuthorizationError struct {
+	Status  int    `json:"status"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Route   string `json:"route,omitempty"`
+}
+
+// Authorize returns a middleware that allows or denies requests using the
+// policy func, which is passed the authenticated principal (see
+// goji.Principal) set by authentication middleware (JWT, OIDC, API keys,
+// ...), and the matched route (see goji.MatchedRoute), including its
+// metadata and authentication policy.
+//
+// When the policy returns an error, the request is denied with a JSON
+// encoded AuthorizationError and 403 Forbidden, or the status of the error
+// when it has a status (see goji.ErrorStatus and goji.StatusError).
+//
+// For example, to require the route's "permission" metadata:
+//
+//	mux.Use(middleware.Authorize(func(principal any, route goji.RouteInfo) error {
+//		perm, _ := route.Meta("permission").(string)
+//		if perm != "" && !principal.(*User).Can(perm) {
+//			return fmt.Errorf("missing permission %q", perm)
+//		}
+//		return nil
+//	}))
+//
+// Authorize must be used with Mux.Use, as it relies on the matched route.
+func Authorize(policy func(principal any, route goji.RouteInfo) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			route := goji.MatchedRoute(req)
+			if err := policy(goji.Principal(req), route); err != nil {
+				status := http.StatusForbidden
+				var se interface{ StatusCode() int }
+				if errors.As(err, &se) {
+					status = se.StatusCode()
+				}
+				res.Header().Set("Content-Type", "application/json; charset=utf-8")
+				res.Header().Set("X-Content-Type-Options", "nosniff")
+				res.WriteHeader(status)
+				_ = json.NewEncoder(res).Encode(AuthorizationError{
+					Status:  status,
+					Error:   http.StatusText(status),
+					Message: err.Error(),
+					Route:   route.Template,
+				})
+				return
+			}
+			next.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestAuthorize(t *testing.T) {
+	m := goji.New()
+	m.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if user := req.Header.Get("X-User"); user != "" {
+				req = req.WithContext(goji.WithPrincipal(req.Context(), user))
+			}
+			h.ServeHTTP(res, req)
+		})
+	})
+	m.Use(Authorize(func(principal any, route goji.RouteInfo) error {
+		owner, _ := route.Meta("owner").(string)
+		switch {
+		case owner == "":
+			return nil
+		case principal == nil:
+			return &goji.StatusError{Status: http.StatusUnauthorized, Err: errors.New("not authenticated")}
+		case principal != owner && principal != route.Params["name"]:
+			return fmt.Errorf("%v is not %s", principal, owner)
+		}
+		return nil
+	}))
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(goji.Get("/public"), h)
+	m.HandleFunc(goji.Get("/users/:name", goji.WithMeta("owner", "admin")), h)
+	tests := []struct {
+		path    string
+		user    string
+		status  int
+		message string
+	}{
+		{"/public", "", http.StatusOK, ""},
+		{"/users/bob", "", http.StatusUnauthorized, "not authenticated"},
+		{"/users/bob", "bob", http.StatusOK, ""},
+		{"/users/bob", "admin", http.StatusOK, ""},
+		{"/users/bob", "eve", http.StatusForbidden, "eve is not admin"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		if test.user != "" {
+			req.Header.Set("X-User", test.user)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if test.message == "" {
+			continue
+		}
+		var v AuthorizationError
+		if err := json.Unmarshal(res.Body.Bytes(), &v); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if v.Status != test.status || v.Message != test.message || v.Route != "/users/:name" {
+			t.Errorf("test %d expected status %d, message %q, got: %+v", i, test.status, test.message, v)
+		}
+	}
+}
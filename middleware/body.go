@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBodyTimeout is the request body read timeout error.
+var ErrBodyTimeout = errors.New("request body read timeout")
+
+// BodyTimeout returns a middleware that enforces a deadline on reading the
+// request body, distinct from the total time spent handling the request.
+//
+// When the request body is not fully read within the timeout, reads of the
+// body return ErrBodyTimeout, and the request is aborted with a 408 Request
+// Timeout response (the connection is closed), protecting the server from
+// slow-loris style uploads. Once the body has been read, the deadline is
+// cleared, leaving long-running responses unaffected.
+//
+// The read-header timeout is enforced by the server (see
+// goji.WithReadHeaderTimeout).
+func BodyTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if req.Body == nil || req.Body == http.NoBody {
+				next.ServeHTTP(res, req)
+				return
+			}
+			rc := http.NewResponseController(res)
+			deadline := time.Now().Add(timeout)
+			_ = rc.SetReadDeadline(deadline)
+			b := &timeoutBody{
+				ReadCloser: req.Body,
+				deadline:   deadline,
+				clear: func() {
+					_ = rc.SetReadDeadline(time.Time{})
+				},
+			}
+			req.Body = b
+			w := &timeoutWriter{ResponseWriter: res, body: b}
+			next.ServeHTTP(w, req)
+			if b.timedOut() && !w.written() {
+				w.WriteHeader(http.StatusRequestTimeout)
+			}
+		})
+	}
+}
+
+// timeoutBody is a request body with a read deadline.
+type timeoutBody struct {
+	io.ReadCloser
+	deadline time.Time
+	clear    func()
+	done     bool
+	timeout  int32
+}
+
+// Read satisfies the io.Reader interface.
+func (b *timeoutBody) Read(buf []byte) (int, error) {
+	if b.timedOut() || (!b.done && time.Now().After(b.deadline)) {
+		atomic.StoreInt32(&b.timeout, 1)
+		return 0, ErrBodyTimeout
+	}
+	n, err := b.ReadCloser.Read(buf)
+	switch {
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		atomic.StoreInt32(&b.timeout, 1)
+		return n, ErrBodyTimeout
+	case err == io.EOF && !b.done:
+		b.done = true
+		b.clear()
+	}
+	return n, err
+}
+
+// timedOut returns whether or not the body read timed out.
+func (b *timeoutBody) timedOut() bool {
+	return atomic.LoadInt32(&b.timeout) != 0
+}
+
+// timeoutWriter is a http.ResponseWriter that responds with 408 Request
+// Timeout when the request body read timed out.
+type timeoutWriter struct {
+	http.ResponseWriter
+	body    *timeoutBody
+	mu      sync.Mutex
+	wrote   bool
+	aborted bool
+}
+
+// WriteHeader satisfies the http.ResponseWriter interface.
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writeHeader(code)
+}
+
+// writeHeader writes the response header, once. The lock must be held.
+func (w *timeoutWriter) writeHeader(code int) {
+	if w.wrote {
+		return
+	}
+	w.wrote = true
+	if w.body.timedOut() {
+		w.aborted = true
+		w.Header().Set("Connection", "close")
+		http.Error(w.ResponseWriter, http.StatusText(http.StatusRequestTimeout), http.StatusRequestTimeout)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write satisfies the http.ResponseWriter interface.
+func (w *timeoutWriter) Write(buf []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writeHeader(http.StatusOK)
+	if w.aborted {
+		return len(buf), nil
+	}
+	return w.ResponseWriter.Write(buf)
+}
+
+// Flush satisfies the http.Flusher interface.
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writeHeader(http.StatusOK)
+	if w.aborted {
+		return
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack satisfies the http.Hijacker interface.
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wrote = true
+	}
+	return conn, rw, err
+}
+
+// written returns whether or not the response header has been written.
+func (w *timeoutWriter) written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wrote
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
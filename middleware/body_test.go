@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBodyTimeout(t *testing.T) {
+	ts := httptest.NewServer(BodyTimeout(50 * time.Millisecond)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.ReadAll(req.Body); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// long running response after the body is read
+		time.Sleep(100 * time.Millisecond)
+		res.Write([]byte("done"))
+	})))
+	defer ts.Close()
+
+	tests := []struct {
+		body   string
+		slow   bool
+		status int
+	}{
+		{"hello", false, http.StatusOK},
+		{"hello", true, http.StatusRequestTimeout},
+	}
+	for i, test := range tests {
+		conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		defer conn.Close()
+		if _, err := io.WriteString(conn, "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 5\r\n\r\n"); err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		if test.slow {
+			io.WriteString(conn, test.body[:2])
+			time.Sleep(100 * time.Millisecond)
+			io.WriteString(conn, test.body[2:])
+		} else {
+			io.WriteString(conn, test.body)
+		}
+		res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.StatusCode)
+		}
+	}
+}
+
+func TestBodyTimeoutUnwritten(t *testing.T) {
+	h := BodyTimeout(time.Millisecond)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		if _, err := req.Body.Read(make([]byte, 1)); err != ErrBodyTimeout {
+			t.Errorf("expected %v, got: %v", ErrBodyTimeout, err)
+		}
+	}))
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("POST", "/", strings.NewReader("hello")))
+	if res.Code != http.StatusRequestTimeout {
+		t.Errorf("expected %d, got: %d", http.StatusRequestTimeout, res.Code)
+	}
+}
+
+func TestBodyTimeoutFlush(t *testing.T) {
+	h := BodyTimeout(time.Second)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		res.Write([]byte("hello"))
+		if err := http.NewResponseController(res).Flush(); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	}))
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("POST", "/", strings.NewReader("hello")))
+	if !res.Flushed || res.Body.String() != "hello" {
+		t.Errorf("expected flushed response, got: %t %q", res.Flushed, res.Body.String())
+	}
+}
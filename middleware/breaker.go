@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// Breaker states.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// Breaker is a per-route circuit breaking middleware.
+//
+// A route's circuit is opened after the configured number of consecutive
+// failed (5xx) responses, after which the route's requests are rejected with
+// 503 Service Unavailable until the cooldown elapses. Once the cooldown
+// elapses, the circuit is half-open and a single probe request is allowed
+// through: when the probe succeeds the circuit is closed, otherwise it is
+// opened again. Only the probe changes the state of a half-open circuit, so
+// requests admitted before the circuit opened do not affect it. The
+// responses of streaming requests (see IsStreaming) are not tracked.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*breakerRoute
+}
+
+// NewBreaker creates a new per-route circuit breaker that opens after
+// threshold consecutive failures, for the cooldown.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		now:       time.Now,
+		routes:    make(map[string]*breakerRoute),
+	}
+}
+
+// CircuitBreak returns a per-route circuit breaking middleware that opens
+// after threshold consecutive failures, for the cooldown.
+func CircuitBreak(threshold int, cooldown time.Duration) func(http.Handler) http.Handler {
+	return NewBreaker(threshold, cooldown).Handler
+}
+
+// Handler satisfies the middleware signature.
+func (b *Breaker) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if IsStreaming(req) {
+			next.ServeHTTP(res, req)
+			return
+		}
+		key := routeKey(req)
+		probe, wait, ok := b.allow(key)
+		if !ok {
+			res.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(res, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		w := &goji.StatusWriter{ResponseWriter: res}
+		failed := true
+		defer func() {
+			b.record(key, probe, failed)
+		}()
+		next.ServeHTTP(w, req)
+		failed = w.Status() >= 500
+	})
+}
+
+// allow determines if a request for the route is allowed, returning whether
+// or not the request is the half-open circuit's probe, or the time until the
+// circuit is half-open when it is not allowed.
+func (b *Breaker) allow(key string) (bool, time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.routes[key]
+	if !ok {
+		r = &breakerRoute{state: BreakerClosed}
+		b.routes[key] = r
+	}
+	switch r.state {
+	case BreakerOpen:
+		if wait := r.opened.Add(b.cooldown).Sub(b.now()); wait > 0 {
+			r.rejected++
+			return false, wait, false
+		}
+		r.state = BreakerHalfOpen
+		return true, 0, true
+	case BreakerHalfOpen:
+		// the probe is in flight
+		r.rejected++
+		return false, time.Second, false
+	}
+	return false, 0, true
+}
+
+// record records the outcome of a request for the route, opening or closing
+// the route's circuit. Only the probe changes the state of a half-open
+// circuit, and the outcomes of other requests completing while the circuit
+// is not closed are ignored.
+func (b *Breaker) record(key string, probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.routes[key]
+	switch {
+	case probe && failed:
+		r.state, r.opened = BreakerOpen, b.now()
+	case probe:
+		r.state, r.failures = BreakerClosed, 0
+	case r.state != BreakerClosed:
+	case !failed:
+		r.failures = 0
+	default:
+		if r.failures++; r.failures >= b.threshold {
+			r.state, r.opened = BreakerOpen, b.now()
+		}
+	}
+}
+
+// Stats returns a snapshot of the circuit breaker's state, keyed by route.
+func (b *Breaker) Stats() map[string]BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make(map[string]BreakerStats, len(b.routes))
+	for key, r := range b.routes {
+		stats[key] = BreakerStats{
+			State:    r.state,
+			Failures: r.failures,
+			Rejected: r.rejected,
+		}
+	}
+	return stats
+}
+
+// State satisfies the Stater interface.
+func (b *Breaker) State() interface{} {
+	return b.Stats()
+}
+
+// BreakerStats are the circuit breaking statistics for a route.
+type BreakerStats struct {
+	State    string `json:"state"`
+	Failures int    `json:"failures"`
+	Rejected uint64 `json:"rejected"`
+}
+
+// breakerRoute is the circuit state for a route.
+type breakerRoute struct {
+	state    string
+	failures int
+	opened   time.Time
+	rejected uint64
+}
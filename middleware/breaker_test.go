@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(2, 10*time.Second)
+	b.now = func() time.Time { return now }
+	m := goji.New()
+	m.Use(b.Handler)
+	fail := true
+	m.HandleFunc(goji.Get("/a"), func(res http.ResponseWriter, req *http.Request) {
+		if fail {
+			res.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	do := func() *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", "/a", nil))
+		return res
+	}
+	tests := []struct {
+		elapsed time.Duration
+		fail    bool
+		code    int
+		state   string
+	}{
+		{0, true, 500, BreakerClosed},
+		{0, true, 500, BreakerOpen},
+		{5 * time.Second, false, 503, BreakerOpen},
+		{10 * time.Second, true, 500, BreakerOpen},
+		{15 * time.Second, false, 503, BreakerOpen},
+		{20 * time.Second, false, 200, BreakerClosed},
+		{20 * time.Second, true, 500, BreakerClosed},
+	}
+	start := now
+	for i, test := range tests {
+		now, fail = start.Add(test.elapsed), test.fail
+		if res := do(); res.Code != test.code {
+			t.Errorf("test %d expected %d, got: %d", i, test.code, res.Code)
+		}
+		if st := b.Stats()["/a"]; st.State != test.state {
+			t.Errorf("test %d expected state %q, got: %q", i, test.state, st.State)
+		}
+	}
+	if st := b.Stats()["/a"]; st.Rejected != 2 || st.Failures != 1 {
+		t.Errorf("expected rejected=2 failures=1, got: %+v", st)
+	}
+}
+
+func TestBreakerStale(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(1, 10*time.Second)
+	b.now = func() time.Time { return now }
+	m := goji.New()
+	m.Use(b.Handler)
+	started := make(chan struct{})
+	release := map[string]chan bool{
+		"stale": make(chan bool),
+		"probe": make(chan bool),
+	}
+	m.HandleFunc(goji.Get("/a"), func(res http.ResponseWriter, req *http.Request) {
+		if ch, ok := release[req.Header.Get("X-Block")]; ok {
+			started <- struct{}{}
+			if <-ch {
+				res.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+		if req.Header.Get("X-Fail") != "" {
+			res.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	do := func(name, value string) int {
+		req := httptest.NewRequest("GET", "/a", nil)
+		if name != "" {
+			req.Header.Set(name, value)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		return res.Code
+	}
+	block := func(name string) chan int {
+		ch := make(chan int, 1)
+		go func() {
+			ch <- do("X-Block", name)
+		}()
+		<-started
+		return ch
+	}
+	state := func() string {
+		return b.Stats()["/a"].State
+	}
+
+	// admitted before the circuit opened
+	stale := block("stale")
+	if code := do("X-Fail", "1"); code != 500 || state() != BreakerOpen {
+		t.Fatalf("expected 500 %s, got: %d %s", BreakerOpen, code, state())
+	}
+	now = now.Add(10 * time.Second)
+	probe := block("probe")
+	release["stale"] <- false
+	if code := <-stale; code != 200 || state() != BreakerHalfOpen {
+		t.Errorf("expected stale 200 %s, got: %d %s", BreakerHalfOpen, code, state())
+	}
+	if code := do("", ""); code != 503 {
+		t.Errorf("expected 503 while probing, got: %d", code)
+	}
+	release["probe"] <- true
+	if code := <-probe; code != 500 || state() != BreakerOpen {
+		t.Errorf("expected probe 500 %s, got: %d %s", BreakerOpen, code, state())
+	}
+}
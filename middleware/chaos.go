@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// ChaosKey is the route metadata key used to tag routes for fault injection
+// (see Chaos). The value must be true.
+const ChaosKey = "chaos"
+
+// ChaosEnv is the default environment variable that enables fault injection
+// (see ChaosConfig).
+const ChaosEnv = "GOJI_CHAOS"
+
+// ChaosConfig is the fault injection configuration. Rates are the fraction
+// (0 to 1) of requests to tagged routes that have the fault injected.
+type ChaosConfig struct {
+	// Env is the environment variable that must be set to a true value (see
+	// strconv.ParseBool) for faults to be injected (default ChaosEnv).
+	Env string
+	// Latency is the latency injected.
+	Latency time.Duration
+	// LatencyRate is the rate of requests with injected latency.
+	LatencyRate float64
+	// ErrorStatus is the status of injected error responses (default 503
+	// Service Unavailable).
+	ErrorStatus int
+	// ErrorRate is the rate of requests with an injected error response.
+	ErrorRate float64
+	// ResetRate is the rate of requests with an injected connection reset.
+	ResetRate float64
+}
+
+// Chaos returns a middleware that injects latency, error responses, or
+// connection resets for a percentage of requests to routes tagged for fault
+// injection (see ChaosKey), supporting resilience testing of clients.
+//
+// Fault injection is disabled unless the configured environment variable is
+// set, so that the middleware can be safely left in place:
+//
+//	mux.Use(middleware.Chaos(middleware.ChaosConfig{
+//		Latency:     2 * time.Second,
+//		LatencyRate: 0.1,
+//		ErrorRate:   0.05,
+//	}))
+//	mux.Handle(goji.Get("/orders", goji.WithMeta(middleware.ChaosKey, true)), h)
+//
+// Chaos must be used with Mux.Use, as it relies on the matched route.
+func Chaos(cfg ChaosConfig) func(http.Handler) http.Handler {
+	if cfg.Env == "" {
+		cfg.Env = ChaosEnv
+	}
+	if enabled, _ := strconv.ParseBool(os.Getenv(cfg.Env)); !enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	if cfg.ErrorStatus == 0 {
+		cfg.ErrorStatus = http.StatusServiceUnavailable
+	}
+	c := &chaos{
+		cfg: cfg,
+		rnd: rand.Float64,
+	}
+	return c.handler
+}
+
+// chaos is the fault injector.
+type chaos struct {
+	cfg ChaosConfig
+	rnd func() float64
+}
+
+// handler satisfies the middleware signature.
+func (c *chaos) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if v, _ := goji.Meta(req, ChaosKey).(bool); !v {
+			next.ServeHTTP(res, req)
+			return
+		}
+		if c.cfg.LatencyRate > 0 && c.rnd() < c.cfg.LatencyRate {
+			t := time.NewTimer(c.cfg.Latency)
+			select {
+			case <-t.C:
+			case <-req.Context().Done():
+				t.Stop()
+				return
+			}
+		}
+		switch {
+		case c.cfg.ResetRate > 0 && c.rnd() < c.cfg.ResetRate:
+			reset(res)
+		case c.cfg.ErrorRate > 0 && c.rnd() < c.cfg.ErrorRate:
+			http.Error(res, http.StatusText(c.cfg.ErrorStatus), c.cfg.ErrorStatus)
+		default:
+			next.ServeHTTP(res, req)
+		}
+	})
+}
+
+// reset resets the connection, falling back to aborting the response when
+// the connection cannot be hijacked.
+func reset(res http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(res).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	conn.Close()
+}
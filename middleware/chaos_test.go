@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestChaos(t *testing.T) {
+	newMux := func(cfg ChaosConfig) *goji.Mux {
+		m := goji.New()
+		m.Use(Chaos(cfg))
+		h := func(http.ResponseWriter, *http.Request) {}
+		m.HandleFunc(goji.Get("/tagged", goji.WithMeta(ChaosKey, true)), h)
+		m.HandleFunc(goji.Get("/untagged"), h)
+		return m
+	}
+	tests := []struct {
+		env    string
+		cfg    ChaosConfig
+		path   string
+		status int
+		min    time.Duration
+	}{
+		{"", ChaosConfig{ErrorRate: 1}, "/tagged", http.StatusOK, 0},
+		{"false", ChaosConfig{ErrorRate: 1}, "/tagged", http.StatusOK, 0},
+		{"true", ChaosConfig{ErrorRate: 1}, "/tagged", http.StatusServiceUnavailable, 0},
+		{"true", ChaosConfig{ErrorRate: 1}, "/untagged", http.StatusOK, 0},
+		{"true", ChaosConfig{ErrorRate: 1, ErrorStatus: http.StatusTeapot}, "/tagged", http.StatusTeapot, 0},
+		{"true", ChaosConfig{ErrorRate: 0}, "/tagged", http.StatusOK, 0},
+		{"true", ChaosConfig{Latency: 20 * time.Millisecond, LatencyRate: 1}, "/tagged", http.StatusOK, 20 * time.Millisecond},
+	}
+	for i, test := range tests {
+		t.Setenv(ChaosEnv, test.env)
+		m := newMux(test.cfg)
+		res := httptest.NewRecorder()
+		start := time.Now()
+		m.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if d := time.Since(start); d < test.min {
+			t.Errorf("test %d expected latency of at least %v, got: %v", i, test.min, d)
+		}
+	}
+}
+
+func TestChaosReset(t *testing.T) {
+	t.Setenv(ChaosEnv, "1")
+	m := goji.New()
+	m.Use(Chaos(ChaosConfig{ResetRate: 1}))
+	m.HandleFunc(goji.Get("/", goji.WithMeta(ChaosKey, true)), func(http.ResponseWriter, *http.Request) {})
+	s := httptest.NewServer(m)
+	defer s.Close()
+	if res, err := http.Get(s.URL); err == nil {
+		res.Body.Close()
+		t.Errorf("expected error, got: %d", res.StatusCode)
+	}
+}
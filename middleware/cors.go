@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// CORSKey is the route metadata key used to override the CORS policy for a
+// route. The value must be a *CORSPolicy, and is resolved at request time
+// from the matched route.
+//
+// For example, to allow all origins for a public endpoint while the rest of
+// the API is origin restricted:
+//
+//	mux.Use(middleware.CORS(middleware.WithAllowedOrigins("https://example.com")))
+//	mux.Handle(goji.NewPathSpec("/widget.js", goji.WithMeta(middleware.CORSKey, middleware.NewCORSPolicy())), h)
+//
+// Note that preflight requests use the OPTIONS method, so the route must
+// match OPTIONS requests for its policy to apply to preflights.
+const CORSKey = "cors"
+
+// CORSPolicy is a Cross-Origin Resource Sharing (CORS) policy.
+type CORSPolicy struct {
+	origins        map[string]bool
+	allOrigins     bool
+	originFunc     func(string) bool
+	methods        []string
+	routeMethods   bool
+	headers        []string
+	exposed        []string
+	credentials    bool
+	maxAge         time.Duration
+	privateNetwork bool
+	passthrough    bool
+}
+
+// NewCORSPolicy creates a new CORS policy. By default, all origins are
+// allowed, with the GET, HEAD, and POST methods, and any requested headers.
+//
+// NewCORSPolicy panics when credentials are allowed (see
+// WithAllowCredentials) for all origins, as that would allow any site to make
+// credentialed requests. Credentialed policies must set the allowed origins
+// with WithAllowedOrigins (without "*") or WithAllowOriginFunc.
+func NewCORSPolicy(opts ...CORSOption) *CORSPolicy {
+	c := &CORSPolicy{
+		allOrigins: true,
+		methods:    []string{"GET", "HEAD", "POST"},
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.credentials && c.allOrigins && c.originFunc == nil {
+		panic("goji: CORS credentials cannot be allowed for all origins")
+	}
+	return c
+}
+
+// CORS returns a middleware applying a CORS policy created with the options.
+//
+// Preflight requests are answered by the middleware with 204 No Content,
+// without invoking downstream middleware or handlers, unless the
+// WithPreflightPassthrough option is used.
+func CORS(opts ...CORSOption) func(http.Handler) http.Handler {
+	return NewCORSPolicy(opts...).Handler
+}
+
+// Handler satisfies the middleware signature. When the matched route has a
+// *CORSPolicy for the CORSKey metadata, that policy is applied instead.
+func (c *CORSPolicy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		policy := c
+		if p, ok := goji.Meta(req, CORSKey).(*CORSPolicy); ok && p != nil {
+			policy = p
+		}
+		if policy.serve(res, req) {
+			next.ServeHTTP(res, req)
+		}
+	})
+}
+
+// serve applies the policy to the request, returning whether or not the
+// request should be passed to the next handler.
+func (c *CORSPolicy) serve(res http.ResponseWriter, req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	h := res.Header()
+	h.Add("Vary", "Origin")
+	preflight := req.Method == "OPTIONS" && req.Header.Get("Access-Control-Request-Method") != ""
+	if preflight {
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+	}
+	if !c.allowed(origin) {
+		return !preflight || c.passthrough
+	}
+	if c.allOrigins && c.originFunc == nil {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if c.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		if len(c.exposed) != 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(c.exposed, ", "))
+		}
+		return true
+	}
+	methods := c.methods
+	if c.routeMethods {
+		if allowed := goji.AllowedMethods(req); len(allowed) != 0 {
+			methods = allowed
+		}
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	switch {
+	case len(c.headers) != 0:
+		h.Set("Access-Control-Allow-Headers", strings.Join(c.headers, ", "))
+	case req.Header.Get("Access-Control-Request-Headers") != "":
+		h.Set("Access-Control-Allow-Headers", req.Header.Get("Access-Control-Request-Headers"))
+	}
+	if c.maxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.maxAge/time.Second)))
+	}
+	if c.privateNetwork && req.Header.Get("Access-Control-Request-Private-Network") == "true" {
+		h.Set("Access-Control-Allow-Private-Network", "true")
+	}
+	if c.passthrough {
+		return true
+	}
+	res.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+// allowed returns whether or not the origin is allowed.
+func (c *CORSPolicy) allowed(origin string) bool {
+	if c.originFunc != nil {
+		return c.originFunc(origin)
+	}
+	return c.allOrigins || c.origins[strings.ToLower(origin)]
+}
+
+// CORSOption is a CORS policy option.
+type CORSOption func(*CORSPolicy)
+
+// WithAllowedOrigins is a CORS policy option to set the allowed origins (for
+// example, "https://example.com"). The "*" origin allows all origins.
+func WithAllowedOrigins(origins ...string) CORSOption {
+	return func(c *CORSPolicy) {
+		c.allOrigins, c.origins = false, make(map[string]bool, len(origins))
+		for _, origin := range origins {
+			if origin == "*" {
+				c.allOrigins = true
+			}
+			c.origins[strings.ToLower(origin)] = true
+		}
+	}
+}
+
+// WithAllowOriginFunc is a CORS policy option to set a func determining
+// whether or not an origin is allowed, overriding the allowed origins (see
+// WithAllowedOrigins). Allowed origins are echoed in the
+// Access-Control-Allow-Origin header.
+func WithAllowOriginFunc(f func(origin string) bool) CORSOption {
+	return func(c *CORSPolicy) {
+		c.originFunc = f
+	}
+}
+
+// WithAllowedMethods is a CORS policy option to set the allowed methods.
+func WithAllowedMethods(methods ...string) CORSOption {
+	return func(c *CORSPolicy) {
+		c.methods = methods
+	}
+}
+
+// WithRouteMethods is a CORS policy option to compute the allowed methods
+// for preflight requests from the methods registered with the Mux for the
+// request's path (see goji.AllowedMethods), keeping the allowed methods in
+// sync with the route table. The allowed methods are used when no routes
+// match.
+func WithRouteMethods() CORSOption {
+	return func(c *CORSPolicy) {
+		c.routeMethods = true
+	}
+}
+
+// WithAllowedHeaders is a CORS policy option to set the allowed request
+// headers. By default, any requested headers are allowed.
+func WithAllowedHeaders(headers ...string) CORSOption {
+	return func(c *CORSPolicy) {
+		c.headers = headers
+	}
+}
+
+// WithExposedHeaders is a CORS policy option to set the response headers
+// exposed to clients.
+func WithExposedHeaders(headers ...string) CORSOption {
+	return func(c *CORSPolicy) {
+		c.exposed = headers
+	}
+}
+
+// WithAllowCredentials is a CORS policy option to allow credentials
+// (cookies, authorization headers, ...) in cross-origin requests. The allowed
+// origins must be explicitly set (see NewCORSPolicy).
+func WithAllowCredentials(credentials bool) CORSOption {
+	return func(c *CORSPolicy) {
+		c.credentials = credentials
+	}
+}
+
+// WithMaxAge is a CORS policy option to set how long clients may cache
+// preflight responses (the Access-Control-Max-Age header), reducing
+// preflight overhead.
+func WithMaxAge(maxAge time.Duration) CORSOption {
+	return func(c *CORSPolicy) {
+		c.maxAge = maxAge
+	}
+}
+
+// WithPrivateNetwork is a CORS policy option to allow preflight requests for
+// private network access (the Access-Control-Request-Private-Network
+// header).
+func WithPrivateNetwork(privateNetwork bool) CORSOption {
+	return func(c *CORSPolicy) {
+		c.privateNetwork = privateNetwork
+	}
+}
+
+// WithPreflightPassthrough is a CORS policy option to pass preflight
+// requests to downstream middleware and handlers, after setting the CORS
+// headers, instead of answering them directly.
+func WithPreflightPassthrough(passthrough bool) CORSOption {
+	return func(c *CORSPolicy) {
+		c.passthrough = passthrough
+	}
+}
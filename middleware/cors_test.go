@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		opts    []CORSOption
+		method  string
+		headers map[string]string
+		status  int
+		called  bool
+		exp     map[string]string
+	}{
+		// no origin
+		{nil, "GET", nil, http.StatusOK, true, map[string]string{"Access-Control-Allow-Origin": ""}},
+		// simple
+		{nil, "GET", map[string]string{"Origin": "https://a.com"}, http.StatusOK, true, map[string]string{
+			"Access-Control-Allow-Origin": "*",
+			"Vary":                        "Origin",
+		}},
+		// disallowed origin
+		{[]CORSOption{WithAllowedOrigins("https://b.com")}, "GET", map[string]string{"Origin": "https://a.com"}, http.StatusOK, true, map[string]string{
+			"Access-Control-Allow-Origin": "",
+		}},
+		// credentials and exposed headers
+		{[]CORSOption{WithAllowedOrigins("https://A.com"), WithAllowCredentials(true), WithExposedHeaders("X-Total-Count")}, "GET", map[string]string{"Origin": "https://a.com"}, http.StatusOK, true, map[string]string{
+			"Access-Control-Allow-Origin":      "https://a.com",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Expose-Headers":    "X-Total-Count",
+		}},
+		// preflight
+		{[]CORSOption{WithMaxAge(10 * time.Minute), WithAllowedMethods("GET", "PUT")}, "OPTIONS", map[string]string{
+			"Origin":                         "https://a.com",
+			"Access-Control-Request-Method":  "PUT",
+			"Access-Control-Request-Headers": "X-Token",
+		}, http.StatusNoContent, false, map[string]string{
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Methods": "GET, PUT",
+			"Access-Control-Allow-Headers": "X-Token",
+			"Access-Control-Max-Age":       "600",
+		}},
+		// preflight with private network
+		{[]CORSOption{WithPrivateNetwork(true), WithAllowedHeaders("X-A", "X-B")}, "OPTIONS", map[string]string{
+			"Origin":                                 "https://a.com",
+			"Access-Control-Request-Method":          "GET",
+			"Access-Control-Request-Private-Network": "true",
+		}, http.StatusNoContent, false, map[string]string{
+			"Access-Control-Allow-Private-Network": "true",
+			"Access-Control-Allow-Headers":         "X-A, X-B",
+			"Access-Control-Max-Age":               "",
+		}},
+		// preflight without private network
+		{nil, "OPTIONS", map[string]string{
+			"Origin":                                 "https://a.com",
+			"Access-Control-Request-Method":          "GET",
+			"Access-Control-Request-Private-Network": "true",
+		}, http.StatusNoContent, false, map[string]string{
+			"Access-Control-Allow-Private-Network": "",
+		}},
+		// preflight passthrough
+		{[]CORSOption{WithPreflightPassthrough(true)}, "OPTIONS", map[string]string{
+			"Origin":                        "https://a.com",
+			"Access-Control-Request-Method": "GET",
+		}, http.StatusOK, true, map[string]string{
+			"Access-Control-Allow-Methods": "GET, HEAD, POST",
+		}},
+		// preflight disallowed
+		{[]CORSOption{WithAllowedOrigins("https://b.com")}, "OPTIONS", map[string]string{
+			"Origin":                        "https://a.com",
+			"Access-Control-Request-Method": "GET",
+		}, http.StatusOK, false, map[string]string{
+			"Access-Control-Allow-Origin": "",
+		}},
+	}
+	for i, test := range tests {
+		var called bool
+		h := CORS(test.opts...)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			called = true
+		}))
+		req := httptest.NewRequest(test.method, "/", nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		if res.Code != test.status || called != test.called {
+			t.Errorf("test %d expected %d called=%t, got: %d %t", i, test.status, test.called, res.Code, called)
+		}
+		for k, v := range test.exp {
+			if s := res.Header().Get(k); s != v {
+				t.Errorf("test %d expected %s: %q, got: %q", i, k, v, s)
+			}
+		}
+	}
+}
+
+func TestCORSOverride(t *testing.T) {
+	m := goji.New()
+	m.Use(CORS(WithAllowedOrigins("https://example.com")))
+	h := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	m.Handle(goji.Get("/api"), h)
+	m.Handle(goji.NewPathSpec("/widget", goji.WithMeta(CORSKey, NewCORSPolicy(WithMaxAge(time.Minute)))), h)
+	tests := []struct {
+		method string
+		path   string
+		origin string
+		exp    string
+		maxAge string
+	}{
+		{"GET", "/api", "https://example.com", "https://example.com", ""},
+		{"GET", "/api", "https://other.com", "", ""},
+		{"GET", "/widget", "https://other.com", "*", ""},
+		{"OPTIONS", "/widget", "https://other.com", "*", "60"},
+		{"OPTIONS", "/api", "https://other.com", "", ""},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		req.Header.Set("Origin", test.origin)
+		if test.method == "OPTIONS" {
+			req.Header.Set("Access-Control-Request-Method", "GET")
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Header().Get("Access-Control-Allow-Origin"); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+		if s := res.Header().Get("Access-Control-Max-Age"); s != test.maxAge {
+			t.Errorf("test %d expected max age %q, got: %q", i, test.maxAge, s)
+		}
+	}
+}
+
+func TestCORSRouteMethods(t *testing.T) {
+	m := goji.New(goji.WithAutoOptions(nil))
+	m.Use(CORS(WithRouteMethods()))
+	h := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	m.Handle(goji.Get("/users"), h)
+	m.Handle(goji.Put("/users"), h)
+	sub := goji.NewSubMux(goji.WithAutoOptions(nil))
+	sub.Use(CORS(WithRouteMethods()))
+	sub.Handle(goji.Put("/x"), h)
+	sub.Handle(goji.Delete("/x"), h)
+	root := goji.New()
+	root.Handle(goji.NewPathSpec("/api/*"), sub)
+	tests := []struct {
+		mux  http.Handler
+		path string
+		exp  string
+	}{
+		{m, "/users", "GET, HEAD, OPTIONS, PUT"},
+		{m, "/missing", "GET, HEAD, POST"},
+		{root, "/api/x", "DELETE, OPTIONS, PUT"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("OPTIONS", test.path, nil)
+		req.Header.Set("Origin", "https://a.com")
+		req.Header.Set("Access-Control-Request-Method", "PUT")
+		res := httptest.NewRecorder()
+		test.mux.ServeHTTP(res, req)
+		if res.Code != http.StatusNoContent {
+			t.Errorf("test %d expected %d, got: %d", i, http.StatusNoContent, res.Code)
+		}
+		if s := res.Header().Get("Access-Control-Allow-Methods"); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestCORSCredentials(t *testing.T) {
+	for i, opts := range [][]CORSOption{
+		{WithAllowCredentials(true)},
+		{WithAllowedOrigins("*"), WithAllowCredentials(true)},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("test %d expected panic", i)
+				}
+			}()
+			NewCORSPolicy(opts...)
+		}()
+	}
+	c := NewCORSPolicy(WithAllowCredentials(true), WithAllowOriginFunc(func(origin string) bool {
+		return strings.HasSuffix(origin, ".example.com")
+	}))
+	tests := []struct {
+		origin string
+		exp    string
+	}{
+		{"https://a.example.com", "https://a.example.com"},
+		{"https://other.com", ""},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Origin", test.origin)
+		res := httptest.NewRecorder()
+		c.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(res, req)
+		if s := res.Header().Get("Access-Control-Allow-Origin"); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestCSRF(t *testing.T) {
+	m := goji.New()
+	m.Use(CSRF(WithTrustedOrigins("https://Trusted.com")))
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(goji.NewPathSpec("/form"), h)
+	m.HandleFunc(goji.Post("/hooks", goji.WithMeta(CSRFExemptKey, true)), h)
+	m.HandleFunc(goji.Post("/partner", goji.WithMeta(CSRFOriginsKey, []string{"https://partner.com"})), h)
+	tests := []struct {
+		method  string
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{"GET", "/form", map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusOK},
+		{"POST", "/form", nil, http.StatusOK},
+		{"POST", "/form", map[string]string{"Sec-Fetch-Site": "same-origin"}, http.StatusOK},
+		{"POST", "/form", map[string]string{"Sec-Fetch-Site": "none"}, http.StatusOK},
+		{"POST", "/form", map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"POST", "/form", map[string]string{"Sec-Fetch-Site": "same-site"}, http.StatusForbidden},
+		{"POST", "/form", map[string]string{"Origin": "http://example.com"}, http.StatusOK},
+		{"POST", "/form", map[string]string{"Origin": "https://evil.com"}, http.StatusForbidden},
+		{"POST", "/form", map[string]string{"Origin": "https://trusted.com", "Sec-Fetch-Site": "cross-site"}, http.StatusOK},
+		{"DELETE", "/form", map[string]string{"Origin": "https://evil.com"}, http.StatusForbidden},
+		{"POST", "/hooks", map[string]string{"Origin": "https://evil.com", "Sec-Fetch-Site": "cross-site"}, http.StatusOK},
+		{"POST", "/partner", map[string]string{"Origin": "https://partner.com", "Sec-Fetch-Site": "cross-site"}, http.StatusOK},
+		{"POST", "/partner", map[string]string{"Origin": "https://evil.com", "Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kenshaw/goji"
+)
+
+// Forwarded is a middleware that applies the scheme, host, and prefix sent by
+// a reverse proxy to the request, so that URLs and redirects generated by
+// handlers are externally correct.
+//
+// The scheme and host are read from the first element of the standard
+// Forwarded header (RFC 7239), falling back to the X-Forwarded-Proto and
+// X-Forwarded-Host headers, and are set on the request's URL (and Host). The
+// X-Forwarded-Prefix header is made available via goji.ForwardedPrefix.
+//
+// As middleware is called after routing, wrap the Mux with Forwarded (instead
+// of adding it via Mux.Use) for the forwarded values to be used for routing:
+//
+//	http.ListenAndServe(":3000", middleware.Forwarded(mux))
+//
+// Only use with a reverse proxy that sets (or strips) the headers.
+func Forwarded(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		proto, host := forwarded(req.Header.Get("Forwarded"))
+		if proto == "" {
+			proto = firstValue(req.Header.Get("X-Forwarded-Proto"))
+		}
+		if host == "" {
+			host = firstValue(req.Header.Get("X-Forwarded-Host"))
+		}
+		prefix := firstValue(req.Header.Get("X-Forwarded-Prefix"))
+		if proto == "" && host == "" && prefix == "" {
+			next.ServeHTTP(res, req)
+			return
+		}
+		ctx := req.Context()
+		if prefix != "" {
+			ctx = goji.WithForwardedPrefix(ctx, prefix)
+		}
+		req = req.WithContext(ctx)
+		u := *req.URL
+		if proto != "" {
+			u.Scheme = strings.ToLower(proto)
+		}
+		if host != "" {
+			u.Host, req.Host = host, host
+		}
+		req.URL = &u
+		next.ServeHTTP(res, req)
+	})
+}
+
+// forwarded parses the proto and host parameters from the first element of a
+// Forwarded header.
+func forwarded(v string) (string, string) {
+	var proto, host string
+	v, _, _ = strings.Cut(v, ",")
+	for _, pair := range strings.Split(v, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(key) {
+		case "proto":
+			proto = value
+		case "host":
+			host = value
+		}
+	}
+	return proto, host
+}
+
+// firstValue returns the first value of a comma separated header value.
+func firstValue(v string) string {
+	v, _, _ = strings.Cut(v, ",")
+	return strings.TrimSpace(v)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestForwarded(t *testing.T) {
+	tests := []struct {
+		headers map[string]string
+		exp     string
+	}{
+		{nil, "http://example.com/users"},
+		{map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "a.com"}, "https://a.com/users"},
+		{map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-Prefix": "/app/"}, "https://example.com/app/users"},
+		{map[string]string{"Forwarded": `for=1.2.3.4;proto=HTTPS;host="b.com", for=5.6.7.8`, "X-Forwarded-Host": "a.com"}, "https://b.com/users"},
+		{map[string]string{"Forwarded": "for=1.2.3.4", "X-Forwarded-Host": "a.com"}, "http://a.com/users"},
+	}
+	for i, test := range tests {
+		m := goji.New()
+		m.HandleFunc(goji.Get("/users"), func(res http.ResponseWriter, req *http.Request) {
+			scheme := req.URL.Scheme
+			if scheme == "" {
+				scheme = "http"
+			}
+			res.Write([]byte(scheme + "://" + req.Host + m.URLFor(req, "/users")))
+		})
+		req := httptest.NewRequest("GET", "/users", nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		res := httptest.NewRecorder()
+		Forwarded(m).ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kenshaw/goji"
+)
+
+// HeaderRule is a response header policy rule (see HeaderPolicy). The rule
+// applies to responses matching all of its (non-empty) conditions.
+type HeaderRule struct {
+	// Meta is the route metadata key that must be set (and not false) for the
+	// matched route (see goji.WithMeta).
+	Meta string
+	// PathPrefix is the request path prefix, such as "/api/".
+	PathPrefix string
+	// StatusClasses are the response status classes, such as 4 for 4xx and
+	// 5 for 5xx responses.
+	StatusClasses []int
+	// ContentTypes are the response media types, which may have a "*"
+	// subtype, such as "text/*".
+	ContentTypes []string
+	// Set are the response headers to set.
+	Set http.Header
+	// Add are the response header values to append.
+	Add http.Header
+	// Del are the response headers to remove.
+	Del []string
+}
+
+// HeaderPolicy returns a middleware that applies the declarative response
+// header rules, in order, before the response header is written. For
+// example, to set Cache-Control on all API error responses, and remove the
+// X-Powered-By header from all responses:
+//
+//	mux.Use(middleware.HeaderPolicy(
+//		middleware.HeaderRule{
+//			PathPrefix:    "/api/",
+//			StatusClasses: []int{4, 5},
+//			Set:           http.Header{"Cache-Control": {"no-store"}},
+//		},
+//		middleware.HeaderRule{Del: []string{"X-Powered-By"}},
+//	))
+func HeaderPolicy(rules ...HeaderRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(&goji.HeaderWriter{
+				ResponseWriter: res,
+				Func: func(h http.Header, status int) {
+					applyRules(req, h, status, rules)
+				},
+			}, req)
+		})
+	}
+}
+
+// matches returns whether or not the rule matches the response.
+func (rule HeaderRule) matches(req *http.Request, h http.Header, status int) bool {
+	if rule.Meta != "" {
+		if v := goji.Meta(req, rule.Meta); v == nil || v == false {
+			return false
+		}
+	}
+	if rule.PathPrefix != "" && !strings.HasPrefix(req.URL.Path, rule.PathPrefix) {
+		return false
+	}
+	if len(rule.StatusClasses) != 0 && !slices.Contains(rule.StatusClasses, status/100) {
+		return false
+	}
+	if len(rule.ContentTypes) != 0 {
+		typ, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+		if !slices.ContainsFunc(rule.ContentTypes, func(t string) bool {
+			t = strings.ToLower(t)
+			return t == typ || strings.HasSuffix(t, "/*") && strings.HasPrefix(typ, t[:len(t)-1])
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRules applies the matching rules to the response header.
+func applyRules(req *http.Request, h http.Header, status int, rules []HeaderRule) {
+	for _, rule := range rules {
+		if !rule.matches(req, h, status) {
+			continue
+		}
+		for k, v := range rule.Set {
+			h[http.CanonicalHeaderKey(k)] = slices.Clone(v)
+		}
+		for k, v := range rule.Add {
+			for _, s := range v {
+				h.Add(k, s)
+			}
+		}
+		for _, k := range rule.Del {
+			h.Del(k)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestHeaderPolicy(t *testing.T) {
+	m := goji.New()
+	m.Use(HeaderPolicy(
+		HeaderRule{
+			PathPrefix:    "/api/",
+			StatusClasses: []int{4, 5},
+			Set:           http.Header{"Cache-Control": {"no-store"}},
+		},
+		HeaderRule{
+			Meta: "private",
+			Add:  http.Header{"Vary": {"Cookie"}},
+		},
+		HeaderRule{
+			ContentTypes: []string{"text/*"},
+			Set:          http.Header{"X-Content-Type-Options": {"nosniff"}},
+		},
+		HeaderRule{Del: []string{"X-Powered-By"}},
+	))
+	h := func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("X-Powered-By", "goji")
+		res.Header().Set("Vary", "Accept")
+		if req.URL.Query().Get("html") != "" {
+			res.Header().Set("Content-Type", "text/html")
+		}
+		if req.URL.Query().Get("fail") != "" {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		res.Write([]byte("ok"))
+	}
+	m.HandleFunc(goji.Get("/api/users"), h)
+	m.HandleFunc(goji.Get("/api/me", goji.WithMeta("private", true)), h)
+	m.HandleFunc(goji.Get("/page"), h)
+	tests := []struct {
+		path    string
+		cache   string
+		vary    []string
+		nosniff string
+	}{
+		{"/api/users", "", []string{"Accept"}, ""},
+		{"/api/users?fail=1", "no-store", []string{"Accept"}, ""},
+		{"/api/me", "", []string{"Accept", "Cookie"}, ""},
+		{"/page?fail=1", "", []string{"Accept"}, ""},
+		{"/page?html=1", "", []string{"Accept"}, "nosniff"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if s := res.Header().Get("Cache-Control"); s != test.cache {
+			t.Errorf("test %d expected Cache-Control %q, got: %q", i, test.cache, s)
+		}
+		if v := res.Header().Values("Vary"); len(v) != len(test.vary) || v[len(v)-1] != test.vary[len(test.vary)-1] {
+			t.Errorf("test %d expected Vary %v, got: %v", i, test.vary, v)
+		}
+		if s := res.Header().Get("X-Content-Type-Options"); s != test.nosniff {
+			t.Errorf("test %d expected X-Content-Type-Options %q, got: %q", i, test.nosniff, s)
+		}
+		if s := res.Header().Get("X-Powered-By"); s != "" {
+			t.Errorf("test %d expected X-Powered-By to be removed, got: %q", i, s)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Stater is the interface for middleware that reject traffic (load shedders,
+// concurrency limiters, rate limiters, circuit breakers, ...) and can report
+// their live per-route state.
+type Stater interface {
+	// State returns a JSON encodable snapshot of the middleware's state.
+	State() interface{}
+}
+
+// State satisfies the Stater interface.
+func (s *Shedder) State() interface{} {
+	return s.Stats()
+}
+
+// State satisfies the Stater interface.
+func (l *AdaptiveLimiter) State() interface{} {
+	return l.Stats()
+}
+
+// Limits is a http.Handler that reports the live state of registered limiting
+// middleware as JSON, keyed by the registered name, so operators can see why
+// traffic is being rejected.
+//
+// Limits is intended to be mounted at an internal path, such as
+// "/_health/limits":
+//
+//	limits := middleware.NewLimits()
+//	limits.Register("shed", shedder)
+//	mux.Handle(goji.Get("/_health/limits"), limits)
+type Limits struct {
+	mu      sync.RWMutex
+	staters map[string]Stater
+}
+
+// NewLimits creates a new limits introspection handler.
+func NewLimits() *Limits {
+	return &Limits{
+		staters: make(map[string]Stater),
+	}
+}
+
+// Register registers the Stater with the name, replacing any previously
+// registered Stater with the same name.
+func (l *Limits) Register(name string, s Stater) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.staters[name] = s
+}
+
+// Names returns the sorted names of the registered Staters.
+func (l *Limits) Names() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, 0, len(l.staters))
+	for name := range l.staters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (l *Limits) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	l.mu.RLock()
+	state := make(map[string]interface{}, len(l.staters))
+	for name, s := range l.staters {
+		state[name] = s.State()
+	}
+	l.mu.RUnlock()
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(res)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(state)
+}
+
+// DefaultLimits is the default limits introspection handler.
+var DefaultLimits = NewLimits()
+
+// RegisterLimiter registers the Stater with the name on DefaultLimits.
+func RegisterLimiter(name string, s Stater) {
+	DefaultLimits.Register(name, s)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestLimits(t *testing.T) {
+	s := NewShedder(time.Second)
+	l := NewAdaptiveLimiter(WithLimitInitial(5))
+	b := NewBreaker(5, time.Second)
+	m := goji.New()
+	m.Use(s.Handler)
+	m.Use(l.Handler)
+	m.Use(b.Handler)
+	m.HandleFunc(goji.Get("/hello"), func(http.ResponseWriter, *http.Request) {})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/hello", nil))
+
+	limits := NewLimits()
+	limits.Register("shed", s)
+	limits.Register("adaptive", l)
+	limits.Register("breaker", b)
+	if names, exp := limits.Names(), []string{"adaptive", "breaker", "shed"}; !reflect.DeepEqual(names, exp) {
+		t.Errorf("expected %v, got: %v", exp, names)
+	}
+
+	res := httptest.NewRecorder()
+	limits.ServeHTTP(res, httptest.NewRequest("GET", "/_health/limits", nil))
+	if ct := res.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got: %q", ct)
+	}
+	var v map[string]map[string]map[string]interface{}
+	if err := json.Unmarshal(res.Body.Bytes(), &v); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if limit := v["adaptive"]["/hello"]["limit"]; limit != float64(5) {
+		t.Errorf("expected limit 5, got: %v", limit)
+	}
+	if state := v["breaker"]["/hello"]["state"]; state != BreakerClosed {
+		t.Errorf("expected state %q, got: %v", BreakerClosed, state)
+	}
+	if frac, ok := v["shed"]["/hello"]["fraction"]; !ok || frac != float64(0) {
+		t.Errorf("expected fraction 0, got: %v", frac)
+	}
+}
@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// AdaptiveLimiter is an adaptive concurrency limiting middleware.
+//
+// Rather than using a fixed concurrency limit, an AdaptiveLimiter discovers
+// the sustainable concurrency for each route using a latency gradient with
+// additive increase/multiplicative decrease (AIMD): the limit for a route is
+// increased by 1/limit for each request that completes within the tolerated
+// latency (relative to the lowest recently observed latency for the route,
+// see WithLimitWindow), and is multiplied by the backoff factor for each
+// request that exceeds it or results in a server error.
+//
+// Requests in excess of a route's current limit are rejected with 503 Service
+// Unavailable. Streaming requests (see IsStreaming) bypass the limiter, as
+// long-lived connections would otherwise hold slots indefinitely.
+type AdaptiveLimiter struct {
+	initial   float64
+	min       float64
+	max       float64
+	backoff   float64
+	tolerance float64
+	window    time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*limitRoute
+}
+
+// NewAdaptiveLimiter creates a new adaptive concurrency limiter.
+func NewAdaptiveLimiter(opts ...AdaptiveLimiterOption) *AdaptiveLimiter {
+	l := &AdaptiveLimiter{
+		initial:   20,
+		min:       1,
+		max:       1000,
+		backoff:   0.9,
+		tolerance: 2,
+		window:    30 * time.Second,
+		now:       time.Now,
+		routes:    make(map[string]*limitRoute),
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// AdaptiveLimit returns an adaptive concurrency limiting middleware.
+func AdaptiveLimit(opts ...AdaptiveLimiterOption) func(http.Handler) http.Handler {
+	return NewAdaptiveLimiter(opts...).Handler
+}
+
+// Handler satisfies the middleware signature.
+func (l *AdaptiveLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if IsStreaming(req) {
+			next.ServeHTTP(res, req)
+			return
+		}
+		key := routeKey(req)
+		if !l.acquire(key) {
+			res.Header().Set("Retry-After", "1")
+			http.Error(res, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		w := &goji.StatusWriter{ResponseWriter: res}
+		start := time.Now()
+		defer func() {
+			l.release(key, time.Since(start), w.Status())
+		}()
+		next.ServeHTTP(w, req)
+	})
+}
+
+// acquire acquires a concurrency slot for the route.
+func (l *AdaptiveLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.routes[key]
+	if !ok {
+		r = &limitRoute{limit: l.initial}
+		l.routes[key] = r
+	}
+	if float64(r.inflight) >= r.limit {
+		r.rejected++
+		return false
+	}
+	r.inflight++
+	return true
+}
+
+// release releases the concurrency slot for the route, adjusting the route's
+// limit based on the observed latency and status.
+func (l *AdaptiveLimiter) release(key string, d time.Duration, status int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r := l.routes[key]
+	r.inflight--
+	r.observe(l.now(), l.window, d)
+	if status >= 500 || float64(d) > float64(r.minRTT)*l.tolerance {
+		r.limit *= l.backoff
+		if r.limit < l.min {
+			r.limit = l.min
+		}
+	} else {
+		r.limit += 1 / r.limit
+		if r.limit > l.max {
+			r.limit = l.max
+		}
+	}
+}
+
+// Limit returns the current concurrency limit for the route.
+func (l *AdaptiveLimiter) Limit(route string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if r, ok := l.routes[route]; ok {
+		return int(r.limit)
+	}
+	return int(l.initial)
+}
+
+// Stats returns a snapshot of the limiter's state, keyed by route.
+func (l *AdaptiveLimiter) Stats() map[string]LimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make(map[string]LimitStats, len(l.routes))
+	for key, r := range l.routes {
+		stats[key] = LimitStats{
+			Limit:    int(r.limit),
+			InFlight: r.inflight,
+			Rejected: r.rejected,
+			MinRTT:   r.minRTT,
+		}
+	}
+	return stats
+}
+
+// LimitStats are the adaptive concurrency limit statistics for a route.
+type LimitStats struct {
+	Limit    int           `json:"limit"`
+	InFlight int           `json:"in_flight"`
+	Rejected uint64        `json:"rejected"`
+	MinRTT   time.Duration `json:"min_rtt"`
+}
+
+// limitRoute is the tracked concurrency state for a route.
+type limitRoute struct {
+	limit    float64
+	inflight int
+	rejected uint64
+	minRTT   time.Duration
+	// cur and prev are the minimum latencies observed in the current and
+	// previous windows, starting at start.
+	cur, prev time.Duration
+	start     time.Time
+}
+
+// observe records the latency, updating the minimum latency over the
+// current and previous windows, so that minimums observed more than two
+// windows ago expire.
+func (r *limitRoute) observe(now time.Time, window, d time.Duration) {
+	if now.Sub(r.start) >= window {
+		if now.Sub(r.start) >= 2*window {
+			r.cur = 0
+		}
+		r.prev, r.cur, r.start = r.cur, 0, now
+	}
+	if r.cur == 0 || d < r.cur {
+		r.cur = d
+	}
+	r.minRTT = r.cur
+	if r.prev != 0 && r.prev < r.minRTT {
+		r.minRTT = r.prev
+	}
+}
+
+// AdaptiveLimiterOption is an adaptive limiter option.
+type AdaptiveLimiterOption func(*AdaptiveLimiter)
+
+// WithLimitInitial is an adaptive limiter option to set the initial
+// concurrency limit for a route (default 20).
+func WithLimitInitial(initial int) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.initial = float64(initial)
+	}
+}
+
+// WithLimitBounds is an adaptive limiter option to set the minimum and maximum
+// concurrency limit for a route (default 1 and 1000).
+func WithLimitBounds(min, max int) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.min, l.max = float64(min), float64(max)
+	}
+}
+
+// WithLimitBackoff is an adaptive limiter option to set the multiplicative
+// decrease factor applied when a route is overloaded (default 0.9).
+func WithLimitBackoff(backoff float64) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.backoff = backoff
+	}
+}
+
+// WithLimitTolerance is an adaptive limiter option to set the tolerated
+// latency, as a multiple of the lowest recently observed latency for a route,
+// before the route is considered overloaded (default 2).
+func WithLimitTolerance(tolerance float64) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.tolerance = tolerance
+	}
+}
+
+// WithLimitWindow is an adaptive limiter option to set the window over which
+// the lowest latency for a route is tracked (default 30 seconds). The lowest
+// latency is the minimum over the current and previous windows, allowing the
+// limiter to adapt when a route's baseline latency increases.
+func WithLimitWindow(window time.Duration) AdaptiveLimiterOption {
+	return func(l *AdaptiveLimiter) {
+		l.window = window
+	}
+}
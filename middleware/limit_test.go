@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	m := goji.New()
+	l := NewAdaptiveLimiter(WithLimitInitial(2), WithLimitBounds(1, 3), WithLimitBackoff(0.5), WithLimitTolerance(1e9))
+	m.Use(l.Handler)
+	block, done := make(chan bool), make(chan bool)
+	m.HandleFunc(goji.Get("/block"), func(res http.ResponseWriter, req *http.Request) {
+		block <- true
+		<-done
+	})
+	m.HandleFunc(goji.Get("/ok"), func(res http.ResponseWriter, req *http.Request) {})
+	m.HandleFunc(goji.Get("/fail"), func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusInternalServerError)
+	})
+
+	do := func(path string) int {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", path, nil))
+		return res.Code
+	}
+
+	// exceed limit
+	for i := 0; i < 2; i++ {
+		go do("/block")
+		<-block
+	}
+	if code := do("/block"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got: %d", http.StatusServiceUnavailable, code)
+	}
+	if st := l.Stats()["/block"]; st.InFlight != 2 || st.Rejected != 1 || st.Limit != 2 {
+		t.Errorf("expected in_flight=2 rejected=1 limit=2, got: %+v", st)
+	}
+	close(done)
+
+	// additive increase
+	for i := 0; i < 10; i++ {
+		if code := do("/ok"); code != http.StatusOK {
+			t.Fatalf("expected %d, got: %d", http.StatusOK, code)
+		}
+	}
+	if limit := l.Limit("/ok"); limit != 3 {
+		t.Errorf("expected limit 3, got: %d", limit)
+	}
+
+	// multiplicative decrease
+	for i := 0; i < 3; i++ {
+		if code := do("/fail"); code != http.StatusInternalServerError {
+			t.Fatalf("expected %d, got: %d", http.StatusInternalServerError, code)
+		}
+	}
+	if limit := l.Limit("/fail"); limit != 1 {
+		t.Errorf("expected limit 1, got: %d", limit)
+	}
+	if limit := l.Limit("/unknown"); limit != 2 {
+		t.Errorf("expected limit 2, got: %d", limit)
+	}
+}
+
+func TestAdaptiveLimiterWindow(t *testing.T) {
+	start := time.Unix(0, 0)
+	var r limitRoute
+	tests := []struct {
+		elapsed time.Duration
+		d       time.Duration
+		exp     time.Duration
+	}{
+		{0, 10 * time.Millisecond, 10 * time.Millisecond},
+		{1 * time.Second, 50 * time.Millisecond, 10 * time.Millisecond},
+		{30 * time.Second, 50 * time.Millisecond, 10 * time.Millisecond},
+		{40 * time.Second, 40 * time.Millisecond, 10 * time.Millisecond},
+		{60 * time.Second, 50 * time.Millisecond, 40 * time.Millisecond},
+		{180 * time.Second, 70 * time.Millisecond, 70 * time.Millisecond},
+	}
+	for i, test := range tests {
+		r.observe(start.Add(test.elapsed), 30*time.Second, test.d)
+		if r.minRTT != test.exp {
+			t.Errorf("test %d expected %v, got: %v", i, test.exp, r.minRTT)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package middleware contains middleware for use with goji.Mux.
+//
+// Middleware in this package is intended to be added to a Mux via Mux.Use,
+// and is called after routing has been performed, allowing it to inspect the
+// matched route (see goji.Matched and goji.Meta).
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kenshaw/goji"
+)
+
+// StreamingKey is the route metadata key used to tag routes serving
+// long-lived streaming responses (SSE, long polling, ...). Routes tagged with
+// a true value bypass response deadlines and concurrency limits.
+//
+// For example:
+//
+//	mux.Handle(goji.Get("/events", goji.WithMeta(middleware.StreamingKey, true)), h)
+const StreamingKey = "streaming"
+
+// WebSocketKey is the route metadata key used to tag routes accepting
+// connection upgrades (such as WebSockets). Upgrade requests to routes tagged
+// with a true value bypass response deadlines and concurrency limits.
+//
+// For example:
+//
+//	mux.Handle(goji.Get("/ws", goji.WithMeta(middleware.WebSocketKey, true)), h)
+const WebSocketKey = "websocket"
+
+// IsStreaming returns whether or not the request is for a route tagged with
+// the StreamingKey metadata, or is a connection upgrade request for a route
+// tagged with the WebSocketKey metadata. Upgrade requests to other routes are
+// not streaming, as clients can otherwise bypass deadlines and limits by
+// sending upgrade headers.
+func IsStreaming(req *http.Request) bool {
+	if b, _ := goji.Meta(req, StreamingKey).(bool); b {
+		return true
+	}
+	b, _ := goji.Meta(req, WebSocketKey).(bool)
+	return b && isUpgrade(req)
+}
+
+// isUpgrade returns whether or not the request is a connection upgrade
+// request.
+func isUpgrade(req *http.Request) bool {
+	if req.Header.Get("Upgrade") == "" {
+		return false
+	}
+	for _, v := range req.Header.Values("Connection") {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// routeKey returns the key used to track per-route state for the request.
+func routeKey(req *http.Request) string {
+	return goji.RouteTemplate(req)
+}
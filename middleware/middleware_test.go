@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestIsStreaming(t *testing.T) {
+	tests := []struct {
+		path    string
+		headers map[string]string
+		exp     bool
+	}{
+		{"/", nil, false},
+		{"/", map[string]string{"Upgrade": "websocket", "Connection": "keep-alive, Upgrade"}, false},
+		{"/ws", map[string]string{"Upgrade": "websocket", "Connection": "keep-alive, Upgrade"}, true},
+		{"/ws", map[string]string{"Upgrade": "websocket"}, false},
+		{"/ws", map[string]string{"Connection": "upgrade"}, false},
+		{"/ws", nil, false},
+		{"/events", nil, true},
+	}
+	m := goji.New()
+	m.HandleFunc(goji.Get("/events", goji.WithMeta(StreamingKey, true)), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(goji.Get("/ws", goji.WithMeta(WebSocketKey, true)), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(goji.Get("/"), func(http.ResponseWriter, *http.Request) {})
+	var streaming bool
+	m.Use(func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			streaming = IsStreaming(req)
+		})
+	})
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		m.ServeHTTP(httptest.NewRecorder(), req)
+		if streaming != test.exp {
+			t.Errorf("test %d expected %t, got: %t", i, test.exp, streaming)
+		}
+	}
+}
+
+func TestStreamingBypass(t *testing.T) {
+	m := goji.New()
+	m.Use(AdaptiveLimit(WithLimitInitial(1)))
+	m.Use(Timeout(10 * time.Millisecond))
+	block := make(chan bool)
+	m.HandleFunc(goji.Get("/events", goji.WithMeta(StreamingKey, true)), func(res http.ResponseWriter, req *http.Request) {
+		<-block
+		res.Write([]byte("event"))
+	})
+	m.HandleFunc(goji.Get("/"), func(http.ResponseWriter, *http.Request) {})
+
+	// streams bypass the concurrency limit and timeout
+	done := make(chan *httptest.ResponseRecorder)
+	for i := 0; i < 2; i++ {
+		go func() {
+			res := httptest.NewRecorder()
+			m.ServeHTTP(res, httptest.NewRequest("GET", "/events", nil))
+			done <- res
+		}()
+	}
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if res.Code != http.StatusOK {
+		t.Errorf("expected %d, got: %d", http.StatusOK, res.Code)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(block)
+	for i := 0; i < 2; i++ {
+		if res := <-done; res.Code != http.StatusOK || res.Body.String() != "event" {
+			t.Errorf("expected %d event, got: %d %q", http.StatusOK, res.Code, res.Body.String())
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a per-route token bucket rate limiting middleware.
+//
+// Requests in excess of a route's rate (and burst) are rejected with 429 Too
+// Many Requests.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*rateRoute
+}
+
+// NewRateLimiter creates a new per-route rate limiter allowing rate requests
+// per second, with bursts of up to burst requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		now:    time.Now,
+		routes: make(map[string]*rateRoute),
+	}
+}
+
+// RateLimit returns a per-route rate limiting middleware allowing rate
+// requests per second, with bursts of up to burst requests.
+func RateLimit(rate float64, burst int) func(http.Handler) http.Handler {
+	return NewRateLimiter(rate, burst).Handler
+}
+
+// Handler satisfies the middleware signature.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if wait, ok := l.allow(routeKey(req)); !ok {
+			res.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(res, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+// allow takes a token from the route's bucket, returning the time to wait
+// for the next token when the bucket is empty.
+func (l *RateLimiter) allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	r, ok := l.routes[key]
+	if !ok {
+		r = &rateRoute{tokens: l.burst, last: now}
+		l.routes[key] = r
+	}
+	r.tokens = math.Min(l.burst, r.tokens+now.Sub(r.last).Seconds()*l.rate)
+	r.last = now
+	if r.tokens < 1 {
+		r.rejected++
+		return time.Duration((1 - r.tokens) / l.rate * float64(time.Second)), false
+	}
+	r.tokens--
+	r.allowed++
+	return 0, true
+}
+
+// Stats returns a snapshot of the rate limiter's state, keyed by route.
+func (l *RateLimiter) Stats() map[string]RateStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make(map[string]RateStats, len(l.routes))
+	for key, r := range l.routes {
+		stats[key] = RateStats{
+			Tokens:   r.tokens,
+			Allowed:  r.allowed,
+			Rejected: r.rejected,
+		}
+	}
+	return stats
+}
+
+// State satisfies the Stater interface.
+func (l *RateLimiter) State() interface{} {
+	return l.Stats()
+}
+
+// RateStats are the rate limiting statistics for a route.
+type RateStats struct {
+	Tokens   float64 `json:"tokens"`
+	Allowed  uint64  `json:"allowed"`
+	Rejected uint64  `json:"rejected"`
+}
+
+// rateRoute is the token bucket for a route.
+type rateRoute struct {
+	tokens   float64
+	last     time.Time
+	allowed  uint64
+	rejected uint64
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter(2, 2)
+	l.now = func() time.Time { return now }
+	m := goji.New()
+	m.Use(l.Handler)
+	m.HandleFunc(goji.Get("/a"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(goji.Get("/b"), func(http.ResponseWriter, *http.Request) {})
+	do := func(path string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", path, nil))
+		return res
+	}
+	for i := 0; i < 2; i++ {
+		if res := do("/a"); res.Code != http.StatusOK {
+			t.Fatalf("expected %d, got: %d", http.StatusOK, res.Code)
+		}
+	}
+	res := do("/a")
+	if res.Code != http.StatusTooManyRequests || res.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected %d with Retry-After 1, got: %d %q", http.StatusTooManyRequests, res.Code, res.Header().Get("Retry-After"))
+	}
+	// separate bucket per route
+	if res := do("/b"); res.Code != http.StatusOK {
+		t.Errorf("expected %d, got: %d", http.StatusOK, res.Code)
+	}
+	// refill
+	now = now.Add(500 * time.Millisecond)
+	if res := do("/a"); res.Code != http.StatusOK {
+		t.Errorf("expected %d, got: %d", http.StatusOK, res.Code)
+	}
+	if st := l.Stats()["/a"]; st.Allowed != 3 || st.Rejected != 1 {
+		t.Errorf("expected allowed=3 rejected=1, got: %+v", st)
+	}
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// Recording is a recorded request, and optionally its response, in a
+// replayable form (see gojitest.Replay).
+type Recording struct {
+	// Time is the time the request started.
+	Time time.Time `json:"time"`
+	// Method is the request method.
+	Method string `json:"method"`
+	// URL is the request URL (path and query), with the path redacted when
+	// a sensitive param is bound (see goji.RedactedPath).
+	URL string `json:"url"`
+	// Host is the request host.
+	Host string `json:"host,omitempty"`
+	// Header is the request header, with sensitive headers redacted.
+	Header http.Header `json:"header,omitempty"`
+	// Body is the request body.
+	Body []byte `json:"body,omitempty"`
+	// Truncated is whether or not the request body was truncated to the max
+	// body size (see WithRecordMaxBody).
+	Truncated bool `json:"truncated,omitempty"`
+	// Route is the matched route template (see goji.RouteTemplate).
+	Route string `json:"route,omitempty"`
+	// Params are the bound route params, with sensitive values redacted
+	// (see goji.RedactedParams).
+	Params map[string]string `json:"params,omitempty"`
+	// Response is the recorded response, when recording responses (see
+	// WithRecordResponses).
+	Response *RecordedResponse `json:"response,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	// Status is the response status.
+	Status int `json:"status"`
+	// Header is the response header, with sensitive headers redacted.
+	Header http.Header `json:"header,omitempty"`
+	// Body is the response body.
+	Body []byte `json:"body,omitempty"`
+	// Truncated is whether or not the response body was truncated to the max
+	// body size (see WithRecordMaxBody).
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RecordSink is the interface for recording sinks.
+type RecordSink interface {
+	// Record records the recording.
+	Record(Recording)
+}
+
+// RecordSinkFunc is a RecordSink func.
+type RecordSinkFunc func(Recording)
+
+// Record satisfies the RecordSink interface.
+func (f RecordSinkFunc) Record(r Recording) {
+	f(r)
+}
+
+// JSONLSink returns a RecordSink that writes each recording to the writer
+// as a line of JSON (JSONL), the format read by gojitest.Replay.
+func JSONLSink(w io.Writer) RecordSink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return RecordSinkFunc(func(r Recording) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(r)
+	})
+}
+
+// Record returns a middleware that records sampled requests (and optionally
+// their responses) to the sink, for offline replay against a Mux (see
+// gojitest.Replay).
+//
+// By default, 1% of requests are recorded (see WithRecordSample), without
+// their responses (see WithRecordResponses). Request and response bodies are
+// recorded up to the max body size (see WithRecordMaxBody), and the values of
+// sensitive headers (such as Authorization and Cookie) and params (see
+// goji.RedactParams) are redacted.
+func Record(sink RecordSink, opts ...RecordOption) func(http.Handler) http.Handler {
+	r := &recorder{
+		sink:    sink,
+		sample:  0.01,
+		maxBody: 1 << 20,
+		rnd:     rand.Float64,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r.handler
+}
+
+// recorder is the request recorder.
+type recorder struct {
+	sink      RecordSink
+	sample    float64
+	responses bool
+	maxBody   int64
+	rnd       func() float64
+}
+
+// handler satisfies the middleware signature.
+func (r *recorder) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if r.sample < 1 && r.rnd() >= r.sample {
+			next.ServeHTTP(res, req)
+			return
+		}
+		u := *req.URL
+		u.Path, u.RawPath = goji.RedactedPath(req), ""
+		rec := Recording{
+			Time:   time.Now(),
+			Method: req.Method,
+			URL:    u.RequestURI(),
+			Host:   req.Host,
+			Header: redactHeader(req.Header, "Authorization", "Cookie", "Proxy-Authorization"),
+			Params: goji.RedactedParams(req),
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			buf, err := io.ReadAll(io.LimitReader(req.Body, r.maxBody+1))
+			if err == nil {
+				rec.Body, rec.Truncated = buf[:min(int64(len(buf)), r.maxBody)], int64(len(buf)) > r.maxBody
+			}
+			req.Body = readCloser{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+		}
+		var w *recordWriter
+		if r.responses {
+			w = &recordWriter{StatusWriter: goji.StatusWriter{ResponseWriter: res}, max: r.maxBody}
+			res = w
+		}
+		next.ServeHTTP(res, req)
+		rec.Route = goji.RouteTemplate(req)
+		if w != nil {
+			rec.Response = &RecordedResponse{
+				Status:    w.Status(),
+				Header:    redactHeader(w.Header(), "Set-Cookie"),
+				Body:      w.buf.Bytes(),
+				Truncated: w.truncated,
+			}
+		}
+		r.sink.Record(rec)
+	})
+}
+
+// redactHeader returns a copy of the header with the values of the named
+// headers redacted.
+func redactHeader(h http.Header, names ...string) http.Header {
+	h = h.Clone()
+	for _, name := range names {
+		if _, ok := h[name]; ok {
+			h.Set(name, Redacted)
+		}
+	}
+	return h
+}
+
+// readCloser is a io.ReadCloser with a separate io.Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// recordWriter is a http.ResponseWriter that records the response status
+// and body.
+type recordWriter struct {
+	goji.StatusWriter
+	buf       bytes.Buffer
+	max       int64
+	truncated bool
+}
+
+// Write satisfies the http.ResponseWriter interface.
+func (w *recordWriter) Write(buf []byte) (int, error) {
+	n := min(int64(len(buf)), max(w.max-int64(w.buf.Len()), 0))
+	w.buf.Write(buf[:n])
+	w.truncated = w.truncated || n < int64(len(buf))
+	return w.StatusWriter.Write(buf)
+}
+
+// RecordOption is a request recorder option.
+type RecordOption func(*recorder)
+
+// WithRecordSample is a request recorder option to set the fraction (0 to
+// 1) of requests recorded (default 0.01).
+func WithRecordSample(sample float64) RecordOption {
+	return func(r *recorder) {
+		r.sample = sample
+	}
+}
+
+// WithRecordResponses is a request recorder option to also record
+// responses (default false). Responses may contain sensitive data, and are
+// only redacted of the Set-Cookie header.
+func WithRecordResponses(responses bool) RecordOption {
+	return func(r *recorder) {
+		r.responses = responses
+	}
+}
+
+// WithRecordMaxBody is a request recorder option to set the maximum size of
+// recorded request and response bodies (default 1 MiB).
+func WithRecordMaxBody(n int64) RecordOption {
+	return func(r *recorder) {
+		r.maxBody = n
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestRecord(t *testing.T) {
+	var buf bytes.Buffer
+	m := goji.New()
+	m.Use(Record(JSONLSink(&buf), WithRecordSample(1), WithRecordResponses(true), WithRecordMaxBody(4)))
+	m.HandleFunc(goji.Post("/echo/:name"), func(res http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		res.Header().Set("Set-Cookie", "session=secret")
+		res.WriteHeader(http.StatusCreated)
+		res.Write(body)
+	})
+	req := httptest.NewRequest("POST", "/echo/carl?x=1", strings.NewReader("hello world"))
+	req.Header.Set("Authorization", "Bearer secret")
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, req)
+	if s := res.Body.String(); s != "hello world" {
+		t.Errorf("expected handler to read full body, got: %q", s)
+	}
+	var rec Recording
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if rec.Method != "POST" || rec.URL != "/echo/carl?x=1" || rec.Route != "/echo/:name" || string(rec.Body) != "hell" || !rec.Truncated || rec.Params["name"] != "carl" {
+		t.Errorf("unexpected recording: %+v", rec)
+	}
+	if s := rec.Header.Get("Authorization"); s != Redacted {
+		t.Errorf("expected %q, got: %q", Redacted, s)
+	}
+	if rec.Response == nil || rec.Response.Status != http.StatusCreated || string(rec.Response.Body) != "hell" || !rec.Response.Truncated {
+		t.Fatalf("unexpected response: %+v", rec.Response)
+	}
+	if s := rec.Response.Header.Get("Set-Cookie"); s != Redacted {
+		t.Errorf("expected %q, got: %q", Redacted, s)
+	}
+}
+
+func TestRecordRedacted(t *testing.T) {
+	goji.RedactParams("record_token")
+	var recs []Recording
+	m := goji.New()
+	m.Use(Record(RecordSinkFunc(func(rec Recording) {
+		recs = append(recs, rec)
+	}), WithRecordSample(1)))
+	m.HandleFunc(goji.Get("/reset/:record_token"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("ok"))
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/reset/secret?x=1", nil))
+	if len(recs) != 1 {
+		t.Fatalf("expected 1 recording, got: %d", len(recs))
+	}
+	rec := recs[0]
+	if rec.URL != "/reset/:record_token?x=1" || rec.Params["record_token"] != goji.Redacted || rec.Truncated || rec.Response != nil {
+		t.Errorf("unexpected recording: %+v", rec)
+	}
+}
+
+func TestRecordSample(t *testing.T) {
+	var n int
+	r := &recorder{
+		sink:   RecordSinkFunc(func(Recording) { n++ }),
+		sample: 0.5,
+	}
+	vals := []float64{0.1, 0.9, 0.4, 0.6}
+	r.rnd = func() float64 {
+		v := vals[0]
+		vals = vals[1:]
+		return v
+	}
+	h := r.handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for range 4 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+	if n != 2 {
+		t.Errorf("expected 2 recordings, got: %d", n)
+	}
+}
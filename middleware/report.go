@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kenshaw/goji"
+)
+
+// ErrorReport is a panic or server error (5xx) report.
+type ErrorReport struct {
+	// Request is the request.
+	Request *http.Request
+	// Route is the matched route template (see goji.RouteTemplate).
+	Route string
+	// Name is the matched route name (see goji.RouteName).
+	Name string
+	// Params are the bound route params, with sensitive params redacted (see
+	// goji.RedactParams).
+	Params map[string]string
+	// RequestID is the request ID (see WithReportRequestID).
+	RequestID string
+	// Status is the response status.
+	Status int
+	// Panic is the recovered panic value, or nil when the request did not
+	// panic.
+	Panic interface{}
+	// Stack is the stack of the panicking goroutine, or nil when the request
+	// did not panic.
+	Stack []byte
+}
+
+// Reporter is the interface for error reporters, such as adapters for
+// external error trackers.
+type Reporter interface {
+	// Report reports the error report.
+	Report(context.Context, ErrorReport)
+}
+
+// ReporterFunc is a Reporter func.
+type ReporterFunc func(context.Context, ErrorReport)
+
+// Report satisfies the Reporter interface.
+func (f ReporterFunc) Report(ctx context.Context, r ErrorReport) {
+	f(ctx, r)
+}
+
+// Report returns a middleware that reports panics and server error (5xx)
+// responses to the reporter, with the matched route, redacted params,
+// request ID, and, for panics, the stack. When the reporter is nil, reports
+// are logged using the default slog.Logger.
+//
+// Panics are reported and then re-panicked, so Report should be used after
+// (that is, inside) any middleware recovering from panics, such as
+// errpages.Pages.Recover:
+//
+//	mux.Use(pages.Recover)
+//	mux.Use(middleware.Report(sentryReporter))
+//
+// Panics with http.ErrAbortHandler are not reported. Report must be used
+// with Mux.Use, as it relies on the matched route.
+func Report(reporter Reporter, opts ...ReportOption) func(http.Handler) http.Handler {
+	r := &report{
+		reporter: reporter,
+		requestID: func(req *http.Request) string {
+			return req.Header.Get("X-Request-Id")
+		},
+	}
+	if r.reporter == nil {
+		r.reporter = ReporterFunc(LogReport)
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r.handler
+}
+
+// report is the error reporter.
+type report struct {
+	reporter  Reporter
+	requestID func(*http.Request) string
+}
+
+// handler satisfies the middleware signature.
+func (r *report) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		w := &goji.StatusWriter{ResponseWriter: res}
+		defer func() {
+			v := recover()
+			if v == nil {
+				if status := w.Status(); status >= 500 {
+					r.report(req, status, nil, nil)
+				}
+				return
+			}
+			if v != http.ErrAbortHandler {
+				r.report(req, http.StatusInternalServerError, v, debug.Stack())
+			}
+			panic(v)
+		}()
+		next.ServeHTTP(w, req)
+	})
+}
+
+// report reports the request to the reporter.
+func (r *report) report(req *http.Request, status int, v interface{}, stack []byte) {
+	r.reporter.Report(req.Context(), ErrorReport{
+		Request:   req,
+		Route:     routeKey(req),
+		Name:      goji.RouteName(req),
+		Params:    goji.RedactedParams(req),
+		RequestID: r.requestID(req),
+		Status:    status,
+		Panic:     v,
+		Stack:     stack,
+	})
+}
+
+// LogReport logs the error report using the default slog.Logger.
+func LogReport(ctx context.Context, r ErrorReport) {
+	attrs := []any{
+		"method", r.Request.Method,
+		"path", goji.RedactedPath(r.Request),
+		"route", r.Route,
+		"params", r.Params,
+		"status", r.Status,
+	}
+	if r.Name != "" {
+		attrs = append(attrs, "route_name", r.Name)
+	}
+	if r.RequestID != "" {
+		attrs = append(attrs, "request_id", r.RequestID)
+	}
+	if r.Panic != nil {
+		attrs = append(attrs, "panic", r.Panic, "stack", string(r.Stack))
+		slog.ErrorContext(ctx, "panic serving request", attrs...)
+		return
+	}
+	slog.ErrorContext(ctx, "server error", attrs...)
+}
+
+// ReportOption is an error reporter option.
+type ReportOption func(*report)
+
+// WithReportRequestID is an error reporter option to set the func used to
+// determine the request ID (default the X-Request-Id header).
+func WithReportRequestID(f func(*http.Request) string) ReportOption {
+	return func(r *report) {
+		r.requestID = f
+	}
+}
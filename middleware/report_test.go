@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestReport(t *testing.T) {
+	var reports []ErrorReport
+	reporter := ReporterFunc(func(_ context.Context, r ErrorReport) {
+		reports = append(reports, r)
+	})
+	m := goji.New()
+	m.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer func() {
+				if v := recover(); v != nil {
+					res.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+			h.ServeHTTP(res, req)
+		})
+	})
+	m.Use(Report(reporter, WithReportRequestID(func(req *http.Request) string {
+		return req.Header.Get("X-Trace")
+	})))
+	m.HandleFunc(goji.Get("/ok"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(goji.Get("/missing"), func(res http.ResponseWriter, req *http.Request) {
+		http.NotFound(res, req)
+	})
+	m.HandleFunc(goji.Get("/unavailable", goji.WithName("unavailable")), func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	})
+	m.HandleFunc(goji.Get("/users/:id"), func(http.ResponseWriter, *http.Request) {
+		panic("user failed")
+	})
+	m.HandleFunc(goji.Get("/abort"), func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/ok", http.StatusOK},
+		{"/missing", http.StatusNotFound},
+		{"/unavailable", http.StatusServiceUnavailable},
+		{"/users/42", http.StatusInternalServerError},
+		{"/abort", http.StatusInternalServerError},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("X-Trace", "trace-"+test.path[1:])
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got: %d", len(reports))
+	}
+	r := reports[0]
+	if r.Route != "/unavailable" || r.Name != "unavailable" || r.Status != http.StatusServiceUnavailable || r.RequestID != "trace-unavailable" || r.Panic != nil || r.Stack != nil {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	r = reports[1]
+	if r.Route != "/users/:id" || r.Params["id"] != "42" || r.Status != http.StatusInternalServerError || r.Panic != "user failed" || !strings.Contains(string(r.Stack), "report.go") {
+		t.Errorf("unexpected report: %+v", r)
+	}
+}
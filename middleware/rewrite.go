@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/kenshaw/goji"
+)
+
+// RewriteRule is a request rewrite rule (see Rewrite).
+type RewriteRule struct {
+	// Match is the regexp matching the request path, or nil to match all
+	// paths.
+	Match *regexp.Regexp
+	// Path is the rewritten path, which may refer to Match's submatches
+	// (see regexp.Regexp.Expand), such as "/v2/$1", or empty to leave the
+	// path unchanged.
+	Path string
+	// SetHeader are the request headers to set.
+	SetHeader http.Header
+	// DelHeader are the request headers to remove.
+	DelHeader []string
+	// SetQuery are the query parameters to set.
+	SetQuery url.Values
+	// DelQuery are the query parameters to remove.
+	DelQuery []string
+	// Last is whether or not to stop processing rules when the rule matches.
+	Last bool
+}
+
+// Rewrite is a middleware that applies the ordered rewrite rules to requests
+// before routing, replacing small reverse proxy rewrite configurations when
+// serving a Mux directly at the edge.
+//
+// As middleware is called after routing, wrap the Mux with Rewrite (instead
+// of adding it via Mux.Use) for rewritten requests to be routed:
+//
+//	rewrite := middleware.Rewrite(
+//		middleware.RewriteRule{
+//			Match:    regexp.MustCompile(`^/api/v1/(.*)$`),
+//			Path:     "/api/v2/$1",
+//			SetQuery: url.Values{"compat": {"v1"}},
+//		},
+//		middleware.RewriteRule{DelHeader: []string{"X-Debug"}},
+//	)
+//	http.ListenAndServe(":3000", rewrite(mux))
+//
+// When wrapping a handler mounted on a wildcard route (such as a sub-Mux),
+// the remaining path to be routed (see goji.Path) is also rewritten.
+func Rewrite(rules ...RewriteRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			var u *url.URL
+			for _, rule := range rules {
+				path := req.URL.Path
+				if u != nil {
+					path = u.Path
+				}
+				var m []int
+				if rule.Match != nil {
+					if m = rule.Match.FindStringSubmatchIndex(path); m == nil {
+						continue
+					}
+				}
+				if u == nil {
+					u, req = new(url.URL), req.Clone(req.Context())
+					*u = *req.URL
+				}
+				if rule.Path != "" {
+					if rule.Match != nil {
+						path = string(rule.Match.ExpandString(nil, rule.Path, path, m))
+					} else {
+						path = rule.Path
+					}
+					u.Path, u.RawPath = path, ""
+				}
+				if len(rule.SetQuery) != 0 || len(rule.DelQuery) != 0 {
+					q := u.Query()
+					for k, v := range rule.SetQuery {
+						q[k] = v
+					}
+					for _, k := range rule.DelQuery {
+						q.Del(k)
+					}
+					u.RawQuery = q.Encode()
+				}
+				for k, v := range rule.SetHeader {
+					req.Header[http.CanonicalHeaderKey(k)] = v
+				}
+				for _, k := range rule.DelHeader {
+					req.Header.Del(k)
+				}
+				if rule.Last {
+					break
+				}
+			}
+			if u != nil {
+				// update the remaining path when already routed (for example,
+				// when mounted under a wildcard route), keeping the consumed
+				// prefix
+				if path := goji.Path(req.Context()); path != "" {
+					prefix := strings.TrimSuffix(req.URL.EscapedPath(), path)
+					req = req.WithContext(goji.WithPath(req.Context(), strings.TrimPrefix(u.EscapedPath(), prefix)))
+				}
+				req.URL, req.RequestURI = u, u.RequestURI()
+			}
+			next.ServeHTTP(res, req)
+		})
+	}
+}
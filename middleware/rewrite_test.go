@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestRewrite(t *testing.T) {
+	m := goji.New()
+	m.HandleFunc(goji.Get("/api/v2/:name"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(goji.Param(req, "name") + " " + req.URL.RawQuery + " " + req.Header.Get("X-Version") + req.Header.Get("X-Debug")))
+	})
+	h := Rewrite(
+		RewriteRule{
+			Match:    regexp.MustCompile(`^/api/v1/(.*)$`),
+			Path:     "/api/v2/$1",
+			SetQuery: url.Values{"compat": {"v1"}},
+			DelQuery: []string{"debug"},
+		},
+		RewriteRule{
+			Match:     regexp.MustCompile(`^/api/v2/`),
+			SetHeader: http.Header{"x-version": {"2"}},
+			Last:      true,
+		},
+		RewriteRule{DelHeader: []string{"X-Debug"}},
+	)(m)
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/api/v1/carl?debug=1&a=b", http.StatusOK, "carl a=b&compat=v1 2debug"},
+		{"/api/v2/carl", http.StatusOK, "carl  2debug"},
+		{"/api/v3/carl", http.StatusNotFound, "404 page not found\n"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("X-Debug", "debug")
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.body {
+			t.Errorf("test %d expected %q, got: %q", i, test.body, s)
+		}
+		if s := req.Header.Get("X-Debug"); s != "debug" {
+			t.Errorf("test %d expected original request to be unmodified, got: %q", i, s)
+		}
+	}
+	// rewriting a sub-mux path
+	sub := goji.NewSubMux()
+	sub.HandleFunc(goji.Get("/new"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("new"))
+	})
+	m = goji.New()
+	m.Handle(goji.NewPathSpec("/sub/*"), Rewrite(RewriteRule{Match: regexp.MustCompile(`^/sub/old$`), Path: "/sub/new"})(sub))
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "/sub/old", nil))
+	if s := res.Body.String(); s != "new" {
+		t.Errorf("expected %q, got: %q", "new", s)
+	}
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/kenshaw/goji"
+)
+
+// ResponseSchemaKey is the route metadata key for the expected response
+// schema of the route, checked by the ResponseSchema middleware. The value
+// is either a SchemaValidator (such as an adapter for a JSON schema package),
+// or a value of the Go type of the response body (such as User{} or
+// []User{}).
+//
+// For example:
+//
+//	mux.HandleFunc(goji.Get("/users/:id", goji.WithMeta(middleware.ResponseSchemaKey, User{})), user)
+const ResponseSchemaKey = "response_schema"
+
+// SchemaValidator is the interface for response schema validators.
+type SchemaValidator interface {
+	ValidateResponse(body []byte) error
+}
+
+// SchemaMismatch is a response schema mismatch report.
+type SchemaMismatch struct {
+	// Request is the request.
+	Request *http.Request
+	// Route is the matched route template (see goji.RouteTemplate).
+	Route string
+	// Name is the matched route name (see goji.RouteName).
+	Name string
+	// Status is the response status.
+	Status int
+	// Err is the validation error.
+	Err error
+}
+
+// maxSchemaBody is the maximum size of response bodies checked by the
+// ResponseSchema middleware.
+const maxSchemaBody = 10 << 20
+
+// ResponseSchema returns a middleware that checks the successful (2xx) JSON
+// responses of routes with an expected response schema (see
+// ResponseSchemaKey), invoking the callback on mismatches. When the callback
+// is nil, mismatches are logged using the default slog.Logger. Responses are
+// not modified.
+//
+// ResponseSchema buffers response bodies, and is intended for use in
+// development and test environments, keeping documented response types
+// honest:
+//
+//	if dev {
+//		mux.Use(middleware.ResponseSchema(nil))
+//	}
+func ResponseSchema(fn func(SchemaMismatch)) func(http.Handler) http.Handler {
+	if fn == nil {
+		fn = LogSchemaMismatch
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			schema := goji.Meta(req, ResponseSchemaKey)
+			if schema == nil {
+				next.ServeHTTP(res, req)
+				return
+			}
+			w := &recordWriter{StatusWriter: goji.StatusWriter{ResponseWriter: res}, max: maxSchemaBody}
+			next.ServeHTTP(w, req)
+			status := w.Status()
+			if status < 200 || status > 299 || w.buf.Len() == 0 || w.buf.Len() >= maxSchemaBody || !isJSON(res.Header().Get("Content-Type")) {
+				return
+			}
+			if err := validateSchema(schema, w.buf.Bytes()); err != nil {
+				fn(SchemaMismatch{
+					Request: req,
+					Route:   routeKey(req),
+					Name:    goji.RouteName(req),
+					Status:  status,
+					Err:     err,
+				})
+			}
+		})
+	}
+}
+
+// LogSchemaMismatch logs the response schema mismatch using the default
+// slog.Logger.
+func LogSchemaMismatch(m SchemaMismatch) {
+	attrs := []any{
+		"method", m.Request.Method,
+		"path", goji.RedactedPath(m.Request),
+		"route", m.Route,
+		"status", m.Status,
+		"error", m.Err,
+	}
+	if m.Name != "" {
+		attrs = append(attrs, "route_name", m.Name)
+	}
+	slog.Warn("response schema mismatch", attrs...)
+}
+
+// validateSchema validates the response body against the schema.
+func validateSchema(schema interface{}, body []byte) error {
+	if v, ok := schema.(SchemaValidator); ok {
+		return v.ValidateResponse(body)
+	}
+	typ := reflect.TypeOf(schema)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(reflect.New(typ).Interface()); err != nil {
+		return fmt.Errorf("%v: %w", typ, err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%v: unexpected data after JSON value", typ)
+	}
+	return nil
+}
+
+// isJSON returns whether or not the content type is a JSON media type.
+func isJSON(contentType string) bool {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return typ == "application/json" || strings.HasSuffix(typ, "+json")
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+type schemaUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type schemaFunc func([]byte) error
+
+func (f schemaFunc) ValidateResponse(body []byte) error {
+	return f(body)
+}
+
+func TestResponseSchema(t *testing.T) {
+	var mismatches []SchemaMismatch
+	m := goji.New()
+	m.Use(ResponseSchema(func(mm SchemaMismatch) {
+		mismatches = append(mismatches, mm)
+	}))
+	write := func(status int, contentType, body string) http.HandlerFunc {
+		return func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Set("Content-Type", contentType)
+			res.WriteHeader(status)
+			res.Write([]byte(body))
+		}
+	}
+	user := goji.WithMeta(ResponseSchemaKey, schemaUser{})
+	users := goji.WithMeta(ResponseSchemaKey, &[]schemaUser{})
+	m.Handle(goji.Get("/ok", user), write(200, "application/json", `{"id":1,"name":"carl"}`))
+	m.Handle(goji.Get("/extra", user), write(200, "application/json", `{"id":1,"email":"carl@example.com"}`))
+	m.Handle(goji.Get("/type", user), write(200, "application/json; charset=utf-8", `{"id":"1"}`))
+	m.Handle(goji.Get("/trailing", user), write(200, "application/json", `{"id":1} {}`))
+	m.Handle(goji.Get("/list", users), write(200, "application/vnd.api+json", `[{"id":1},{"id":2}]`))
+	m.Handle(goji.Get("/error", user), write(404, "application/json", `{"error":"not found"}`))
+	m.Handle(goji.Get("/html", user), write(200, "text/html", `<p>hello</p>`))
+	m.Handle(goji.Get("/none"), write(200, "application/json", `{"anything":true}`))
+	m.Handle(goji.Get("/validator", goji.WithMeta(ResponseSchemaKey, schemaFunc(func([]byte) error {
+		return errors.New("invalid")
+	}))), write(200, "application/json", `{}`))
+	tests := []struct {
+		path     string
+		mismatch bool
+	}{
+		{"/ok", false},
+		{"/extra", true},
+		{"/type", true},
+		{"/trailing", true},
+		{"/list", false},
+		{"/error", false},
+		{"/html", false},
+		{"/none", false},
+		{"/validator", true},
+	}
+	for i, test := range tests {
+		mismatches = nil
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if n := len(mismatches); test.mismatch && n != 1 || !test.mismatch && n != 0 {
+			t.Errorf("test %d %s expected mismatch %t, got: %v", i, test.path, test.mismatch, mismatches)
+		}
+		if test.mismatch && len(mismatches) == 1 && mismatches[0].Route != test.path {
+			t.Errorf("test %d expected route %s, got: %s", i, test.path, mismatches[0].Route)
+		}
+	}
+}
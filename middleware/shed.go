@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// PriorityKey is the route metadata key used to determine the priority of a
+// route's traffic. Routes with the PriorityLow value are eligible for
+// shedding.
+//
+// For example:
+//
+//	mux.Handle(goji.Get("/reports/*", goji.WithMeta(middleware.PriorityKey, middleware.PriorityLow)), h)
+const PriorityKey = "priority"
+
+// PriorityLow is the route metadata value for low priority traffic.
+const PriorityLow = "low"
+
+// Shedder is a latency based load shedding middleware.
+//
+// A Shedder tracks the recent p99 latency for each route, and when the p99
+// latency for a route exceeds the configured target, starts rejecting a
+// fraction of the route's low priority requests with 503 Service Unavailable.
+// The rejected fraction is increased while the target is exceeded, and is
+// decreased (recovering automatically) once the p99 latency falls back below
+// the target. As rejected requests are not observed, the rejected fraction
+// also decays over time (see WithShedDecay), so that routes recover even when
+// all of their traffic is being shed. The latency of streaming requests (see
+// IsStreaming) is not tracked.
+type Shedder struct {
+	target    time.Duration
+	window    int
+	step      float64
+	maxFrac   float64
+	decay     time.Duration
+	sheddable func(*http.Request) bool
+	rnd       func() float64
+	now       func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*shedRoute
+}
+
+// NewShedder creates a new latency based load shedder for the target p99
+// latency.
+func NewShedder(target time.Duration, opts ...ShedderOption) *Shedder {
+	s := &Shedder{
+		target:  target,
+		window:  100,
+		step:    0.1,
+		maxFrac: 0.9,
+		decay:   time.Second,
+		sheddable: func(req *http.Request) bool {
+			return goji.Meta(req, PriorityKey) == PriorityLow
+		},
+		rnd:    rand.Float64,
+		now:    time.Now,
+		routes: make(map[string]*shedRoute),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Shed returns a latency based load shedding middleware for the target p99
+// latency.
+func Shed(target time.Duration, opts ...ShedderOption) func(http.Handler) http.Handler {
+	return NewShedder(target, opts...).Handler
+}
+
+// Handler satisfies the middleware signature.
+func (s *Shedder) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		key := routeKey(req)
+		if s.sheddable(req) && s.rnd() < s.fraction(key) {
+			res.Header().Set("Retry-After", "1")
+			http.Error(res, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		if IsStreaming(req) {
+			next.ServeHTTP(res, req)
+			return
+		}
+		start := time.Now()
+		next.ServeHTTP(res, req)
+		s.observe(key, time.Since(start))
+	})
+}
+
+// fraction returns the current shed fraction for the route, decaying the
+// fraction by the step for each decay interval elapsed since the fraction
+// was last recalculated.
+func (s *Shedder) fraction(key string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.routes[key]
+	if !ok {
+		return 0
+	}
+	if now := s.now(); r.frac > 0 && s.decay > 0 {
+		if n := now.Sub(r.updated) / s.decay; n > 0 {
+			r.frac = max(r.frac-float64(n)*s.step, 0)
+			r.updated = r.updated.Add(n * s.decay)
+		}
+	}
+	return r.frac
+}
+
+// observe records the latency for the route, recalculating the p99 latency
+// and shed fraction once every tenth of the window.
+func (s *Shedder) observe(key string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.routes[key]
+	if !ok {
+		r = &shedRoute{samples: make([]time.Duration, 0, s.window)}
+		s.routes[key] = r
+	}
+	if len(r.samples) < s.window {
+		r.samples = append(r.samples, d)
+	} else {
+		r.samples[r.pos] = d
+	}
+	r.pos = (r.pos + 1) % s.window
+	if r.n++; r.n < (s.window+9)/10 {
+		return
+	}
+	r.n, r.updated = 0, s.now()
+	r.p99 = percentile(r.samples, 0.99)
+	if r.p99 > s.target {
+		r.frac = min(r.frac+s.step, s.maxFrac)
+	} else {
+		r.frac = max(r.frac-s.step, 0)
+	}
+}
+
+// Stats returns a snapshot of the shedder's state, keyed by route.
+func (s *Shedder) Stats() map[string]ShedStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[string]ShedStats, len(s.routes))
+	for key, r := range s.routes {
+		stats[key] = ShedStats{
+			P99:      r.p99,
+			Fraction: r.frac,
+		}
+	}
+	return stats
+}
+
+// ShedStats are the load shedding statistics for a route.
+type ShedStats struct {
+	P99      time.Duration `json:"p99"`
+	Fraction float64       `json:"fraction"`
+}
+
+// shedRoute is the tracked latency state for a route.
+type shedRoute struct {
+	samples []time.Duration
+	pos     int
+	n       int
+	p99     time.Duration
+	frac    float64
+	updated time.Time
+}
+
+// percentile returns the p percentile of the samples.
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	s := append([]time.Duration(nil), samples...)
+	sort.Slice(s, func(i, j int) bool {
+		return s[i] < s[j]
+	})
+	return s[int(float64(len(s)-1)*p)]
+}
+
+// ShedderOption is a shedder option.
+type ShedderOption func(*Shedder)
+
+// WithShedWindow is a shedder option to set the number of latency samples
+// tracked per route (default 100).
+func WithShedWindow(window int) ShedderOption {
+	return func(s *Shedder) {
+		if window > 0 {
+			s.window = window
+		}
+	}
+}
+
+// WithShedStep is a shedder option to set the amount the shed fraction is
+// adjusted by each time the p99 latency is recalculated (default 0.1).
+func WithShedStep(step float64) ShedderOption {
+	return func(s *Shedder) {
+		s.step = step
+	}
+}
+
+// WithShedMax is a shedder option to set the maximum fraction of sheddable
+// traffic that will be rejected (default 0.9).
+func WithShedMax(frac float64) ShedderOption {
+	return func(s *Shedder) {
+		s.maxFrac = frac
+	}
+}
+
+// WithShedDecay is a shedder option to set the interval after which the shed
+// fraction decays by the step when the p99 latency has not been recalculated
+// (default 1 second). A decay of 0 disables decay.
+func WithShedDecay(decay time.Duration) ShedderOption {
+	return func(s *Shedder) {
+		s.decay = decay
+	}
+}
+
+// WithSheddable is a shedder option to set the func used to determine if a
+// request may be shed. By default, requests for routes with the PriorityKey
+// metadata set to PriorityLow may be shed.
+func WithSheddable(sheddable func(*http.Request) bool) ShedderOption {
+	return func(s *Shedder) {
+		s.sheddable = sheddable
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestShedder(t *testing.T) {
+	var delay time.Duration
+	m := goji.New()
+	s := NewShedder(5*time.Millisecond, WithShedWindow(10), WithShedStep(0.5), WithShedMax(1))
+	s.rnd = func() float64 { return 0.75 }
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	m.Use(s.Handler)
+	h := func(res http.ResponseWriter, req *http.Request) {
+		time.Sleep(delay)
+	}
+	m.HandleFunc(goji.Get("/low", goji.WithMeta(PriorityKey, PriorityLow)), h)
+	m.HandleFunc(goji.Get("/high"), h)
+
+	do := func(path string) int {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", path, nil))
+		return res.Code
+	}
+
+	// push p99 above target
+	delay = 10 * time.Millisecond
+	for i := 0; i < 2; i++ {
+		if code := do("/low"); code != http.StatusOK {
+			t.Fatalf("expected %d, got: %d", http.StatusOK, code)
+		}
+	}
+	if code := do("/low"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got: %d", http.StatusServiceUnavailable, code)
+	}
+
+	// high priority traffic is never shed
+	for i := 0; i < 3; i++ {
+		if code := do("/high"); code != http.StatusOK {
+			t.Errorf("expected %d, got: %d", http.StatusOK, code)
+		}
+	}
+	stats := s.Stats()
+	if st := stats["/low"]; st.Fraction != 1 || st.P99 < delay {
+		t.Errorf("expected fraction=1 p99>=%v, got: %+v", delay, st)
+	}
+	if st := stats["/high"]; st.Fraction != 1 {
+		t.Errorf("expected fraction=1, got: %+v", st)
+	}
+
+	// recover
+	delay = 0
+	s.rnd = func() float64 { return 1 }
+	for i := 0; i < 10; i++ {
+		if code := do("/low"); code != http.StatusOK {
+			t.Fatalf("expected %d, got: %d", http.StatusOK, code)
+		}
+	}
+	if st := s.Stats()["/low"]; st.Fraction != 0 {
+		t.Errorf("expected fraction=0, got: %+v", st)
+	}
+
+	// decay while all traffic is shed
+	delay = 10 * time.Millisecond
+	s.rnd = func() float64 { return 0.75 }
+	for i := 0; i < 3; i++ {
+		do("/low")
+	}
+	if st := s.Stats()["/low"]; st.Fraction != 1 {
+		t.Fatalf("expected fraction=1, got: %+v", st)
+	}
+	if code := do("/low"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got: %d", http.StatusServiceUnavailable, code)
+	}
+	now = now.Add(time.Second)
+	if code := do("/low"); code != http.StatusOK {
+		t.Errorf("expected %d, got: %d", http.StatusOK, code)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i > 0; i-- {
+		samples = append(samples, time.Duration(i))
+	}
+	if p := percentile(samples, 0.99); p != 99 {
+		t.Errorf("expected 99, got: %v", p)
+	}
+	if p := percentile(samples, 0.5); p != 50 {
+		t.Errorf("expected 50, got: %v", p)
+	}
+	if p := percentile(nil, 0.99); p != 0 {
+		t.Errorf("expected 0, got: %v", p)
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// SlowRequest is a slow request report.
+type SlowRequest struct {
+	// Request is the slow request.
+	Request *http.Request
+	// Route is the matched route template (see goji.RouteTemplate).
+	Route string
+	// Name is the matched route name (see goji.RouteName).
+	Name string
+	// Params are the bound route params, with sensitive params redacted (see
+	// goji.RedactParams).
+	Params map[string]string
+	// Duration is the duration of the request at the time of the report.
+	Duration time.Duration
+	// Done is whether or not the request had completed at the time of the
+	// report.
+	Done bool
+	// Stack are the stacks of all goroutines, captured when the request was
+	// still in progress after the stuck threshold (see WithSlowStacks).
+	Stack []byte
+}
+
+// Slow returns a middleware that invokes the callback when a request exceeds
+// the threshold. When the callback is nil, slow requests are logged using
+// the default slog.Logger.
+func Slow(threshold time.Duration, fn func(SlowRequest), opts ...SlowOption) func(http.Handler) http.Handler {
+	s := &slow{
+		threshold: threshold,
+		fn:        fn,
+	}
+	if s.fn == nil {
+		s.fn = LogSlow
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s.handler
+}
+
+// slow is the slow request detector.
+type slow struct {
+	threshold time.Duration
+	stuck     time.Duration
+	fn        func(SlowRequest)
+}
+
+// handler satisfies the middleware signature.
+func (s *slow) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		if s.stuck > 0 {
+			t := time.AfterFunc(s.stuck, func() {
+				buf := make([]byte, 1<<20)
+				s.fn(SlowRequest{
+					Request:  req,
+					Route:    routeKey(req),
+					Name:     goji.RouteName(req),
+					Params:   goji.RedactedParams(req),
+					Duration: time.Since(start),
+					Stack:    buf[:runtime.Stack(buf, true)],
+				})
+			})
+			defer t.Stop()
+		}
+		next.ServeHTTP(res, req)
+		if d := time.Since(start); d > s.threshold {
+			s.fn(SlowRequest{
+				Request:  req,
+				Route:    routeKey(req),
+				Name:     goji.RouteName(req),
+				Params:   goji.RedactedParams(req),
+				Duration: d,
+				Done:     true,
+			})
+		}
+	})
+}
+
+// LogSlow logs the slow request using the default slog.Logger.
+func LogSlow(r SlowRequest) {
+	attrs := []any{
+		"method", r.Request.Method,
+		"path", goji.RedactedPath(r.Request),
+		"route", r.Route,
+		"params", r.Params,
+		"duration", r.Duration,
+		"done", r.Done,
+	}
+	if r.Name != "" {
+		attrs = append(attrs, "route_name", r.Name)
+	}
+	if r.Stack != nil {
+		attrs = append(attrs, "stack", string(r.Stack))
+	}
+	slog.Warn("slow request", attrs...)
+}
+
+// SlowOption is a slow request detector option.
+type SlowOption func(*slow)
+
+// WithSlowStacks is a slow request detector option to capture all goroutine
+// stacks and invoke the callback (with Done false) for requests still in
+// progress after the stuck threshold.
+func WithSlowStacks(stuck time.Duration) SlowOption {
+	return func(s *slow) {
+		s.stuck = stuck
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestSlow(t *testing.T) {
+	var mu sync.Mutex
+	var reports []SlowRequest
+	m := goji.New()
+	m.Use(Slow(5*time.Millisecond, func(r SlowRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, r)
+	}, WithSlowStacks(20*time.Millisecond)))
+	m.HandleFunc(goji.Get("/fast/:id"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(goji.Get("/slow/:id"), func(http.ResponseWriter, *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+	})
+	m.HandleFunc(goji.Get("/stuck/:id"), func(http.ResponseWriter, *http.Request) {
+		time.Sleep(40 * time.Millisecond)
+	})
+	for _, path := range []string{"/fast/1", "/slow/2", "/stuck/3"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 3 {
+		t.Fatalf("expected 3 reports, got: %d", len(reports))
+	}
+	tests := []struct {
+		route string
+		id    string
+		done  bool
+		stack bool
+	}{
+		{"/slow/:id", "2", true, false},
+		{"/stuck/:id", "3", false, true},
+		{"/stuck/:id", "3", true, false},
+	}
+	for i, test := range tests {
+		r := reports[i]
+		if r.Route != test.route || !reflect.DeepEqual(r.Params, map[string]string{"id": test.id}) {
+			t.Errorf("test %d expected %s id=%s, got: %s %v", i, test.route, test.id, r.Route, r.Params)
+		}
+		if r.Done != test.done || (r.Stack != nil) != test.stack {
+			t.Errorf("test %d expected done=%t stack=%t, got: %t %t", i, test.done, test.stack, r.Done, r.Stack != nil)
+		}
+		if test.stack && !bytes.Contains(r.Stack, []byte("goroutine")) {
+			t.Errorf("test %d expected goroutine stacks, got: %q", i, r.Stack)
+		}
+	}
+}
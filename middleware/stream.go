@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// StreamDeadline returns a middleware for streaming (for example, SSE)
+// routes that extends the connection's write deadline by the duration on
+// each successful flush, so that the server's WriteTimeout does not
+// terminate legitimate long-lived streams. Streams that stop flushing are
+// still terminated once the extended deadline passes.
+//
+// The write deadline is set via http.ResponseController, and is a no-op when
+// the underlying http.ResponseWriter does not support deadlines.
+func StreamDeadline(extend time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			rc := http.NewResponseController(res)
+			_ = rc.SetWriteDeadline(time.Now().Add(extend))
+			next.ServeHTTP(&deadlineWriter{
+				ResponseWriter: res,
+				rc:             rc,
+				extend:         extend,
+			}, req)
+		})
+	}
+}
+
+// deadlineWriter is a http.ResponseWriter that extends the write deadline on
+// each successful flush.
+type deadlineWriter struct {
+	http.ResponseWriter
+	rc     *http.ResponseController
+	extend time.Duration
+}
+
+// Flush satisfies the http.Flusher interface.
+func (w *deadlineWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError flushes the response, extending the write deadline on success.
+// FlushError is used by http.ResponseController.
+func (w *deadlineWriter) FlushError() error {
+	if err := w.rc.Flush(); err != nil {
+		return err
+	}
+	_ = w.rc.SetWriteDeadline(time.Now().Add(w.extend))
+	return nil
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
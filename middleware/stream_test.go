@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStreamDeadline(t *testing.T) {
+	h := func(flush bool) http.Handler {
+		return StreamDeadline(100 * time.Millisecond)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			rc := http.NewResponseController(res)
+			for i := 0; i < 5; i++ {
+				if _, err := res.Write([]byte("data\n")); err != nil {
+					return
+				}
+				if flush {
+					if err := rc.Flush(); err != nil {
+						return
+					}
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+		}))
+	}
+	tests := []struct {
+		flush bool
+		lines int
+	}{
+		{true, 5},
+		{false, 0},
+	}
+	for i, test := range tests {
+		ts := httptest.NewUnstartedServer(h(test.flush))
+		// the total stream time exceeds the server's write timeout
+		ts.Config.WriteTimeout = 100 * time.Millisecond
+		ts.Start()
+		res, err := ts.Client().Get(ts.URL)
+		if err != nil {
+			if test.lines != 0 {
+				t.Fatalf("test %d expected no error, got: %v", i, err)
+			}
+			ts.Close()
+			continue
+		}
+		var lines int
+		s := bufio.NewScanner(res.Body)
+		for s.Scan() {
+			lines++
+		}
+		res.Body.Close()
+		ts.Close()
+		if lines != test.lines {
+			t.Errorf("test %d expected %d lines, got: %d", i, test.lines, lines)
+		}
+	}
+}
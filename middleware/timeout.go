@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// Timeout returns a middleware that bounds the total time spent handling a
+// request. The request context's deadline is set to the timeout, and when
+// the handler has not completed within the timeout, a 503 Service
+// Unavailable response is sent.
+//
+// As with http.TimeoutHandler, the handler's response is buffered until the
+// handler completes. Streaming requests (see IsStreaming) bypass the timeout.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		h := http.TimeoutHandler(next, timeout, http.StatusText(http.StatusServiceUnavailable))
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if IsStreaming(req) {
+				next.ServeHTTP(res, req)
+				return
+			}
+			h.ServeHTTP(res, req)
+		})
+	}
+}
+
+// MaxBody returns a middleware that limits the size of request bodies to n
+// bytes. Requests with a Content-Length exceeding the limit are rejected
+// with 413 Request Entity Too Large, and reads beyond the limit return an
+// error (see http.MaxBytesReader).
+func MaxBody(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if req.ContentLength > n {
+				http.Error(res, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			req.Body = http.MaxBytesReader(res, req.Body, n)
+			next.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	h := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, ok := req.Context().Deadline(); !ok {
+			t.Error("expected context deadline")
+		}
+		if req.URL.Path == "/slow" {
+			<-req.Context().Done()
+			return
+		}
+		res.Write([]byte("fast"))
+	}))
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/fast", http.StatusOK},
+		{"/slow", http.StatusServiceUnavailable},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+}
+
+func TestMaxBody(t *testing.T) {
+	h := MaxBody(5)(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.ReadAll(req.Body); err != nil {
+			http.Error(res, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		res.WriteHeader(http.StatusNoContent)
+	}))
+	tests := []struct {
+		body    string
+		chunked bool
+		status  int
+	}{
+		{"hello", false, http.StatusNoContent},
+		{"hello world", false, http.StatusRequestEntityTooLarge},
+		{"hello world", true, http.StatusRequestEntityTooLarge},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+		if test.chunked {
+			req.ContentLength = -1
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+}
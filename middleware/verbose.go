@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// VerboseKey is the route metadata key used to enable verbose request and
+// response body logging for a fraction of the route's requests (see
+// Verbose). The value is the sample rate, a float64 from 0 to 1.
+//
+// For example:
+//
+//	mux.Handle(goji.Post("/orders", goji.WithMeta(middleware.VerboseKey, 0.01)), h)
+const VerboseKey = "verbose"
+
+// VerboseRequest is a verbose request report, containing the request and
+// response headers and bodies.
+type VerboseRequest struct {
+	// Request is the request.
+	Request *http.Request
+	// Route is the matched route template (see goji.RouteTemplate).
+	Route string
+	// Name is the matched route name (see goji.RouteName).
+	Name string
+	// Params are the bound route params, with sensitive params redacted (see
+	// goji.RedactParams).
+	Params map[string]string
+	// Header is the request header, with sensitive headers redacted.
+	Header http.Header
+	// Body is the request body, truncated to the max body size (see
+	// WithVerboseMaxBody).
+	Body []byte
+	// Status is the response status.
+	Status int
+	// ResponseHeader is the response header, with sensitive headers redacted.
+	ResponseHeader http.Header
+	// ResponseBody is the response body, truncated to the max body size.
+	ResponseBody []byte
+	// Duration is the duration of the request.
+	Duration time.Duration
+}
+
+// Verbose returns a middleware that captures the request and response
+// bodies of a sampled fraction of the requests to routes with a verbose
+// sample rate (see VerboseKey), invoking the callback for each sampled
+// request. When the callback is nil, sampled requests are logged using the
+// default slog.Logger at the debug level.
+//
+// Verbose allows bodies to be logged in production for only the routes being
+// debugged, without drowning in logs. Verbose must be used with Mux.Use, as
+// it relies on the matched route.
+func Verbose(fn func(VerboseRequest), opts ...VerboseOption) func(http.Handler) http.Handler {
+	v := &verbose{
+		fn:      fn,
+		maxBody: 64 << 10,
+		rnd:     rand.Float64,
+	}
+	if v.fn == nil {
+		v.fn = LogVerbose
+	}
+	for _, o := range opts {
+		o(v)
+	}
+	return v.handler
+}
+
+// verbose is the verbose request logger.
+type verbose struct {
+	fn      func(VerboseRequest)
+	maxBody int64
+	rnd     func() float64
+}
+
+// handler satisfies the middleware signature.
+func (v *verbose) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if rate, _ := goji.Meta(req, VerboseKey).(float64); rate <= 0 || rate < 1 && v.rnd() >= rate {
+			next.ServeHTTP(res, req)
+			return
+		}
+		start := time.Now()
+		var body []byte
+		if req.Body != nil && req.Body != http.NoBody {
+			buf, err := io.ReadAll(io.LimitReader(req.Body, v.maxBody))
+			if err == nil {
+				body = buf
+			}
+			req.Body = readCloser{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+		}
+		w := &recordWriter{StatusWriter: goji.StatusWriter{ResponseWriter: res}, max: v.maxBody}
+		next.ServeHTTP(w, req)
+		v.fn(VerboseRequest{
+			Request:        req,
+			Route:          routeKey(req),
+			Name:           goji.RouteName(req),
+			Params:         goji.RedactedParams(req),
+			Header:         redactHeader(req.Header, "Authorization", "Cookie", "Proxy-Authorization"),
+			Body:           body,
+			Status:         w.Status(),
+			ResponseHeader: redactHeader(w.Header(), "Set-Cookie"),
+			ResponseBody:   w.buf.Bytes(),
+			Duration:       time.Since(start),
+		})
+	})
+}
+
+// LogVerbose logs the verbose request using the default slog.Logger at the
+// debug level.
+func LogVerbose(r VerboseRequest) {
+	attrs := []any{
+		"method", r.Request.Method,
+		"path", goji.RedactedPath(r.Request),
+		"route", r.Route,
+		"params", r.Params,
+		"header", r.Header,
+		"body", string(r.Body),
+		"status", r.Status,
+		"response_header", r.ResponseHeader,
+		"response_body", string(r.ResponseBody),
+		"duration", r.Duration,
+	}
+	if r.Name != "" {
+		attrs = append(attrs, "route_name", r.Name)
+	}
+	slog.DebugContext(r.Request.Context(), "verbose request", attrs...)
+}
+
+// VerboseOption is a verbose request logger option.
+type VerboseOption func(*verbose)
+
+// WithVerboseMaxBody is a verbose request logger option to set the maximum
+// size of captured request and response bodies (default 64 KiB).
+func WithVerboseMaxBody(n int64) VerboseOption {
+	return func(v *verbose) {
+		v.maxBody = n
+	}
+}
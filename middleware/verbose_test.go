@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestVerbose(t *testing.T) {
+	var reqs []VerboseRequest
+	v := &verbose{
+		fn:      func(r VerboseRequest) { reqs = append(reqs, r) },
+		maxBody: 4,
+	}
+	vals := []float64{0.1, 0.9}
+	v.rnd = func() float64 {
+		r := vals[0]
+		vals = vals[1:]
+		return r
+	}
+	m := goji.New()
+	m.Use(v.handler)
+	echo := func(res http.ResponseWriter, req *http.Request) {
+		buf, _ := io.ReadAll(req.Body)
+		res.Header().Set("Set-Cookie", "session=secret")
+		res.WriteHeader(http.StatusCreated)
+		res.Write(buf)
+	}
+	m.HandleFunc(goji.Post("/plain"), echo)
+	m.HandleFunc(goji.Post("/sampled", goji.WithMeta(VerboseKey, 0.5)), echo)
+	m.HandleFunc(goji.Post("/all", goji.WithMeta(VerboseKey, 1.0)), echo)
+	tests := []struct {
+		path string
+		exp  int
+	}{
+		{"/plain", 0},
+		{"/sampled", 1},
+		{"/sampled", 1},
+		{"/all", 2},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("POST", test.path, strings.NewReader("hello"))
+		req.Header.Set("Authorization", "Bearer token")
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != "hello" {
+			t.Errorf("test %d expected %q, got: %q", i, "hello", s)
+		}
+		if len(reqs) != test.exp {
+			t.Errorf("test %d expected %d reports, got: %d", i, test.exp, len(reqs))
+		}
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 reports, got: %d", len(reqs))
+	}
+	r := reqs[0]
+	if r.Route != "/sampled" || r.Status != http.StatusCreated || string(r.Body) != "hell" || string(r.ResponseBody) != "hell" {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if s := r.Header.Get("Authorization"); s != Redacted {
+		t.Errorf("expected %q, got: %q", Redacted, s)
+	}
+	if s := r.ResponseHeader.Get("Set-Cookie"); s != Redacted {
+		t.Errorf("expected %q, got: %q", Redacted, s)
+	}
+}
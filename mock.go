@@ -0,0 +1,70 @@
+package goji
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// MockRule is a canned response rule for a mock upstream (see
+// MockUpstream).
+type MockRule struct {
+	// Method is the matching request method, or empty to match all methods.
+	Method string
+	// Path is the matching unmatched path suffix, which may be a pattern (see
+	// path.Match), such as "/users/*", or empty to match all paths.
+	Path string
+	// Status is the response status (default 200 OK).
+	Status int
+	// Header is the response header.
+	Header http.Header
+	// Body is the response body.
+	Body string
+}
+
+// MockUpstream returns a stub handler serving canned responses for wildcard
+// routes, such as proxy routes, in development and test environments. The
+// response is that of the first rule matching the request's method and
+// unmatched path suffix (see Path), and 502 Bad Gateway when no rule
+// matches.
+//
+// For example:
+//
+//	upstream := http.Handler(proxy)
+//	if dev {
+//		upstream = goji.MockUpstream(
+//			goji.MockRule{Method: "GET", Path: "/users/*", Body: `{"name":"carl"}`},
+//			goji.MockRule{Method: "POST", Path: "/users", Status: http.StatusCreated},
+//		)
+//	}
+//	mux.Handle(goji.NewPathSpec("/api/*"), upstream)
+func MockUpstream(rules ...MockRule) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		suffix := Path(req.Context())
+		if suffix == "" {
+			suffix = req.URL.Path
+		}
+		for _, rule := range rules {
+			if rule.Method != "" && rule.Method != req.Method {
+				continue
+			}
+			if ok, _ := path.Match(rule.Path, suffix); rule.Path != "" && !ok {
+				continue
+			}
+			for k, v := range rule.Header {
+				res.Header()[k] = v
+			}
+			if res.Header().Get("Content-Length") == "" {
+				res.Header().Set("Content-Length", strconv.Itoa(len(rule.Body)))
+			}
+			status := rule.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			res.WriteHeader(status)
+			res.Write([]byte(rule.Body))
+			return
+		}
+		http.Error(res, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+	})
+}
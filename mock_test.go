@@ -0,0 +1,38 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMockUpstream(t *testing.T) {
+	m := New()
+	m.Handle(NewPathSpec("/api/*"), MockUpstream(
+		MockRule{Method: "GET", Path: "/users/*", Header: http.Header{"Content-Type": {"application/json"}}, Body: `{"name":"carl"}`},
+		MockRule{Method: "POST", Path: "/users", Status: http.StatusCreated},
+		MockRule{Path: "/health", Body: "ok"},
+	))
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"GET", "/api/users/carl", http.StatusOK, `{"name":"carl"}`},
+		{"POST", "/api/users", http.StatusCreated, ""},
+		{"GET", "/api/users", http.StatusBadGateway, "Bad Gateway\n"},
+		{"PUT", "/api/health", http.StatusOK, "ok"},
+		{"GET", "/api/other", http.StatusBadGateway, "Bad Gateway\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.body {
+			t.Errorf("test %d expected %q, got: %q", i, test.body, s)
+		}
+	}
+}
@@ -2,7 +2,14 @@ package goji
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Mux is a HTTP multiplexer and router similar to net/http.ServeMux.
@@ -23,7 +30,24 @@ type Mux struct {
 	handler    http.Handler
 	middleware []func(http.Handler) http.Handler
 	notFound   http.Handler
+	auth       Authenticator
+	options    func(http.ResponseWriter, *http.Request, []string)
+	notAllowed http.Handler
+	routeLimit time.Duration
+	isolate    bool
+	isolateFor time.Duration
 	sub        bool
+	basePath   string
+	forwarded  bool
+	routeHdr   bool
+	headGet    bool
+	mu         sync.RWMutex
+	names      map[string]Matcher
+	rels       map[string][]relation
+	draining   int32
+	onRouted   []func(*http.Request, Matcher)
+	onResponse []func(*http.Request, int, time.Duration)
+	events     []func(Event)
 }
 
 // New returns a new Mux with no configured middleware using the default
@@ -50,9 +74,35 @@ func NewSubMux(opts ...MuxOption) *Mux {
 func (m *Mux) buildChain() {
 	m.handler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		if h := req.Context().Value(handlerKey); h != nil {
-			h.(http.Handler).ServeHTTP(res, req)
+			if m.routeHdr {
+				res.Header().Set("X-Route", RouteTemplate(req))
+				if name := RouteName(req); name != "" {
+					res.Header().Set("X-Route-Name", name)
+				}
+			}
+			if authPolicy(res, req, m.auth) && warmUpPolicy(res, req) && bodyPolicy(res, req) {
+				h.(http.Handler).ServeHTTP(cacheControl(res, req), req)
+			}
 			return
 		}
+		if d, ok := req.Context().Value(dispatchKey).(*dispatchState); ok && d.mux == m && !d.routed {
+			// outside the base path
+			m.notFound.ServeHTTP(res, req)
+			return
+		}
+		if req.Method == "OPTIONS" && m.options != nil {
+			if allowed := m.Allowed(req); len(allowed) != 0 {
+				m.options(res, req, allowed)
+				return
+			}
+		}
+		if m.notAllowed != nil {
+			if allowed := m.allowed(req); len(allowed) != 0 {
+				res.Header().Set("Allow", strings.Join(allowed, ", "))
+				m.notAllowed.ServeHTTP(res, req)
+				return
+			}
+		}
 		m.notFound.ServeHTTP(res, req)
 	})
 	for i := len(m.middleware) - 1; i >= 0; i-- {
@@ -123,6 +173,34 @@ func (m *Mux) Use(middleware func(http.Handler) http.Handler) {
 	m.buildChain()
 }
 
+// Middlewares returns a copy of the Mux's middleware stack, in the order in
+// which the middleware are called.
+func (m *Mux) Middlewares() []func(http.Handler) http.Handler {
+	return slices.Clone(m.middleware)
+}
+
+// UseAt inserts a middleware into the Mux's middleware stack at index i,
+// allowing frameworks built on the Mux to insert middleware (such as tracing
+// before logging) regardless of registration order. UseAt(len, mw) is
+// equivalent to Use(mw). UseAt panics when i is out of range.
+//
+// As with Use, it is not safe to modify the middleware stack concurrently
+// with requests.
+func (m *Mux) UseAt(i int, middleware func(http.Handler) http.Handler) {
+	m.middleware = slices.Insert(m.middleware, i, middleware)
+	m.buildChain()
+}
+
+// RemoveMiddleware removes the middleware at index i from the Mux's
+// middleware stack. RemoveMiddleware panics when i is out of range.
+//
+// As with Use, it is not safe to modify the middleware stack concurrently
+// with requests.
+func (m *Mux) RemoveMiddleware(i int) {
+	m.middleware = slices.Delete(m.middleware, i, i+1)
+	m.buildChain()
+}
+
 // Handle adds a new route to the Mux. Requests that match the given Matcher will
 // be dispatched to the given http.Handler.
 //
@@ -141,24 +219,459 @@ func (m *Mux) Use(middleware func(http.Handler) http.Handler) {
 // 		}
 // 	}
 //
+// Handle returns a token identifying the route, that can be used to later
+// remove or replace the route (see Remove and Replace).
+//
 // It is not safe to concurrently register routes from multiple goroutines, or to
-// register routes concurrently with requests.
-func (m *Mux) Handle(matcher Matcher, handler http.Handler) {
-	m.router.Handle(matcher, handler)
+// register routes concurrently with requests, unless the Mux was created with
+// the Dynamic option.
+func (m *Mux) Handle(matcher Matcher, handler http.Handler) RouteToken {
+	var token RouteToken
+	rm := matcher
+	if m.isolate {
+		rm = isolate(matcher, m.isolateFor)
+	}
+	m.mu.Lock()
+	if r, ok := m.router.(interface {
+		handle(Matcher, http.Handler) uint64
+	}); ok {
+		token.id = r.handle(rm, handler)
+	} else {
+		m.router.Handle(rm, handler)
+	}
+	m.name(matcher)
+	m.mu.Unlock()
+	if len(m.events) != 0 {
+		m.emit(Event{
+			Type:    RouteAdded,
+			Time:    time.Now(),
+			Matcher: matcher,
+			Handler: handler,
+		})
+	}
+	return token
 }
 
 // HandleFunc adds a new route to the Mux. It is equivalent to calling Handle on a
 // handler wrapped with http.HandlerFunc, and is provided only for convenience.
-func (m *Mux) HandleFunc(matcher Matcher, handler func(http.ResponseWriter, *http.Request)) {
-	m.Handle(matcher, http.HandlerFunc(handler))
+func (m *Mux) HandleFunc(matcher Matcher, handler func(http.ResponseWriter, *http.Request)) RouteToken {
+	return m.Handle(matcher, http.HandlerFunc(handler))
+}
+
+// RouteToken identifies a route added to a Mux (see Mux.Handle). The zero
+// RouteToken identifies no route.
+type RouteToken struct {
+	id uint64
+}
+
+// Remove removes the route identified by the token from the Mux, for example
+// to unload a plugin without rebuilding the Mux. The router's tries are
+// rebuilt without the route. Returns ErrUnknownRoute when the route does not
+// exist (or was already removed), or when the Mux's router does not support
+// removal.
+//
+// It is not safe to remove routes concurrently with requests, unless the Mux
+// was created with the Dynamic option.
+func (m *Mux) Remove(token RouteToken) error {
+	r, ok := m.router.(interface {
+		remove(uint64) (route, bool)
+	})
+	if !ok || token.id == 0 {
+		return ErrUnknownRoute
+	}
+	m.mu.Lock()
+	rt, ok := r.remove(token.id)
+	if ok {
+		m.unname(registered(rt.matcher))
+	}
+	m.mu.Unlock()
+	if !ok {
+		return ErrUnknownRoute
+	}
+	if len(m.events) != 0 {
+		m.emit(Event{
+			Type:    RouteRemoved,
+			Time:    time.Now(),
+			Matcher: registered(rt.matcher),
+			Handler: rt.handler,
+		})
+	}
+	return nil
+}
+
+// Replace replaces the Matcher and handler of the route identified by the
+// token, retaining the route's position in the routing order. Returns
+// ErrUnknownRoute when the route does not exist, or when the Mux's router
+// does not support replacement.
+//
+// It is not safe to replace routes concurrently with requests, unless the Mux
+// was created with the Dynamic option.
+func (m *Mux) Replace(token RouteToken, matcher Matcher, handler http.Handler) error {
+	r, ok := m.router.(interface {
+		replace(uint64, Matcher, http.Handler) (route, bool)
+	})
+	if !ok || token.id == 0 {
+		return ErrUnknownRoute
+	}
+	rm := matcher
+	if m.isolate {
+		rm = isolate(matcher, m.isolateFor)
+	}
+	m.mu.Lock()
+	rt, ok := r.replace(token.id, rm, handler)
+	if ok {
+		m.unname(registered(rt.matcher))
+		m.name(matcher)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return ErrUnknownRoute
+	}
+	if len(m.events) != 0 {
+		m.emit(Event{
+			Type:    RouteRemoved,
+			Time:    time.Now(),
+			Matcher: registered(rt.matcher),
+			Handler: rt.handler,
+		})
+		m.emit(Event{
+			Type:    RouteAdded,
+			Time:    time.Now(),
+			Matcher: matcher,
+			Handler: handler,
+		})
+	}
+	return nil
+}
+
+// Delete adds a new route to the Mux for DELETE requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to
+// calling HandleFunc with the Delete path spec.
+func (m *Mux) Delete(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Delete(spec, opts...), handler)
+}
+
+// Get adds a new route to the Mux for GET and HEAD requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to calling
+// HandleFunc with the Get path spec, and is provided only for convenience:
+//
+//	mux.Get("/user/:name", user)
+func (m *Mux) Get(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Get(spec, opts...), handler)
+}
+
+// Head adds a new route to the Mux for HEAD requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to
+// calling HandleFunc with the Head path spec.
+func (m *Mux) Head(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Head(spec, opts...), handler)
+}
+
+// Options adds a new route to the Mux for OPTIONS requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to
+// calling HandleFunc with the Options path spec.
+func (m *Mux) Options(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Options(spec, opts...), handler)
+}
+
+// Patch adds a new route to the Mux for PATCH requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to
+// calling HandleFunc with the Patch path spec.
+func (m *Mux) Patch(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Patch(spec, opts...), handler)
+}
+
+// Post adds a new route to the Mux for POST requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to
+// calling HandleFunc with the Post path spec.
+func (m *Mux) Post(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Post(spec, opts...), handler)
+}
+
+// Put adds a new route to the Mux for PUT requests matching the path
+// spec, returning the route's token (see Handle). It is equivalent to
+// calling HandleFunc with the Put path spec.
+func (m *Mux) Put(spec string, handler func(http.ResponseWriter, *http.Request), opts ...PathSpecOption) RouteToken {
+	return m.HandleFunc(Put(spec, opts...), handler)
+}
+
+// Mount adds a new route to the Mux for requests under the path prefix,
+// handled by the handler with the prefix stripped from the request's URL
+// path, and returns the route's token (see Handle). Mount allows arbitrary
+// third-party http.Handlers to be served under a subtree:
+//
+//	mux.Mount("/metrics/", promhttp.Handler())
+//	mux.Mount("/debug/", http.DefaultServeMux)
+//
+// The route's PathSpec is the prefix with a trailing wildcard (for example,
+// "/metrics/*"), and the stripped URL path is the wildcard's remaining path
+// (see Path), preserving the request's escaped path. As with other wildcard
+// path specs, the prefix itself without a trailing slash is not matched.
+//
+// Mounted handlers are reported by Walk, and the routes of mounted sub-Muxes
+// are included in Routes.
+func (m *Mux) Mount(prefix string, handler http.Handler, opts ...PathSpecOption) RouteToken {
+	return m.Handle(NewPathSpec(strings.TrimSuffix(prefix, "/")+"/*", opts...), mounted{handler})
+}
+
+// mounted is a handler mounted under a path prefix (see Mux.Mount).
+type mounted struct {
+	handler http.Handler
+}
+
+// ServeHTTP satisfies the http.Handler interface, serving the request with
+// the mounted handler, with the prefix stripped from the request's URL path.
+func (h mounted) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	path := Path(req.Context())
+	if path == "" {
+		path = "/"
+	}
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		http.Error(res, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	r := new(http.Request)
+	*r = *req
+	r.URL = new(url.URL)
+	*r.URL = *req.URL
+	r.URL.Path, r.URL.RawPath = unescaped, path
+	h.handler.ServeHTTP(res, r)
+}
+
+// OnRouted adds a hook invoked after a request has been routed, with the
+// routed request and the matched Matcher (nil when no route matched), prior
+// to the middleware stack.
+//
+// Hooks provide a lightweight extension point for observability
+// integrations. It is not safe to add hooks concurrently with requests.
+func (m *Mux) OnRouted(f func(*http.Request, Matcher)) {
+	m.onRouted = append(m.onRouted, f)
+}
+
+// OnResponse adds a hook invoked after a request has been dispatched, with
+// the routed request, the response status, and the duration of the dispatch
+// (including the middleware stack).
+//
+// It is not safe to add hooks concurrently with requests.
+func (m *Mux) OnResponse(f func(*http.Request, int, time.Duration)) {
+	m.onResponse = append(m.onResponse, f)
 }
 
 // ServeHTTP satisfies the http.Handler interface.
 func (m *Mux) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	if !m.sub {
-		req = req.WithContext(context.WithValue(req.Context(), pathKey, req.URL.EscapedPath()))
+		ctx := context.WithValue(req.Context(), pathKey, req.URL.EscapedPath())
+		ctx = context.WithValue(ctx, muxKey, m)
+		ctx = context.WithValue(ctx, storeKey, new(store))
+		if prefix := req.Header.Get("X-Forwarded-Prefix"); m.forwarded && prefix != "" {
+			ctx = WithForwardedPrefix(ctx, prefix)
+		}
+		if prefix := ForwardedPrefix(ctx); prefix != "" {
+			if path, ok := trimBase(Path(ctx), prefix); ok {
+				ctx = context.WithValue(ctx, pathKey, path)
+			}
+		}
+		req = req.WithContext(ctx)
+	}
+	routed := true
+	if m.basePath != "" {
+		var path string
+		if path, routed = trimBase(Path(req.Context()), m.basePath); routed {
+			req = req.WithContext(context.WithValue(req.Context(), pathKey, path))
+		}
+	}
+	req = req.WithContext(context.WithValue(req.Context(), dispatchKey, &dispatchState{mux: m, routed: routed, path: Path(req.Context())}))
+	var head *headWriter
+	if routed {
+		req = m.route(req)
+		if m.headGet && req.Method == "HEAD" && Matched(req) == nil {
+			if r := m.routeHead(req); r != nil {
+				head = &headWriter{ResponseWriter: res}
+				req, res = r, head
+			}
+		}
+	}
+	req = authenticate(req, m.auth)
+	for _, f := range m.onRouted {
+		f(req, Matched(req))
+	}
+	m.dispatch(res, req)
+	// not deferred, so that the header is not written when the handler
+	// panics
+	if head != nil {
+		head.finish()
+	}
+}
+
+// dispatchState is the dispatch state of the Mux serving a request.
+type dispatchState struct {
+	mux *Mux
+	// routed is whether or not the request's path is within the Mux's base
+	// path (see WithBasePath).
+	routed bool
+	// path is the request's path prior to routing.
+	path string
+}
+
+// dispatch dispatches the routed request to the handler chain, invoking the
+// response hooks (see OnResponse).
+func (m *Mux) dispatch(res http.ResponseWriter, req *http.Request) {
+	if len(m.onResponse) == 0 {
+		m.handler.ServeHTTP(res, req)
+		return
+	}
+	w, start := &StatusWriter{ResponseWriter: res}, time.Now()
+	m.handler.ServeHTTP(w, req)
+	d := time.Since(start)
+	for _, f := range m.onResponse {
+		f(req, w.Status(), d)
+	}
+}
+
+// routeHead routes the HEAD request as a GET request, returning the routed
+// HEAD request, or nil when no route matched.
+func (m *Mux) routeHead(req *http.Request) *http.Request {
+	r := *req
+	r.Method = "GET"
+	routed := m.route(&r)
+	if Matched(routed) == nil {
+		return nil
+	}
+	routed.Method = "HEAD"
+	return routed
+}
+
+// route routes the request, bounding the time spent routing by the route
+// timeout (see WithRouteTimeout).
+func (m *Mux) route(req *http.Request) *http.Request {
+	if m.routeLimit <= 0 {
+		return m.router.Route(req)
+	}
+	type result struct {
+		req *http.Request
+		v   interface{}
+	}
+	ch := make(chan result, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				ch <- result{v: v}
+			}
+		}()
+		ch <- result{req: m.router.Route(req)}
+	}()
+	t := time.NewTimer(m.routeLimit)
+	defer t.Stop()
+	select {
+	case r := <-ch:
+		if r.v != nil {
+			panic(r.v)
+		}
+		return r.req
+	case <-t.C:
+		slog.Warn("routing timeout exceeded", "method", req.Method, "path", req.URL.Path, "timeout", m.routeLimit)
+		return req
+	}
+}
+
+// URLFor returns the externally valid URL for the path for the request,
+// including the request's forwarded prefix (see ForwardedPrefix) and the
+// Mux's base path.
+func (m *Mux) URLFor(req *http.Request, path string) string {
+	return ForwardedPrefix(req.Context()) + m.basePath + path
+}
+
+// BasePath returns the Mux's base path.
+func (m *Mux) BasePath() string {
+	return m.basePath
+}
+
+// trimBase trims the base path from the path, returning false when the path
+// is not under the base path.
+func trimBase(path, base string) (string, bool) {
+	switch {
+	case path == base:
+		return "/", true
+	case strings.HasPrefix(path, base) && path[len(base)] == '/':
+		return path[len(base):], true
+	}
+	return "", false
+}
+
+// Allowed returns the methods of the routes registered for the request's
+// path, computed from the route table, or nil when no method specific routes
+// match the request's path (or the router does not support computing allowed
+// methods).
+func (m *Mux) Allowed(req *http.Request) []string {
+	if r, ok := m.router.(interface {
+		Allowed(*http.Request) []string
+	}); ok {
+		return r.Allowed(req)
+	}
+	return nil
+}
+
+// allowed returns the methods of the routes registered for the request's
+// path, excluding OPTIONS when OPTIONS requests are not handled for the path.
+func (m *Mux) allowed(req *http.Request) []string {
+	allowed := m.Allowed(req)
+	if m.options != nil || len(allowed) == 0 {
+		return allowed
+	}
+	r := *req
+	r.Method = "OPTIONS"
+	if Matched(m.router.Route(&r)) != nil {
+		return allowed
+	}
+	return slices.DeleteFunc(allowed, func(method string) bool {
+		return method == "OPTIONS"
+	})
+}
+
+// Compile compacts the Mux's router after routes have been registered,
+// reducing the memory used by large route tables. Routes may still be added
+// after calling Compile.
+//
+// It is not safe to compile concurrently with requests.
+func (m *Mux) Compile() {
+	if r, ok := m.router.(interface{ Compile() }); ok {
+		r.Compile()
+	}
+}
+
+// Stats returns statistics on the shape and memory use of the Mux's router,
+// allowing users with large numbers of routes to reason about memory use and
+// lookup behavior. Returns zero stats when the router does not support
+// statistics.
+func (m *Mux) Stats() RouterStats {
+	if r, ok := m.router.(interface{ Stats() RouterStats }); ok {
+		return r.Stats()
+	}
+	return RouterStats{}
+}
+
+// routes returns the Mux's routes, in registration order, or nil when the
+// router does not support listing routes.
+func (m *Mux) routes() []route {
+	if r, ok := m.router.(interface{ list() []route }); ok {
+		return r.list()
 	}
-	m.handler.ServeHTTP(res, m.router.Route(req))
+	return nil
+}
+
+// Drain marks the Mux as draining, signaling (for example, to readiness
+// checks) that the Mux should no longer receive new traffic. Requests
+// continue to be served normally while draining.
+//
+// Drain is safe to call concurrently with requests.
+func (m *Mux) Drain() {
+	atomic.StoreInt32(&m.draining, 1)
+}
+
+// Draining returns whether or not the Mux is draining.
+func (m *Mux) Draining() bool {
+	return atomic.LoadInt32(&m.draining) != 0
 }
 
 // MuxOption is a Mux option.
@@ -182,3 +695,110 @@ func NotFoundFunc(f http.HandlerFunc) MuxOption {
 		m.notFound = f
 	}
 }
+
+// WithBasePath is a mux option to set a base path (for example, "/app") that
+// is implicitly prefixed to all registered routes, for applications deployed
+// under a sub-path of a shared domain. Requests for paths not under the base
+// path are not routed (and are handled by the not found handler).
+//
+// The URL and URLFor methods generate URLs that include the base path.
+func WithBasePath(basePath string) MuxOption {
+	return func(m *Mux) {
+		m.basePath = cleanPrefix(basePath)
+	}
+}
+
+// TrustForwardedPrefix is a mux option to trust the X-Forwarded-Prefix
+// header sent by a reverse proxy, making the prefix available via
+// ForwardedPrefix and including it in URLs generated by URLFor. When the
+// request's path has the forwarded prefix (that is, the proxy did not strip
+// the prefix), the prefix is removed prior to routing.
+//
+// Only use with a reverse proxy that sets (or strips) the header.
+func TrustForwardedPrefix(m *Mux) {
+	m.forwarded = true
+}
+
+// RouteHeader is a mux option to set the X-Route response header to the
+// matched route's template (see RouteTemplate), and the X-Route-Name
+// response header to the matched route's name (see RouteName), making it
+// easy to verify which route served a response during integration testing.
+//
+// RouteHeader exposes the route table to clients, and is intended for use in
+// development and staging environments.
+func RouteHeader(m *Mux) {
+	m.routeHdr = true
+}
+
+// HeadFallback is a mux option to route HEAD requests that do not match a
+// route as GET requests, for routes registered only for GET (such as
+// NewPathSpec with WithMethod("GET")). The response body is discarded, and
+// the Content-Length response header is set to the length of the discarded
+// body when not set by the handler.
+func HeadFallback(m *Mux) {
+	m.headGet = true
+}
+
+// WithAutoOptions is a mux option to automatically respond to OPTIONS
+// requests for paths without an OPTIONS route, using the responder with the
+// methods registered for the request's path (see Mux.Allowed). When the
+// responder is nil, DefaultOptions is used.
+//
+// As the responder is invoked after the middleware stack, CORS middleware
+// continues to apply (see middleware.WithRouteMethods).
+func WithAutoOptions(f func(http.ResponseWriter, *http.Request, []string)) MuxOption {
+	return func(m *Mux) {
+		if f == nil {
+			f = DefaultOptions
+		}
+		m.options = f
+	}
+}
+
+// MethodNotAllowed is a mux option to handle requests for paths with routes
+// registered only for other methods using the handler, with the Allow
+// response header set to the methods registered for the request's path (see
+// Mux.Allowed), instead of the not found handler. When the handler is nil,
+// DefaultMethodNotAllowed is used.
+func MethodNotAllowed(handler http.Handler) MuxOption {
+	return func(m *Mux) {
+		if handler == nil {
+			handler = http.HandlerFunc(DefaultMethodNotAllowed)
+		}
+		m.notAllowed = handler
+	}
+}
+
+// DefaultMethodNotAllowed is the default method not allowed handler,
+// responding with 405 Method Not Allowed.
+func DefaultMethodNotAllowed(res http.ResponseWriter, req *http.Request) {
+	http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// DefaultOptions is the default OPTIONS responder, responding with 204 No
+// Content with the Allow header set to the allowed methods. For CORS
+// preflight requests, the Access-Control-Allow-Methods header is also set to
+// the allowed methods, when not already set.
+func DefaultOptions(res http.ResponseWriter, req *http.Request, allowed []string) {
+	methods := strings.Join(allowed, ", ")
+	res.Header().Set("Allow", methods)
+	if req.Header.Get("Access-Control-Request-Method") != "" && res.Header().Get("Access-Control-Allow-Methods") == "" {
+		res.Header().Set("Access-Control-Allow-Methods", methods)
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// WithRouteTimeout is a mux option to bound the time spent routing each
+// request, protecting the server from misbehaving Matchers (such as
+// pathological third-party Matchers, or custom Matchers in very large route
+// tables). Requests that are not routed within the timeout are handled by
+// the not found handler, and a warning is logged using the default
+// slog.Logger.
+//
+// Routing is performed on a separate goroutine when the timeout is set, and
+// a Matcher that never returns will leak its goroutine.
+func WithRouteTimeout(timeout time.Duration) MuxOption {
+	return func(m *Mux) {
+		m.routeLimit = timeout
+	}
+}
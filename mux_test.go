@@ -2,8 +2,13 @@ package goji
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMuxHandlerInterface(t *testing.T) {
@@ -72,6 +77,30 @@ func TestMiddlewareReconfigure(t *testing.T) {
 	expectSequence(t, ch, "before one", "before two", "before three", "handler", "after three", "after two", "after one")
 }
 
+func TestMiddlewareManipulation(t *testing.T) {
+	m := New()
+	ch := make(chan string, 10)
+	m.Use(makeMiddleware(ch, "one"))
+	m.Use(makeMiddleware(ch, "two"))
+	m.Handle(boolMatcher(true), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		ch <- "handler"
+	}))
+	m.UseAt(0, makeMiddleware(ch, "zero"))
+	m.UseAt(2, makeMiddleware(ch, "three"))
+	if n := len(m.Middlewares()); n != 4 {
+		t.Errorf("expected 4 middlewares, got: %d", n)
+	}
+	m.ServeHTTP(resreq())
+	expectSequence(t, ch, "before zero", "before one", "before three", "before two", "handler", "after two", "after three", "after one", "after zero")
+	m.RemoveMiddleware(1)
+	m.ServeHTTP(resreq())
+	expectSequence(t, ch, "before zero", "before three", "before two", "handler", "after two", "after three", "after zero")
+	mws := m.Middlewares()
+	mws[0] = makeMiddleware(ch, "copy")
+	m.ServeHTTP(resreq())
+	expectSequence(t, ch, "before zero", "before three", "before two", "handler", "after two", "after three", "after zero")
+}
+
 func TestHandle(t *testing.T) {
 	m := New()
 	var called bool
@@ -132,3 +161,606 @@ func makeMiddleware(ch chan string, name string) func(http.Handler) http.Handler
 		})
 	}
 }
+
+func TestDrain(t *testing.T) {
+	m := New()
+	if m.Draining() {
+		t.Error("expected mux to not be draining")
+	}
+	m.Drain()
+	if !m.Draining() {
+		t.Error("expected mux to be draining")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	m := New()
+	p := Get("/hello")
+	m.HandleFunc(p, func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusAccepted)
+	})
+	m.HandleFunc(Get("/early"), func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusEarlyHints)
+		res.WriteHeader(http.StatusCreated)
+	})
+	var routed []Matcher
+	var statuses []int
+	m.OnRouted(func(req *http.Request, matcher Matcher) {
+		routed = append(routed, matcher)
+	})
+	m.OnResponse(func(req *http.Request, status int, d time.Duration) {
+		if d <= 0 {
+			t.Errorf("expected positive duration, got: %v", d)
+		}
+		statuses = append(statuses, status)
+	})
+	for _, path := range []string{"/hello", "/nope", "/early"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	if len(routed) != 3 || routed[0] != p || routed[1] != nil {
+		t.Errorf("expected [%v <nil> ...], got: %v", p, routed)
+	}
+	if exp := []int{http.StatusAccepted, http.StatusNotFound, http.StatusCreated}; !reflect.DeepEqual(statuses, exp) {
+		t.Errorf("expected %v, got: %v", exp, statuses)
+	}
+}
+
+func TestHooksHijack(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/ws"), func(res http.ResponseWriter, req *http.Request) {
+		conn, _, err := http.NewResponseController(res).Hijack()
+		if err != nil {
+			t.Errorf("expected no error, got: %v", err)
+			return
+		}
+		conn.Close()
+	})
+	statuses := make(chan int, 1)
+	m.OnResponse(func(req *http.Request, status int, d time.Duration) {
+		statuses <- status
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+	if res, err := http.Get(s.URL + "/ws"); err == nil {
+		res.Body.Close()
+	}
+	if status := <-statuses; status != http.StatusSwitchingProtocols {
+		t.Errorf("expected %d, got: %d", http.StatusSwitchingProtocols, status)
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	m := New(WithBasePath("app/"))
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("index"))
+	})
+	m.HandleFunc(Get("/users/:id"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(Param(req, "id")))
+	})
+	tests := []struct {
+		path   string
+		status int
+		exp    string
+	}{
+		{"/app", http.StatusOK, "index"},
+		{"/app/", http.StatusOK, "index"},
+		{"/app/users/carl", http.StatusOK, "carl"},
+		{"/users/carl", http.StatusNotFound, ""},
+		{"/apps/users/carl", http.StatusNotFound, ""},
+		{"/", http.StatusNotFound, ""},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if test.status == http.StatusOK && res.Body.String() != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, res.Body.String())
+		}
+	}
+	if s := m.URLFor(httptest.NewRequest("GET", "/", nil), "/users/carl"); s != "/app/users/carl" {
+		t.Errorf("expected %q, got: %q", "/app/users/carl", s)
+	}
+	if s := m.BasePath(); s != "/app" {
+		t.Errorf("expected %q, got: %q", "/app", s)
+	}
+
+	// requests outside the base path are not found
+	m = New(WithBasePath("/api"), MethodNotAllowed(nil), WithAutoOptions(DefaultOptions))
+	m.HandleFunc(Get("/users"), func(http.ResponseWriter, *http.Request) {})
+	for i, test := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{"POST", "/api/users", http.StatusMethodNotAllowed},
+		{"OPTIONS", "/api/users", http.StatusNoContent},
+		{"GET", "/users", http.StatusNotFound},
+		{"POST", "/users", http.StatusNotFound},
+		{"OPTIONS", "/users", http.StatusNotFound},
+	} {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if allow := res.Header().Get("Allow"); test.status == http.StatusNotFound && allow != "" {
+			t.Errorf("test %d expected no Allow header, got: %q", i, allow)
+		}
+	}
+}
+
+func TestTrustForwardedPrefix(t *testing.T) {
+	m := New(TrustForwardedPrefix)
+	m.HandleFunc(Get("/users"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(m.URLFor(req, "/users")))
+	})
+	tests := []struct {
+		path   string
+		prefix string
+		exp    string
+	}{
+		{"/users", "", "/users"},
+		{"/users", "/app", "/app/users"},
+		{"/users", "app/", "/app/users"},
+		{"/app/users", "/app", "/app/users"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		if test.prefix != "" {
+			req.Header.Set("X-Forwarded-Prefix", test.prefix)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	// untrusted
+	m = New()
+	m.HandleFunc(Get("/users"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(m.URLFor(req, "/users")))
+	})
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("X-Forwarded-Prefix", "/app")
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, req)
+	if s := res.Body.String(); s != "/users" {
+		t.Errorf("expected %q, got: %q", "/users", s)
+	}
+}
+
+func TestAutoOptions(t *testing.T) {
+	h := func(http.ResponseWriter, *http.Request) {}
+	for _, dynamic := range []bool{false, true} {
+		opts := []MuxOption{WithAutoOptions(nil)}
+		if dynamic {
+			opts = append(opts, Dynamic)
+		}
+		m := New(opts...)
+		m.HandleFunc(Get("/users"), h)
+		m.HandleFunc(Post("/users"), h)
+		m.HandleFunc(Delete("/users/:id"), h)
+		m.HandleFunc(Options("/custom"), func(res http.ResponseWriter, req *http.Request) {
+			res.Write([]byte("custom"))
+		})
+		m.HandleFunc(Put("/custom"), h)
+		m.HandleFunc(NewPathSpec("/any"), h)
+		tests := []struct {
+			path   string
+			acrm   bool
+			status int
+			allow  string
+			acam   string
+		}{
+			{"/users", false, http.StatusNoContent, "GET, HEAD, OPTIONS, POST", ""},
+			{"/users", true, http.StatusNoContent, "GET, HEAD, OPTIONS, POST", "GET, HEAD, OPTIONS, POST"},
+			{"/users/1", false, http.StatusNoContent, "DELETE, OPTIONS", ""},
+			{"/custom", false, http.StatusOK, "", ""},
+			{"/any", false, http.StatusOK, "", ""},
+			{"/missing", false, http.StatusNotFound, "", ""},
+		}
+		for i, test := range tests {
+			req := httptest.NewRequest("OPTIONS", test.path, nil)
+			if test.acrm {
+				req.Header.Set("Origin", "https://example.com")
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			res := httptest.NewRecorder()
+			m.ServeHTTP(res, req)
+			if res.Code != test.status {
+				t.Errorf("dynamic %t test %d expected %d, got: %d", dynamic, i, test.status, res.Code)
+			}
+			if s := res.Header().Get("Allow"); s != test.allow {
+				t.Errorf("dynamic %t test %d expected Allow %q, got: %q", dynamic, i, test.allow, s)
+			}
+			if s := res.Header().Get("Access-Control-Allow-Methods"); s != test.acam {
+				t.Errorf("dynamic %t test %d expected Access-Control-Allow-Methods %q, got: %q", dynamic, i, test.acam, s)
+			}
+		}
+	}
+
+	// disabled
+	m := New()
+	m.HandleFunc(Get("/users"), h)
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("OPTIONS", "/users", nil))
+	if res.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got: %d", http.StatusNotFound, res.Code)
+	}
+}
+
+// slowMatcher is a Matcher that sleeps before matching.
+type slowMatcher struct {
+	d time.Duration
+}
+
+func (m slowMatcher) Match(req *http.Request) *http.Request {
+	if m.d < 0 {
+		panic("slow matcher")
+	}
+	time.Sleep(m.d)
+	return req
+}
+
+func (slowMatcher) Methods() map[string]struct{} {
+	return nil
+}
+
+func (slowMatcher) Prefix() string {
+	return ""
+}
+
+func TestRouteTimeout(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		status int
+	}{
+		{0, http.StatusOK},
+		{time.Second, http.StatusNotFound},
+	}
+	for i, test := range tests {
+		m := New(WithRouteTimeout(20 * time.Millisecond))
+		m.HandleFunc(slowMatcher{test.d}, func(http.ResponseWriter, *http.Request) {})
+		res := httptest.NewRecorder()
+		start := time.Now()
+		m.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if d := time.Since(start); d > 500*time.Millisecond {
+			t.Errorf("test %d expected routing to be bounded, took: %v", i, d)
+		}
+	}
+
+	// panics propagate
+	m := New(WithRouteTimeout(time.Second))
+	m.HandleFunc(slowMatcher{-1}, func(http.ResponseWriter, *http.Request) {})
+	defer func() {
+		if v := recover(); v != "slow matcher" {
+			t.Errorf("expected panic, got: %v", v)
+		}
+	}()
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestRouteHeader(t *testing.T) {
+	for _, test := range []struct {
+		opts []MuxOption
+		path string
+		exp  string
+		name string
+	}{
+		{nil, "/users/1", "", ""},
+		{[]MuxOption{RouteHeader}, "/users/1", "/users/:id", "user"},
+		{[]MuxOption{RouteHeader}, "/about", "/about", ""},
+		{[]MuxOption{RouteHeader}, "/missing", "", ""},
+	} {
+		m := New(test.opts...)
+		m.HandleFunc(Get("/users/:id", WithName("user")), func(http.ResponseWriter, *http.Request) {})
+		m.HandleFunc(Get("/about"), func(http.ResponseWriter, *http.Request) {})
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if s := res.Header().Get("X-Route"); s != test.exp {
+			t.Errorf("expected X-Route %q, got: %q", test.exp, s)
+		}
+		if s := res.Header().Get("X-Route-Name"); s != test.name {
+			t.Errorf("expected X-Route-Name %q, got: %q", test.name, s)
+		}
+	}
+}
+
+func TestMuxMethods(t *testing.T) {
+	m := New()
+	h := func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(req.Method + " " + RouteName(req)))
+	}
+	m.Get("/", h, WithName("get"))
+	m.Post("/", h)
+	m.Put("/", h)
+	m.Patch("/", h)
+	m.Delete("/", h)
+	m.Options("/", h)
+	m.Head("/head", h)
+	tests := []struct {
+		method string
+		path   string
+		exp    string
+	}{
+		{"GET", "/", "GET get"},
+		{"POST", "/", "POST "},
+		{"PUT", "/", "PUT "},
+		{"PATCH", "/", "PATCH "},
+		{"DELETE", "/", "DELETE "},
+		{"OPTIONS", "/", "OPTIONS "},
+		{"HEAD", "/head", "HEAD "},
+		{"GET", "/head", "404 page not found\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	h := func(http.ResponseWriter, *http.Request) {}
+	custom := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusTeapot)
+	})
+	tests := []struct {
+		opts   []MuxOption
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{nil, "PUT", "/users", http.StatusNotFound, ""},
+		{[]MuxOption{MethodNotAllowed(nil)}, "PUT", "/users", http.StatusMethodNotAllowed, "GET, HEAD, POST"},
+		{[]MuxOption{MethodNotAllowed(nil)}, "GET", "/users/1", http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{[]MuxOption{MethodNotAllowed(nil)}, "GET", "/users", http.StatusOK, ""},
+		{[]MuxOption{MethodNotAllowed(nil)}, "GET", "/missing", http.StatusNotFound, ""},
+		{[]MuxOption{MethodNotAllowed(nil), WithAutoOptions(nil)}, "PUT", "/users", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST"},
+		{[]MuxOption{MethodNotAllowed(custom), Dynamic}, "PUT", "/users", http.StatusTeapot, "GET, HEAD, POST"},
+	}
+	for i, test := range tests {
+		m := New(test.opts...)
+		m.HandleFunc(Get("/users"), h)
+		m.HandleFunc(Post("/users"), h)
+		m.HandleFunc(Delete("/users/:id"), h)
+		m.HandleFunc(Options("/users/:id"), h)
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if allow := res.Header().Get("Allow"); allow != test.allow {
+			t.Errorf("test %d expected allow %q, got: %q", i, test.allow, allow)
+		}
+	}
+}
+
+func TestAllowedMethods(t *testing.T) {
+	var allowed []string
+	h := func(res http.ResponseWriter, req *http.Request) {
+		allowed = AllowedMethods(req)
+	}
+	sub := NewSubMux()
+	sub.HandleFunc(Put("/x"), h)
+	sub.HandleFunc(Delete("/x"), h)
+	m := New()
+	m.HandleFunc(Get("/users"), h)
+	m.HandleFunc(Post("/users"), h)
+	m.Handle(NewPathSpec("/api/*"), sub)
+	tests := []struct {
+		method string
+		path   string
+		exp    string
+	}{
+		{"GET", "/users", "GET, HEAD, OPTIONS, POST"},
+		{"PUT", "/api/x", "DELETE, OPTIONS, PUT"},
+	}
+	for i, test := range tests {
+		allowed = nil
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.path, nil))
+		if s := strings.Join(allowed, ", "); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	if v := AllowedMethods(httptest.NewRequest("GET", "/users", nil)); v != nil {
+		t.Errorf("expected no methods, got: %v", v)
+	}
+}
+
+func TestHeadFallback(t *testing.T) {
+	h := func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("X-Method", req.Method)
+		res.Header().Set("X-Route", RouteTemplate(req))
+		res.Write([]byte("hello world"))
+	}
+	tests := []struct {
+		opts   []MuxOption
+		method string
+		path   string
+		status int
+		length string
+		body   string
+	}{
+		{nil, "HEAD", "/get", http.StatusNotFound, "", "404 page not found\n"},
+		{[]MuxOption{HeadFallback}, "HEAD", "/get", http.StatusOK, "11", ""},
+		{[]MuxOption{HeadFallback}, "GET", "/get", http.StatusOK, "", "hello world"},
+		{[]MuxOption{HeadFallback}, "HEAD", "/created", http.StatusCreated, "5", ""},
+		{[]MuxOption{HeadFallback}, "HEAD", "/post", http.StatusNotFound, "", "404 page not found\n"},
+	}
+	for i, test := range tests {
+		m := New(test.opts...)
+		m.HandleFunc(NewPathSpec("/get", WithMethod("GET")), h)
+		m.HandleFunc(NewPathSpec("/created", WithMethod("GET")), func(res http.ResponseWriter, req *http.Request) {
+			res.WriteHeader(http.StatusCreated)
+			res.Write([]byte("hello"))
+		})
+		m.HandleFunc(Post("/post"), h)
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if length := res.Header().Get("Content-Length"); length != test.length {
+			t.Errorf("test %d expected length %q, got: %q", i, test.length, length)
+		}
+		if body := res.Body.String(); body != test.body {
+			t.Errorf("test %d expected body %q, got: %q", i, test.body, body)
+		}
+		if test.status == http.StatusOK {
+			if method := res.Header().Get("X-Method"); method != test.method {
+				t.Errorf("test %d expected method %s, got: %s", i, test.method, method)
+			}
+			if route := res.Header().Get("X-Route"); route != "/get" {
+				t.Errorf("test %d expected route /get, got: %s", i, route)
+			}
+		}
+	}
+}
+
+func TestHeadFallbackPanic(t *testing.T) {
+	m := New(HeadFallback)
+	m.HandleFunc(Get("/panic"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("partial"))
+		panic("boom")
+	})
+	res := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic")
+			}
+		}()
+		m.ServeHTTP(res, httptest.NewRequest("HEAD", "/panic", nil))
+	}()
+	if length := res.Header().Get("Content-Length"); length != "" {
+		t.Errorf("expected header not to be written, got length: %q", length)
+	}
+}
+
+func TestMount(t *testing.T) {
+	h := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(req.URL.Path + " " + req.URL.EscapedPath() + " " + RouteTemplate(req)))
+	})
+	sm := http.NewServeMux()
+	sm.Handle("/pprof/", h)
+	m := New()
+	m.Mount("/metrics/", h)
+	m.Mount("/files/", h, WithMethod("GET"))
+	m.Mount("/debug", sm)
+	tests := []struct {
+		method string
+		path   string
+		exp    string
+	}{
+		{"GET", "/metrics", "404 page not found\n"},
+		{"GET", "/metrics/", "/ / /metrics/*"},
+		{"POST", "/metrics/a/b", "/a/b /a/b /metrics/*"},
+		{"GET", "/files/a%2Fb", "/a/b /a%2Fb /files/*"},
+		{"POST", "/files/a", "404 page not found\n"},
+		{"GET", "/debug/pprof/heap", "/pprof/heap /pprof/heap /debug/*"},
+		{"GET", "/metricsx", "404 page not found\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestRemoveReplace(t *testing.T) {
+	for _, dynamic := range []bool{false, true} {
+		var opts []MuxOption
+		if dynamic {
+			opts = append(opts, Dynamic)
+		}
+		var events []string
+		opts = append(opts, WithEvents(func(ev Event) {
+			if ev.Type == RouteAdded || ev.Type == RouteRemoved {
+				events = append(events, ev.Type.String()+" "+matcherString(ev.Matcher))
+			}
+		}))
+		m := New(opts...)
+		h := func(s string) http.Handler {
+			return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Write([]byte(s))
+			})
+		}
+		check := func(path, exp string) {
+			t.Helper()
+			res := httptest.NewRecorder()
+			m.ServeHTTP(res, httptest.NewRequest("GET", path, nil))
+			if s := res.Body.String(); s != exp {
+				t.Errorf("dynamic %t %s expected %q, got: %q", dynamic, path, exp, s)
+			}
+		}
+		users := m.Handle(Get("/users/:id", WithName("user")), h("users"))
+		plugin := m.Handle(NewPathSpec("/users/*"), h("plugin"))
+		m.Handle(Get("/other"), h("other"))
+		check("/users/1", "users")
+		check("/users/1/posts", "plugin")
+		if err := m.Remove(plugin); err != nil {
+			t.Fatalf("dynamic %t expected no error, got: %v", dynamic, err)
+		}
+		if err := m.Remove(plugin); !errors.Is(err, ErrUnknownRoute) {
+			t.Errorf("dynamic %t expected error %v, got: %v", dynamic, ErrUnknownRoute, err)
+		}
+		if err := m.Remove(RouteToken{}); !errors.Is(err, ErrUnknownRoute) {
+			t.Errorf("dynamic %t expected error %v, got: %v", dynamic, ErrUnknownRoute, err)
+		}
+		check("/users/1/posts", "404 page not found\n")
+		check("/other", "other")
+		if err := m.Replace(users, Get("/users/:name", WithName("user_v2")), h("users v2")); err != nil {
+			t.Fatalf("dynamic %t expected no error, got: %v", dynamic, err)
+		}
+		check("/users/1", "users v2")
+		if _, err := m.URL("user"); !errors.Is(err, ErrUnknownRoute) {
+			t.Errorf("dynamic %t expected error %v, got: %v", dynamic, ErrUnknownRoute, err)
+		}
+		if s, err := m.URL("user_v2", "name", "carl"); err != nil || s != "/users/carl" {
+			t.Errorf("dynamic %t expected %q, got: %q, %v", dynamic, "/users/carl", s, err)
+		}
+		if n := len(m.Routes()); n != 2 {
+			t.Errorf("dynamic %t expected 2 routes, got: %d", dynamic, n)
+		}
+		exp := []string{
+			"RouteAdded /users/:id",
+			"RouteAdded /users/*",
+			"RouteAdded /other",
+			"RouteRemoved /users/*",
+			"RouteRemoved /users/:id",
+			"RouteAdded /users/:name",
+		}
+		if !reflect.DeepEqual(events, exp) {
+			t.Errorf("dynamic %t expected %q, got: %q", dynamic, exp, events)
+		}
+	}
+}
+
+func TestRemoveHelpers(t *testing.T) {
+	m := New()
+	h := func(http.ResponseWriter, *http.Request) {}
+	tokens := []RouteToken{
+		m.Get("/get", h),
+		m.Post("/post", h),
+		m.Mount("/mount/", http.HandlerFunc(h)),
+		m.HandleStd("GET /std/{id}", http.HandlerFunc(h)),
+	}
+	for i, token := range tokens {
+		if err := m.Remove(token); err != nil {
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		}
+	}
+	if routes := m.Routes(); len(routes) != 0 {
+		t.Errorf("expected no routes, got: %v", routes)
+	}
+}
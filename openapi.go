@@ -0,0 +1,138 @@
+package goji
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// openAPIMethods are the operation methods of an OpenAPI path item.
+var openAPIMethods = []string{"DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT", "TRACE"}
+
+// openAPIParamRE matches named matches (and their types) in path specs.
+var openAPIParamRE = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)(?::[A-Za-z0-9_]+)?`)
+
+// CheckOpenAPI checks the Mux's routes against the JSON encoded OpenAPI
+// document, returning the problems found: documented operations that are not
+// routed (ProblemUnrouted), and routes that are not documented
+// (ProblemUndocumented).
+//
+// Path spec templates are compared with OpenAPI paths, with named matches
+// equivalent to path parameters (for example, "/users/:id" and
+// "/users/{id}"). Operations with a path under a wildcard route (such as
+// "/api/*") are considered routed, and wildcard routes are not required to be
+// documented. Only PathSpec routes are checked.
+//
+// See gojitest.CheckOpenAPI for use in tests.
+func (m *Mux) CheckOpenAPI(doc []byte) ([]Problem, error) {
+	var v struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	// documented operations
+	documented := make(map[string]map[string]bool)
+	for path, item := range v.Paths {
+		documented[path] = make(map[string]bool)
+		for key := range item {
+			if method := strings.ToUpper(key); containsAny(openAPIMethods, []string{method}) {
+				documented[path][method] = true
+			}
+		}
+	}
+	// routed operations
+	type wildcard struct {
+		prefix  string
+		methods map[string]struct{}
+	}
+	var problems []Problem
+	routed := make(map[string]map[string]struct{})
+	var wildcards []wildcard
+	for _, rt := range m.routes() {
+		p, ok := unwrapMatcher(rt.matcher).(*PathSpec)
+		if !ok {
+			continue
+		}
+		undocumented := false
+		for _, q := range append([]*PathSpec{p}, p.alts...) {
+			if q.alts != nil {
+				continue
+			}
+			if q.wildcard {
+				wildcards = append(wildcards, wildcard{openAPIPath(q.raw[:strings.LastIndex(q.raw, "*")]), q.methods})
+				continue
+			}
+			path := openAPIPath(q.raw)
+			if !documentedAny(documented[path], q.methods) {
+				undocumented = true
+			}
+			if routed[path] == nil {
+				routed[path] = make(map[string]struct{})
+			}
+			if q.methods == nil {
+				for _, method := range openAPIMethods {
+					routed[path][method] = struct{}{}
+				}
+			}
+			for method := range q.methods {
+				routed[path][method] = struct{}{}
+			}
+		}
+		if undocumented {
+			problems = append(problems, Problem{
+				Type:    ProblemUndocumented,
+				Route:   matcherString(p),
+				Message: "route is not documented",
+			})
+		}
+	}
+	// unrouted operations
+	paths := make([]string, 0, len(documented))
+	for path := range documented {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range openAPIMethods {
+			if !documented[path][method] {
+				continue
+			}
+			_, ok := routed[path][method]
+			for _, w := range wildcards {
+				if _, has := w.methods[method]; strings.HasPrefix(path, w.prefix) && (w.methods == nil || has) {
+					ok = true
+				}
+			}
+			if !ok {
+				problems = append(problems, Problem{
+					Type:    ProblemUnrouted,
+					Route:   path,
+					Message: fmt.Sprintf("operation %s %s is not routed", method, path),
+				})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// openAPIPath returns the OpenAPI path for the path spec template.
+func openAPIPath(raw string) string {
+	return openAPIParamRE.ReplaceAllString(raw, "{$1}")
+}
+
+// documentedAny returns whether or not any of the methods are documented,
+// where nil methods are all methods.
+func documentedAny(documented map[string]bool, methods map[string]struct{}) bool {
+	if methods == nil {
+		return len(documented) != 0
+	}
+	for method := range methods {
+		if documented[method] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package goji
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCheckOpenAPI(t *testing.T) {
+	doc := []byte(`{
+		"openapi": "3.1.0",
+		"paths": {
+			"/users": {"get": {}, "post": {}},
+			"/users/{id}": {"parameters": [], "get": {}, "delete": {}},
+			"/orgs/{org}/repos": {"get": {}},
+			"/health": {"get": {}}
+		}
+	}`)
+	m := New()
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(Get("/users"), h)
+	m.HandleFunc(Post("/users"), h)
+	m.HandleFunc(Get("/users/:id:int"), h)
+	m.HandleFunc(Put("/users/:id"), h)
+	m.HandleFunc(Get("/orgs/:org/*"), h)
+	m.HandleFunc(Get("/admin"), h)
+	m.HandleFunc(NewPathSpec("/static/*"), h)
+	problems, err := m.CheckOpenAPI(doc)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := []Problem{
+		{ProblemUndocumented, "/users/:id", "route is not documented"},
+		{ProblemUndocumented, "/admin", "route is not documented"},
+		{ProblemUnrouted, "/health", "operation GET /health is not routed"},
+		{ProblemUnrouted, "/users/{id}", "operation DELETE /users/{id} is not routed"},
+	}
+	if !reflect.DeepEqual(problems, exp) {
+		t.Errorf("expected %v, got: %v", exp, problems)
+	}
+	if _, err := m.CheckOpenAPI([]byte("{")); err == nil {
+		t.Errorf("expected error")
+	}
+}
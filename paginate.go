@@ -0,0 +1,55 @@
+package goji
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Paginate sets the RFC 8288 Link header, with "first", "prev", "next", and
+// "last" relations, and the X-Total-Count header on the response for a page
+// (starting at 1) of a paginated collection of total items, standardizing
+// pagination metadata across handlers.
+//
+// Links are built for the named route (see AbsoluteURL) with the request's
+// route params and query, with the "page" and "per_page" query parameters
+// set. For example:
+//
+//	mux.HandleFunc(goji.Get("/users", goji.WithName("users")), func(res http.ResponseWriter, req *http.Request) {
+//		users, total := list(page, perPage)
+//		if err := goji.Paginate(res, req, "users", page, perPage, total); err != nil {
+//			// ...
+//		}
+//		// ...
+//	})
+func Paginate(res http.ResponseWriter, req *http.Request, name string, page, perPage, total int) error {
+	var params []string
+	for k, v := range Params(req) {
+		params = append(params, k, v)
+	}
+	base, err := AbsoluteURL(req, name, params...)
+	if err != nil {
+		return err
+	}
+	last := 1
+	if perPage > 0 && total > 0 {
+		last = (total + perPage - 1) / perPage
+	}
+	link := func(page int, rel string) string {
+		q := req.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return "<" + base + "?" + q.Encode() + `>; rel="` + rel + `"`
+	}
+	links := []string{link(1, "first")}
+	if page > 1 {
+		links = append(links, link(min(page-1, last), "prev"))
+	}
+	if page < last {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+	res.Header().Set("Link", strings.Join(links, ", "))
+	res.Header().Set("X-Total-Count", strconv.Itoa(total))
+	return nil
+}
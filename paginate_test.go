@@ -0,0 +1,34 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	m := New()
+	m.HandleFunc(Get("/orgs/:org/users", WithName("users")), func(res http.ResponseWriter, req *http.Request) {
+		if err := Paginate(res, req, "users", 2, 10, 35); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+	m.HandleFunc(Get("/empty"), func(res http.ResponseWriter, req *http.Request) {
+		if err := Paginate(res, req, "unknown", 1, 10, 0); err != ErrUnknownRoute {
+			t.Errorf("expected ErrUnknownRoute, got: %v", err)
+		}
+	})
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "http://example.com/orgs/goji/users?q=carl&page=2", nil))
+	exp := `<http://example.com/orgs/goji/users?page=1&per_page=10&q=carl>; rel="first", ` +
+		`<http://example.com/orgs/goji/users?page=1&per_page=10&q=carl>; rel="prev", ` +
+		`<http://example.com/orgs/goji/users?page=3&per_page=10&q=carl>; rel="next", ` +
+		`<http://example.com/orgs/goji/users?page=4&per_page=10&q=carl>; rel="last"`
+	if s := res.Header().Get("Link"); s != exp {
+		t.Errorf("expected %q, got: %q", exp, s)
+	}
+	if s := res.Header().Get("X-Total-Count"); s != "35" {
+		t.Errorf("expected %q, got: %q", "35", s)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/empty", nil))
+}
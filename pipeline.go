@@ -0,0 +1,291 @@
+package goji
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// Validator is the interface for request validators, allowing use of
+// validation packages such as go-playground/validator:
+//
+//	v := validator.New()
+//	goji.WithPipelineValidator(goji.ValidatorFunc(v.Struct))
+type Validator interface {
+	Validate(interface{}) error
+}
+
+// ValidatorFunc is a func that satisfies the Validator interface.
+type ValidatorFunc func(interface{}) error
+
+// Validate satisfies the Validator interface.
+func (f ValidatorFunc) Validate(v interface{}) error {
+	return f(v)
+}
+
+// StatusError is an error with a HTTP response status.
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+// Error satisfies the error interface.
+func (err *StatusError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *StatusError) Unwrap() error {
+	return err.Err
+}
+
+// StatusCode returns the error's HTTP response status.
+func (err *StatusError) StatusCode() int {
+	return err.Status
+}
+
+// ErrorStatus returns the HTTP response status for the error, using the
+// StatusCode method of the first error in the error's chain that has one,
+// and 500 Internal Server Error otherwise.
+func ErrorStatus(err error) int {
+	var se interface{ StatusCode() int }
+	if errors.As(err, &se) {
+		return se.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
+
+// Pipeline returns a handler that binds the request to a Req, validates it,
+// invokes fn, and writes the returned Resp, combining request binding,
+// validation, error handling, and response rendering into a single handler:
+//
+//	type GetUser struct {
+//		Name string `param:"name"`
+//	}
+//
+//	mux.Handle(goji.Get("/user/:name"), goji.Pipeline(func(ctx context.Context, req GetUser) (*User, error) {
+//		return db.User(ctx, req.Name)
+//	}))
+//
+// The request body (when present, and limited to the maximum body size, see
+// WithPipelineMaxBody) is decoded as JSON, unless a binder is set (see
+// WithPipelineBinder), after which bound route params are set to the Req's
+// exported fields with a matching "param" tag. Req may be a struct or a
+// pointer to a struct. Binding errors are reported with a 400 Bad Request
+// status (or 413 Request Entity Too Large when the body is too large), and
+// validation errors with a 422 Unprocessable Entity status.
+//
+// Errors are passed to the error handler (see WithPipelineErrorHandler),
+// which by default writes the error's status (see ErrorStatus) and text,
+// hiding the text of 500 Internal Server Error errors. The Resp is written as
+// JSON, unless a responder is set (see WithPipelineResponder).
+func Pipeline[Req, Resp any](fn func(context.Context, Req) (Resp, error), opts ...PipelineOption) http.Handler {
+	p := &pipeline{
+		status:       http.StatusOK,
+		maxBody:      10 << 20,
+		bind:         bindJSON,
+		respond:      respondJSON,
+		errorHandler: handleError,
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var v Req
+		if rv := reflect.ValueOf(&v).Elem(); rv.Kind() == reflect.Pointer {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		if req.Body != nil && p.maxBody > 0 {
+			req.Body = http.MaxBytesReader(res, req.Body, p.maxBody)
+		}
+		if err := p.bindRequest(req, &v); err != nil {
+			status, e := http.StatusBadRequest, new(http.MaxBytesError)
+			if errors.As(err, &e) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			p.errorHandler(res, req, &StatusError{Status: status, Err: err})
+			return
+		}
+		if p.validator != nil {
+			if err := p.validator.Validate(v); err != nil {
+				p.errorHandler(res, req, &StatusError{Status: http.StatusUnprocessableEntity, Err: err})
+				return
+			}
+		}
+		resp, err := fn(req.Context(), v)
+		if err != nil {
+			p.errorHandler(res, req, err)
+			return
+		}
+		if err := p.respond(res, req, p.status, resp); err != nil {
+			p.errorHandler(res, req, err)
+		}
+	})
+}
+
+// pipeline is a pipeline's configuration.
+type pipeline struct {
+	status       int
+	maxBody      int64
+	validator    Validator
+	bind         func(*http.Request, interface{}) error
+	respond      func(http.ResponseWriter, *http.Request, int, interface{}) error
+	errorHandler func(http.ResponseWriter, *http.Request, error)
+}
+
+// bindRequest binds the request body and params to v.
+func (p *pipeline) bindRequest(req *http.Request, v interface{}) error {
+	if req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0 {
+		if err := p.bind(req, v); err != nil {
+			return err
+		}
+	}
+	return bindParams(req, v)
+}
+
+// bindJSON decodes the request body as JSON.
+func bindJSON(req *http.Request, v interface{}) error {
+	return json.NewDecoder(req.Body).Decode(v)
+}
+
+// bindParams sets the bound route params to the exported fields of the
+// struct (or pointer to a struct) pointed to by v with a matching "param"
+// tag.
+func bindParams(req *http.Request, v interface{}) error {
+	params := Params(req)
+	rv := reflect.ValueOf(v).Elem()
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if len(params) == 0 || rv.Kind() != reflect.Struct {
+		return nil
+	}
+	typ := rv.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := field.Tag.Get("param")
+		s, ok := params[name]
+		if name == "" || !ok || !field.IsExported() {
+			continue
+		}
+		if err := setField(rv.Field(i), s); err != nil {
+			return fmt.Errorf("param %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setField sets the field to the parsed string value, allocating pointer
+// fields.
+func setField(f reflect.Value, s string) error {
+	switch f.Kind() {
+	case reflect.Pointer:
+		v := reflect.New(f.Type().Elem())
+		if err := setField(v.Elem(), s); err != nil {
+			return err
+		}
+		f.Set(v)
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
+
+// respondJSON writes v as JSON.
+func respondJSON(res http.ResponseWriter, _ *http.Request, status int, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	res.Header().Set("Content-Type", "application/json; charset=utf-8")
+	res.WriteHeader(status)
+	if status != http.StatusNoContent {
+		_, err = res.Write(append(buf, '\n'))
+	}
+	return err
+}
+
+// handleError writes the error's status and text.
+func handleError(res http.ResponseWriter, _ *http.Request, err error) {
+	status := ErrorStatus(err)
+	msg := err.Error()
+	if status == http.StatusInternalServerError {
+		msg = http.StatusText(status)
+	}
+	http.Error(res, msg, status)
+}
+
+// PipelineOption is a pipeline option.
+type PipelineOption func(*pipeline)
+
+// WithPipelineValidator is a pipeline option to set the validator for bound
+// requests.
+func WithPipelineValidator(v Validator) PipelineOption {
+	return func(p *pipeline) {
+		p.validator = v
+	}
+}
+
+// WithPipelineBinder is a pipeline option to set the func used to decode
+// request bodies (for example, render.Renderer.Bind).
+func WithPipelineBinder(bind func(*http.Request, interface{}) error) PipelineOption {
+	return func(p *pipeline) {
+		p.bind = bind
+	}
+}
+
+// WithPipelineResponder is a pipeline option to set the func used to write
+// responses (for example, render.Renderer.Render, for content-negotiated
+// responses).
+func WithPipelineResponder(respond func(http.ResponseWriter, *http.Request, int, interface{}) error) PipelineOption {
+	return func(p *pipeline) {
+		p.respond = respond
+	}
+}
+
+// WithPipelineErrorHandler is a pipeline option to set the error handler.
+func WithPipelineErrorHandler(f func(http.ResponseWriter, *http.Request, error)) PipelineOption {
+	return func(p *pipeline) {
+		p.errorHandler = f
+	}
+}
+
+// WithPipelineStatus is a pipeline option to set the response status
+// (default 200 OK).
+func WithPipelineStatus(status int) PipelineOption {
+	return func(p *pipeline) {
+		p.status = status
+	}
+}
+
+// WithPipelineMaxBody is a pipeline option to set the maximum size of request
+// bodies (default 10 MiB). A size of 0 disables the limit.
+func WithPipelineMaxBody(maxBody int64) PipelineOption {
+	return func(p *pipeline) {
+		p.maxBody = maxBody
+	}
+}
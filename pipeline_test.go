@@ -0,0 +1,138 @@
+package goji
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type pipelineReq struct {
+	Name  string `param:"name"`
+	ID    int    `param:"id"`
+	Email string `json:"email"`
+}
+
+type pipelineResp struct {
+	Name  string `json:"name"`
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
+
+func TestPipeline(t *testing.T) {
+	errNotFound := &StatusError{Status: http.StatusNotFound, Err: errors.New("no such user")}
+	h := Pipeline(func(ctx context.Context, req pipelineReq) (pipelineResp, error) {
+		switch req.Name {
+		case "missing":
+			return pipelineResp{}, fmt.Errorf("lookup: %w", errNotFound)
+		case "broken":
+			return pipelineResp{}, errors.New("database password is hunter2")
+		}
+		return pipelineResp{req.Name, req.ID, req.Email}, nil
+	}, WithPipelineValidator(ValidatorFunc(func(v interface{}) error {
+		if req := v.(pipelineReq); strings.HasPrefix(req.Name, "_") {
+			return errors.New("invalid name")
+		}
+		return nil
+	})), WithPipelineStatus(http.StatusCreated))
+	m := New()
+	m.Handle(Post("/user/:name/:id"), h)
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		exp    string
+	}{
+		{"/user/carl/1", "", http.StatusCreated, `{"name":"carl","id":1,"email":""}` + "\n"},
+		{"/user/carl/2", `{"email":"c@example.com","name":"other"}`, http.StatusCreated, `{"name":"carl","id":2,"email":"c@example.com"}` + "\n"},
+		{"/user/carl/x", "", http.StatusBadRequest, "param \"id\": strconv.ParseInt: parsing \"x\": invalid syntax\n"},
+		{"/user/carl/1", `{`, http.StatusBadRequest, "unexpected EOF\n"},
+		{"/user/_carl/1", "", http.StatusUnprocessableEntity, "invalid name\n"},
+		{"/user/missing/1", "", http.StatusNotFound, "lookup: no such user\n"},
+		{"/user/broken/1", "", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("POST", test.path, strings.NewReader(test.body)))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestPipelineOptions(t *testing.T) {
+	var bound, responded bool
+	h := Pipeline(func(ctx context.Context, req string) (string, error) {
+		if req == "fail" {
+			return "", errors.New("fail")
+		}
+		return req + "!", nil
+	}, WithPipelineBinder(func(req *http.Request, v interface{}) error {
+		bound = true
+		*v.(*string) = req.Header.Get("X-Value")
+		return nil
+	}), WithPipelineResponder(func(res http.ResponseWriter, req *http.Request, status int, v interface{}) error {
+		responded = true
+		res.Write([]byte(v.(string)))
+		return nil
+	}), WithPipelineErrorHandler(func(res http.ResponseWriter, req *http.Request, err error) {
+		http.Error(res, "custom "+err.Error(), http.StatusTeapot)
+	}))
+	for i, test := range []struct {
+		value  string
+		status int
+		exp    string
+	}{
+		{"a", http.StatusOK, "a!"},
+		{"fail", http.StatusTeapot, "custom fail\n"},
+	} {
+		req := httptest.NewRequest("PUT", "/", strings.NewReader("body"))
+		req.Header.Set("X-Value", test.value)
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		if res.Code != test.status || res.Body.String() != test.exp {
+			t.Errorf("test %d expected %d %q, got: %d %q", i, test.status, test.exp, res.Code, res.Body.String())
+		}
+	}
+	if !bound || !responded {
+		t.Errorf("expected binder and responder to be used")
+	}
+}
+
+func TestPipelinePointer(t *testing.T) {
+	type request struct {
+		name  string `param:"name"`
+		ID    *int   `param:"id"`
+		Email string `json:"email"`
+	}
+	h := Pipeline(func(ctx context.Context, req *request) (string, error) {
+		return fmt.Sprintf("%q %d %s", req.name, *req.ID, req.Email), nil
+	}, WithPipelineMaxBody(32))
+	m := New()
+	m.Handle(Post("/user/:name/:id"), h)
+	tests := []struct {
+		body   string
+		status int
+		exp    string
+	}{
+		{"", http.StatusOK, `"\"\" 1 "` + "\n"},
+		{`{"email":"c@example.com"}`, http.StatusOK, `"\"\" 1 c@example.com"` + "\n"},
+		{`{"email":"` + strings.Repeat("c", 32) + `"}`, http.StatusRequestEntityTooLarge, "http: request body too large\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("POST", "/user/carl/1", strings.NewReader(test.body)))
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
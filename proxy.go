@@ -0,0 +1,97 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// Proxy returns a reverse proxy handler forwarding requests to the target
+// URL, with the request path set to the target's path joined with the
+// unmatched path suffix (see Path). The X-Forwarded-For, X-Forwarded-Host,
+// and X-Forwarded-Proto headers are set on the outgoing request. The suffix
+// is forwarded with its original escaping, and requests with an invalid
+// suffix are rejected with 400 Bad Request.
+//
+// For example, to proxy requests for "/api/users" to
+// "http://backend:8080/users":
+//
+//	mux.Handle(goji.NewPathSpec("/api/*"), goji.Proxy(backend, goji.WithProxyRouteHeader("X-Route")))
+func Proxy(target *url.URL, opts ...ProxyOption) http.Handler {
+	p := &proxy{}
+	for _, o := range opts {
+		o(p)
+	}
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if suffix := Path(pr.In.Context()); suffix != "" {
+				// the suffix was validated by the handler
+				path, _ := url.PathUnescape(suffix)
+				pr.Out.URL.Path, pr.Out.URL.RawPath = path, suffix
+			}
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			if p.routeHeader != "" {
+				TagRoute(pr.Out.Header, p.routeHeader, pr.In)
+			}
+		},
+		Transport: p.transport,
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, err := url.PathUnescape(Path(req.Context())); err != nil {
+			http.Error(res, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		rp.ServeHTTP(res, req)
+	})
+}
+
+// TagRoute sets the header name to the name of the matched route for the
+// request (see RouteName), falling back to the route template (see
+// RouteTemplate) when the route is not named. The header is removed when no
+// route matched.
+//
+// TagRoute is intended for use when forwarding requests, so that upstream
+// services and tracing systems can attribute traffic by logical route. For
+// example, with a custom httputil.ReverseProxy:
+//
+//	Rewrite: func(pr *httputil.ProxyRequest) {
+//		pr.SetURL(target)
+//		goji.TagRoute(pr.Out.Header, "X-Route", pr.In)
+//	}
+func TagRoute(header http.Header, name string, req *http.Request) {
+	route := RouteName(req)
+	if route == "" {
+		route = RouteTemplate(req)
+	}
+	if route == "" {
+		header.Del(name)
+		return
+	}
+	header.Set(name, route)
+}
+
+// proxy holds the options for a reverse proxy.
+type proxy struct {
+	routeHeader string
+	transport   http.RoundTripper
+}
+
+// ProxyOption is a reverse proxy option.
+type ProxyOption func(*proxy)
+
+// WithProxyRouteHeader is a reverse proxy option to set the header name on
+// outgoing requests to the matched route (see TagRoute).
+func WithProxyRouteHeader(name string) ProxyOption {
+	return func(p *proxy) {
+		p.routeHeader = name
+	}
+}
+
+// WithProxyTransport is a reverse proxy option to set the transport used to
+// forward requests (default http.DefaultTransport).
+func WithProxyTransport(transport http.RoundTripper) ProxyOption {
+	return func(p *proxy) {
+		p.transport = transport
+	}
+}
@@ -0,0 +1,65 @@
+package goji
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestProxy(t *testing.T) {
+	var path, route string
+	upstream := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		path, route = req.URL.EscapedPath(), req.Header.Get("X-Route")
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/v1")
+	m := New()
+	m.Handle(NewPathSpec("/api/*", WithName("api")), Proxy(target, WithProxyRouteHeader("X-Route")))
+	m.Handle(NewPathSpec("/raw/*"), Proxy(target, WithProxyRouteHeader("X-Route")))
+	m.Handle(NewPathSpec("/plain/*"), Proxy(target))
+	tests := []struct {
+		path  string
+		exp   string
+		route string
+	}{
+		{"/api/users/7", "/v1/users/7", "api"},
+		{"/raw/a", "/v1/a", "/raw/*"},
+		{"/plain/b", "/v1/b", ""},
+		{"/plain/a%20b", "/v1/a%20b", ""},
+		{"/plain/a%2Fb", "/v1/a%2Fb", ""},
+	}
+	for i, test := range tests {
+		path, route = "", ""
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("X-Route", "spoofed")
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Errorf("test %d expected %d, got: %d", i, http.StatusOK, res.Code)
+		}
+		if path != test.exp {
+			t.Errorf("test %d expected path %q, got: %q", i, test.exp, path)
+		}
+		if test.route != "" && route != test.route {
+			t.Errorf("test %d expected route %q, got: %q", i, test.route, route)
+		}
+	}
+
+	// invalid suffix
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	Proxy(target).ServeHTTP(res, req.WithContext(context.WithValue(req.Context(), pathKey, "/%zz")))
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got: %d", http.StatusBadRequest, res.Code)
+	}
+}
+
+func TestTagRoute(t *testing.T) {
+	header := http.Header{"X-Route": {"spoofed"}}
+	TagRoute(header, "X-Route", httptest.NewRequest("GET", "/", nil))
+	if v := header.Get("X-Route"); v != "" {
+		t.Errorf("expected no header, got: %q", v)
+	}
+}
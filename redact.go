@@ -0,0 +1,69 @@
+package goji
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Redacted is the value substituted for redacted params.
+const Redacted = "[REDACTED]"
+
+// redactions are the registered sensitive param names and patterns.
+var redactions struct {
+	sync.RWMutex
+	names []string
+}
+
+// RedactParams registers sensitive param names (such as "token" or
+// "password"), whose values are redacted from logs, audit records, and error
+// reports (see RedactedParams and RedactedPath). Names are matched case
+// insensitively, and may be patterns (see path.Match), such as "*_token".
+//
+// RedactParams is intended to be called during initialization.
+func RedactParams(names ...string) {
+	redactions.Lock()
+	defer redactions.Unlock()
+	for _, name := range names {
+		redactions.names = append(redactions.names, strings.ToLower(name))
+	}
+}
+
+// IsRedacted returns whether or not the param name is registered as
+// sensitive (see RedactParams).
+func IsRedacted(name string) bool {
+	name = strings.ToLower(name)
+	redactions.RLock()
+	defer redactions.RUnlock()
+	for _, pattern := range redactions.names {
+		if ok, _ := path.Match(pattern, name); ok || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactedParams returns the bound route params for the request (see
+// Params), with the values of sensitive params replaced by Redacted.
+func RedactedParams(req *http.Request) map[string]string {
+	params := Params(req)
+	for name := range params {
+		if IsRedacted(name) {
+			params[name] = Redacted
+		}
+	}
+	return params
+}
+
+// RedactedPath returns the request path for use in logs, which is the
+// matched route template (see RouteTemplate) when any sensitive param is
+// bound, as the path contains the param's value.
+func RedactedPath(req *http.Request) string {
+	for name := range Params(req) {
+		if IsRedacted(name) {
+			return RouteTemplate(req)
+		}
+	}
+	return req.URL.Path
+}
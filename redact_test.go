@@ -0,0 +1,46 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedactParams(t *testing.T) {
+	RedactParams("Token", "*_key")
+	tests := []struct {
+		name string
+		exp  bool
+	}{
+		{"token", true},
+		{"TOKEN", true},
+		{"api_key", true},
+		{"key", false},
+		{"name", false},
+	}
+	for i, test := range tests {
+		if b := IsRedacted(test.name); b != test.exp {
+			t.Errorf("test %d expected %q redacted %t, got: %t", i, test.name, test.exp, b)
+		}
+	}
+	m := New()
+	var params map[string]string
+	var path string
+	m.HandleFunc(Get("/:name/:token"), func(res http.ResponseWriter, req *http.Request) {
+		params, path = RedactedParams(req), RedactedPath(req)
+	})
+	m.HandleFunc(Get("/:name"), func(res http.ResponseWriter, req *http.Request) {
+		params, path = RedactedParams(req), RedactedPath(req)
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/carl/secret", nil))
+	if params["name"] != "carl" || params["token"] != Redacted {
+		t.Errorf("expected token redacted, got: %v", params)
+	}
+	if path != "/:name/:token" {
+		t.Errorf("expected %q, got: %q", "/:name/:token", path)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/carl", nil))
+	if path != "/carl" {
+		t.Errorf("expected %q, got: %q", "/carl", path)
+	}
+}
@@ -0,0 +1,131 @@
+package goji
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RemoteAddrSpec provides a Matcher that matches requests based on the
+// request's remote (peer) IP address.
+//
+// RemoteAddrSpec is intended for use with And, allowing internal-only routes
+// to be registered on the same Mux as public routes:
+//
+//	mux.HandleFunc(goji.And(goji.Get("/internal/*"), goji.RemoteAddr("10.0.0.0/8")), internal)
+type RemoteAddrSpec struct {
+	prefixes []netip.Prefix
+	trusted  []netip.Prefix
+}
+
+// RemoteAddr returns a RemoteAddrSpec that matches requests with a remote IP
+// address in any of the CIDRs (for example, "10.0.0.0/8"). A CIDR may also be
+// a single IP address. An invalid CIDR is considered a programmer error and
+// will trigger a panic.
+func RemoteAddr(cidrs ...string) *RemoteAddrSpec {
+	return &RemoteAddrSpec{
+		prefixes: parsePrefixes(cidrs),
+	}
+}
+
+// TrustForwarded sets the CIDRs of the trusted reverse proxies, returning the
+// spec. When the remote IP address is a trusted proxy, the client IP address
+// is determined from the X-Forwarded-For header, as the right-most address
+// that is not a trusted proxy.
+func (s *RemoteAddrSpec) TrustForwarded(proxies ...string) *RemoteAddrSpec {
+	s.trusted = append(s.trusted, parsePrefixes(proxies)...)
+	return s
+}
+
+// Match satisfies the Matcher interface.
+func (s *RemoteAddrSpec) Match(req *http.Request) *http.Request {
+	ip, ok := s.clientIP(req)
+	if !ok {
+		return nil
+	}
+	if containsAddr(s.prefixes, ip) {
+		return req
+	}
+	return nil
+}
+
+// Methods satisfies the Matcher interface.
+func (s *RemoteAddrSpec) Methods() map[string]struct{} {
+	return nil
+}
+
+// Prefix satisfies the Matcher interface.
+func (s *RemoteAddrSpec) Prefix() string {
+	return ""
+}
+
+// String satisfies the fmt.Stringer interface.
+func (s *RemoteAddrSpec) String() string {
+	v := make([]string, len(s.prefixes))
+	for i, prefix := range s.prefixes {
+		v[i] = prefix.String()
+	}
+	return "RemoteAddr(" + strings.Join(v, ", ") + ")"
+}
+
+// clientIP returns the client IP address of the request.
+func (s *RemoteAddrSpec) clientIP(req *http.Request) (netip.Addr, bool) {
+	host := req.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	ip = ip.Unmap()
+	values := req.Header.Values("X-Forwarded-For")
+	if len(values) == 0 || !containsAddr(s.trusted, ip) {
+		return ip, true
+	}
+	forwarded := strings.Split(strings.Join(values, ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		if ip = addr.Unmap(); !containsAddr(s.trusted, ip) {
+			break
+		}
+	}
+	return ip, true
+}
+
+// parsePrefixes parses the CIDRs or IP addresses.
+func parsePrefixes(cidrs []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				panic(fmt.Sprintf("goji: invalid remote address %q", cidr))
+			}
+			prefixes[i] = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("goji: invalid remote address %q", cidr))
+		}
+		prefixes[i] = prefix.Masked()
+	}
+	return prefixes
+}
+
+// containsAddr returns whether or not any of the prefixes contains the
+// address.
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
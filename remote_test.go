@@ -0,0 +1,69 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRemoteAddr(t *testing.T) {
+	tests := []struct {
+		m      *RemoteAddrSpec
+		remote string
+		xff    string
+		exp    bool
+	}{
+		{RemoteAddr("10.0.0.0/8"), "10.1.2.3:1234", "", true},
+		{RemoteAddr("10.0.0.0/8"), "11.1.2.3:1234", "", false},
+		{RemoteAddr("10.0.0.0/8", "192.168.0.0/16"), "192.168.1.1:80", "", true},
+		{RemoteAddr("127.0.0.1"), "127.0.0.1:80", "", true},
+		{RemoteAddr("127.0.0.1"), "[::ffff:127.0.0.1]:80", "", true},
+		{RemoteAddr("::1"), "[::1]:80", "", true},
+		{RemoteAddr("10.0.0.0/8"), "bogus", "", false},
+		{RemoteAddr("10.0.0.0/8"), "192.0.2.1:80", "10.1.1.1", false},
+		{RemoteAddr("10.0.0.0/8").TrustForwarded("192.0.2.0/24"), "192.0.2.1:80", "10.1.1.1", true},
+		{RemoteAddr("10.0.0.0/8").TrustForwarded("192.0.2.0/24"), "192.0.2.1:80", "10.1.1.1, 8.8.8.8", false},
+		{RemoteAddr("10.0.0.0/8").TrustForwarded("192.0.2.0/24"), "192.0.2.1:80", "8.8.8.8, 10.1.1.1, 192.0.2.7", true},
+		{RemoteAddr("10.0.0.0/8").TrustForwarded("192.0.2.0/24"), "192.0.2.1:80", "garbage", false},
+		{RemoteAddr("192.0.2.0/24").TrustForwarded("192.0.2.0/24"), "192.0.2.1:80", "", true},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = test.remote
+		if test.xff != "" {
+			req.Header.Set("X-Forwarded-For", test.xff)
+		}
+		if ok := test.m.Match(req) != nil; ok != test.exp {
+			t.Errorf("test %d expected %t, got: %t", i, test.exp, ok)
+		}
+	}
+}
+
+func TestRemoteAddrMux(t *testing.T) {
+	m := New()
+	m.HandleFunc(And(Get("/internal"), RemoteAddr("10.0.0.0/8")), func(http.ResponseWriter, *http.Request) {})
+	for i, test := range []struct {
+		remote string
+		status int
+	}{
+		{"10.0.0.1:1234", http.StatusOK},
+		{"203.0.113.1:1234", http.StatusNotFound},
+	} {
+		req := httptest.NewRequest("GET", "/internal", nil)
+		req.RemoteAddr = test.remote
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+}
+
+func TestRemoteAddrInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	RemoteAddr("10.0.0.0/99")
+}
@@ -0,0 +1,221 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Codec is the interface for response and request body codecs, selected by
+// the request's Accept and Content-Type headers (see Renderer.Render and
+// Renderer.Bind).
+type Codec interface {
+	// ContentType returns the media type handled by the codec.
+	ContentType() string
+	// Marshal marshals v.
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal unmarshals the data into v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSON is the JSON codec.
+var JSON Codec = jsonCodec{}
+
+// GogoProto is the protobuf codec for values that implement the Marshal and
+// Unmarshal methods generated by gogo/protobuf (and similar generators). It
+// does not handle messages generated for the google.golang.org/protobuf
+// package, which must be registered with a custom codec (see WithCodec) using
+// proto.Marshal and proto.Unmarshal.
+var GogoProto Codec = gogoProtoCodec{}
+
+// Msgp is the MessagePack codec for values that implement the MarshalMsg and
+// UnmarshalMsg methods generated by tinylib/msgp.
+var Msgp Codec = msgpCodec{}
+
+// jsonCodec is the JSON codec.
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// gogoProtoCodec is the gogo/protobuf codec.
+type gogoProtoCodec struct{}
+
+func (gogoProtoCodec) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (gogoProtoCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(interface{ Marshal() ([]byte, error) })
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not a protobuf message", ErrUnsupportedType, v)
+	}
+	return m.Marshal()
+}
+
+func (gogoProtoCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(interface{ Unmarshal([]byte) error })
+	if !ok {
+		return fmt.Errorf("%w: %T is not a protobuf message", ErrUnsupportedType, v)
+	}
+	return m.Unmarshal(data)
+}
+
+// msgpCodec is the tinylib/msgp codec.
+type msgpCodec struct{}
+
+func (msgpCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(interface {
+		MarshalMsg([]byte) ([]byte, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not a msgpack message", ErrUnsupportedType, v)
+	}
+	return m.MarshalMsg(nil)
+}
+
+func (msgpCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(interface {
+		UnmarshalMsg([]byte) ([]byte, error)
+	})
+	if !ok {
+		return fmt.Errorf("%w: %T is not a msgpack message", ErrUnsupportedType, v)
+	}
+	_, err := m.UnmarshalMsg(data)
+	return err
+}
+
+// Render renders v using the codec negotiated from the request's Accept
+// header, writing it to the response with the status. JSON is used when the
+// request accepts any type, and is rendered with JSON (including JSONP
+// support). When no codec is acceptable, a 406 Not Acceptable is written and
+// ErrNotAcceptable is returned.
+func (r *Renderer) Render(res http.ResponseWriter, req *http.Request, status int, v interface{}) error {
+	c := r.negotiate(req)
+	switch {
+	case c == nil:
+		http.Error(res, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+		return ErrNotAcceptable
+	case c == JSON:
+		return r.JSON(res, req, status, v)
+	}
+	buf, err := c.Marshal(v)
+	if err != nil {
+		http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	res.Header().Set("Content-Type", c.ContentType())
+	res.Header().Add("Vary", "Accept")
+	res.WriteHeader(status)
+	_, err = res.Write(buf)
+	return err
+}
+
+// negotiate returns the codec for the request's Accept header, or nil when
+// no codec is acceptable.
+func (r *Renderer) negotiate(req *http.Request) Codec {
+	type accept struct {
+		typ string
+		q   float64
+	}
+	var accepts []accept
+	for _, v := range req.Header.Values("Accept") {
+		for _, s := range strings.Split(v, ",") {
+			typ, params, _ := strings.Cut(s, ";")
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				var err error
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if typ = strings.ToLower(strings.TrimSpace(typ)); typ != "" && q > 0 {
+				accepts = append(accepts, accept{typ, q})
+			}
+		}
+	}
+	if len(accepts) == 0 {
+		return r.codec("application/json")
+	}
+	sort.SliceStable(accepts, func(i, j int) bool {
+		return accepts[i].q > accepts[j].q
+	})
+	for _, a := range accepts {
+		if a.typ == "*/*" || a.typ == "application/*" {
+			return r.codec("application/json")
+		}
+		if c := r.codec(a.typ); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// Bind decodes the request body into v using the codec for the request's
+// Content-Type header (JSON when not set). URL-encoded and multipart forms
+// are decoded with BindForm. When no codec handles the Content-Type,
+// ErrUnsupportedMediaType is returned, and callers should respond with 415
+// Unsupported Media Type. When the body exceeds the maximum body size (see
+// WithMaxBody), a *http.MaxBytesError is returned, and callers should
+// respond with 413 Request Entity Too Large.
+func (r *Renderer) Bind(req *http.Request, v interface{}) error {
+	typ := "application/json"
+	if s := req.Header.Get("Content-Type"); s != "" {
+		var err error
+		if typ, _, err = mime.ParseMediaType(s); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+		}
+	}
+	if typ == "application/x-www-form-urlencoded" || typ == "multipart/form-data" {
+		return BindForm(req, v)
+	}
+	c := r.codec(typ)
+	if c == nil {
+		return fmt.Errorf("%w %q", ErrUnsupportedMediaType, typ)
+	}
+	buf, err := io.ReadAll(http.MaxBytesReader(nil, req.Body, r.maxBody))
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(buf, v)
+}
+
+// codec returns the registered codec for the media type.
+func (r *Renderer) codec(typ string) Codec {
+	for _, c := range r.codecs {
+		if c.ContentType() == typ {
+			return c
+		}
+	}
+	return nil
+}
+
+// Codec errors.
+var (
+	// ErrNotAcceptable is the not acceptable error.
+	ErrNotAcceptable = errors.New("not acceptable")
+	// ErrUnsupportedMediaType is the unsupported media type error.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrUnsupportedType is the unsupported type error, returned when a value
+	// cannot be handled by a codec.
+	ErrUnsupportedType = errors.New("unsupported type")
+)
@@ -0,0 +1,134 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// message is a test protobuf and msgpack message.
+type message struct {
+	s string
+}
+
+func (m *message) Marshal() ([]byte, error) {
+	return []byte("pb:" + m.s), nil
+}
+
+func (m *message) Unmarshal(buf []byte) error {
+	m.s = strings.TrimPrefix(string(buf), "pb:")
+	return nil
+}
+
+func (m *message) MarshalMsg(b []byte) ([]byte, error) {
+	return append(b, "msgp:"+m.s...), nil
+}
+
+func (m *message) UnmarshalMsg(buf []byte) ([]byte, error) {
+	m.s = strings.TrimPrefix(string(buf), "msgp:")
+	return nil, nil
+}
+
+func (m *message) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.s + `"`), nil
+}
+
+func TestRender(t *testing.T) {
+	r, err := New(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		accept string
+		status int
+		ctype  string
+		exp    string
+	}{
+		{"", http.StatusOK, "application/json; charset=utf-8", "\"a\"\n"},
+		{"*/*", http.StatusOK, "application/json; charset=utf-8", "\"a\"\n"},
+		{"application/x-protobuf", http.StatusOK, "application/x-protobuf", "pb:a"},
+		{"application/json;q=0.5, application/msgpack", http.StatusOK, "application/msgpack", "msgp:a"},
+		{"text/html, application/*;q=0.1", http.StatusOK, "application/json; charset=utf-8", "\"a\"\n"},
+		{"text/html", http.StatusNotAcceptable, "text/plain; charset=utf-8", "Not Acceptable\n"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		res := httptest.NewRecorder()
+		r.Render(res, req, http.StatusOK, &message{"a"})
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Header().Get("Content-Type"); s != test.ctype {
+			t.Errorf("test %d expected %q, got: %q", i, test.ctype, s)
+		}
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	if err := r.Render(res, req, http.StatusOK, "a"); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected %v, got: %v", ErrUnsupportedType, err)
+	}
+}
+
+func TestBind(t *testing.T) {
+	r, err := New(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tests := []struct {
+		ctype string
+		body  string
+		exp   string
+		err   error
+	}{
+		{"", `{"s":"a"}`, "a", nil},
+		{"application/json; charset=utf-8", `{"s":"b"}`, "b", nil},
+		{"application/x-protobuf", "pb:c", "c", nil},
+		{"application/msgpack", "msgp:d", "d", nil},
+		{"text/plain", "e", "", ErrUnsupportedMediaType},
+		{"bad;;", "e", "", ErrUnsupportedMediaType},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+		if test.ctype != "" {
+			req.Header.Set("Content-Type", test.ctype)
+		}
+		var v struct {
+			message
+			S string `json:"s"`
+		}
+		if err := r.Bind(req, &v); !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if s := v.S + v.s; s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestBindMaxBody(t *testing.T) {
+	r, err := New(nil, WithMaxBody(8))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var v struct {
+		S string `json:"s"`
+	}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"s":"too long"}`))
+	var e *http.MaxBytesError
+	if err := r.Bind(req, &v); !errors.As(err, &e) {
+		t.Errorf("expected %T, got: %v", e, err)
+	}
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"s":""}`))
+	if err := r.Bind(req, &v); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
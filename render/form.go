@@ -0,0 +1,271 @@
+package render
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidForm is the invalid form error.
+var ErrInvalidForm = errors.New("invalid form")
+
+// maxFormIndex is the maximum slice index of a form key.
+const maxFormIndex = 1000
+
+// defaultMaxMemory is the default maximum memory used to parse multipart
+// forms, with the remainder of files stored on disk.
+const defaultMaxMemory = 32 << 20
+
+// BindForm decodes the request's URL-encoded or multipart form into v, a
+// pointer to a struct (see DecodeForm). Renderer.Bind uses BindForm for form
+// content types.
+func BindForm(req *http.Request, v interface{}) error {
+	var files map[string][]*multipart.FileHeader
+	if err := req.ParseMultipartForm(defaultMaxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return fmt.Errorf("%w: %v", ErrInvalidForm, err)
+	}
+	if req.MultipartForm != nil {
+		files = req.MultipartForm.File
+	}
+	return DecodeForm(req.PostForm, files, v)
+}
+
+// DecodeForm decodes the form values and files into v, a pointer to a
+// struct. Struct fields are matched by their "form" tag (or field name), and
+// nested fields and slice elements are addressed with dotted and bracketed
+// keys:
+//
+//	type Item struct {
+//		SKU string `form:"sku"`
+//		Qty int    `form:"qty"`
+//	}
+//
+//	type Order struct {
+//		Name  string                  `form:"name"`  // name=carl
+//		Tags  []string                `form:"tags"`  // tags=a&tags=b
+//		Items []Item                  `form:"items"` // items[0].sku=a&items[0].qty=2
+//		Meta  map[string]string       `form:"meta"`  // meta[color]=red
+//		Photo *multipart.FileHeader   `form:"photo"`
+//		Docs  []*multipart.FileHeader `form:"docs"`
+//	}
+//
+// Repeated values are decoded into slices, and the first value is used for
+// other types. Values are decoded into strings, bools, numbers, and types
+// implementing encoding.TextUnmarshaler. Keys not matching a field are
+// ignored.
+func DecodeForm(values url.Values, files map[string][]*multipart.FileHeader, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a pointer to a struct", ErrUnsupportedType, v)
+	}
+	for key, vals := range values {
+		if err := decodeFormKey(rv.Elem(), key, vals, nil); err != nil {
+			return err
+		}
+	}
+	for key, fhs := range files {
+		if err := decodeFormKey(rv.Elem(), key, nil, fhs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeFormKey decodes the values or files for the key into v.
+func decodeFormKey(v reflect.Value, key string, vals []string, fhs []*multipart.FileHeader) error {
+	path, ok := formPath(key)
+	if !ok {
+		return fmt.Errorf("%w: invalid key %q", ErrInvalidForm, key)
+	}
+	if err := setForm(v, path, vals, fhs); err != nil {
+		return fmt.Errorf("%w: key %q: %v", ErrInvalidForm, key, err)
+	}
+	return nil
+}
+
+// formPath splits the form key into its path, for example "items[0].name"
+// into "items", "0", "name".
+func formPath(key string) ([]string, bool) {
+	var path []string
+	for key != "" {
+		i := strings.IndexAny(key, ".[")
+		if i == -1 {
+			return append(path, key), true
+		}
+		if i != 0 {
+			path = append(path, key[:i])
+		}
+		switch key[i] {
+		case '.':
+			key = key[i+1:]
+			if key == "" || len(path) == 0 {
+				return nil, false
+			}
+		case '[':
+			j := strings.IndexByte(key[i:], ']')
+			if j == -1 || len(path) == 0 {
+				return nil, false
+			}
+			path = append(path, key[i+1:i+j])
+			key = key[i+j+1:]
+		}
+	}
+	return path, len(path) != 0
+}
+
+// fileHeaderType is the type of multipart file headers.
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+
+// textUnmarshalerType is the encoding.TextUnmarshaler type.
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setForm sets the values or files at the path of v.
+func setForm(v reflect.Value, path []string, vals []string, fhs []*multipart.FileHeader) error {
+	switch {
+	case len(path) == 0:
+		return setFormValue(v, vals, fhs)
+	case v.Kind() == reflect.Pointer && v.Type() != fileHeaderType:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setForm(v.Elem(), path, vals, fhs)
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		f, ok := formField(v, path[0])
+		if !ok {
+			return nil
+		}
+		return setForm(f, path[1:], vals, fhs)
+	case reflect.Slice:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= maxFormIndex {
+			return fmt.Errorf("invalid index %q", path[0])
+		}
+		if i >= v.Len() {
+			v.Set(reflect.AppendSlice(v, reflect.MakeSlice(v.Type(), i+1-v.Len(), i+1-v.Len())))
+		}
+		return setForm(v.Index(i), path[1:], vals, fhs)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %v", v.Type().Key())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		k := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if e := v.MapIndex(k); e.IsValid() {
+			elem.Set(e)
+		}
+		if err := setForm(elem, path[1:], vals, fhs); err != nil {
+			return err
+		}
+		v.SetMapIndex(k, elem)
+		return nil
+	}
+	return fmt.Errorf("cannot address %v with %q", v.Type(), path[0])
+}
+
+// formField returns the struct field for the name.
+func formField(v reflect.Value, name string) (reflect.Value, bool) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		switch {
+		case tag == "-":
+			continue
+		case tag == "":
+			tag = f.Name
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setFormValue sets the values or files to v.
+func setFormValue(v reflect.Value, vals []string, fhs []*multipart.FileHeader) error {
+	switch {
+	case v.Type() == fileHeaderType:
+		if len(fhs) != 0 {
+			v.Set(reflect.ValueOf(fhs[0]))
+		}
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem() == fileHeaderType:
+		v.Set(reflect.ValueOf(fhs))
+		return nil
+	case fhs != nil:
+		return fmt.Errorf("cannot decode file into %v", v.Type())
+	case len(vals) == 0:
+		return nil
+	case v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalerType):
+		s := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setFormScalar(s.Index(i), val); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setFormScalar(v, vals[0])
+}
+
+// setFormScalar sets the value to v.
+func setFormScalar(v reflect.Value, val string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(val))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		if val == "on" {
+			val = "true"
+		}
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(val, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
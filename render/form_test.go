@@ -0,0 +1,130 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type formItem struct {
+	SKU string `form:"sku"`
+	Qty int    `form:"qty"`
+}
+
+type formOrder struct {
+	Name    string                 `form:"name"`
+	Tags    []string               `form:"tags"`
+	Items   []formItem             `form:"items"`
+	Meta    map[string]string      `form:"meta"`
+	Addr    *struct{ City string } `form:"addr"`
+	Gift    bool                   `form:"gift"`
+	Price   float64                `form:"price"`
+	Count   uint8                  `form:"count"`
+	When    time.Time              `form:"when"`
+	Ignored string                 `form:"-"`
+	Plain   string
+	Photo   *multipart.FileHeader   `form:"photo"`
+	Docs    []*multipart.FileHeader `form:"docs"`
+}
+
+func TestDecodeForm(t *testing.T) {
+	values := url.Values{
+		"name":          {"carl"},
+		"tags":          {"a", "b"},
+		"items[1].sku":  {"y"},
+		"items[0].sku":  {"x"},
+		"items[0][qty]": {"2"},
+		"meta[color]":   {"red"},
+		"addr.City":     {"Paris"},
+		"gift":          {"on"},
+		"price":         {"9.5"},
+		"count":         {"7"},
+		"when":          {"2024-01-02T03:04:05Z"},
+		"Ignored":       {"x"},
+		"-":             {"x"},
+		"Plain":         {"p"},
+		"unknown[0].x":  {"z"},
+	}
+	var v formOrder
+	if err := DecodeForm(values, nil, &v); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := formOrder{
+		Name:  "carl",
+		Tags:  []string{"a", "b"},
+		Items: []formItem{{"x", 2}, {"y", 0}},
+		Meta:  map[string]string{"color": "red"},
+		Addr:  &struct{ City string }{"Paris"},
+		Gift:  true,
+		Price: 9.5,
+		Count: 7,
+		When:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Plain: "p",
+	}
+	if !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %+v, got: %+v", exp, v)
+	}
+}
+
+func TestDecodeFormErrors(t *testing.T) {
+	tests := []url.Values{
+		{"items[x].sku": {"a"}},
+		{"items[5000].sku": {"a"}},
+		{"items[0": {"a"}},
+		{"[0]": {"a"}},
+		{"name.": {"a"}},
+		{"count": {"300"}},
+		{"gift": {"maybe"}},
+		{"name.first": {"a"}},
+	}
+	for i, test := range tests {
+		var v formOrder
+		if err := DecodeForm(test, nil, &v); !errors.Is(err, ErrInvalidForm) {
+			t.Errorf("test %d expected ErrInvalidForm, got: %v", i, err)
+		}
+	}
+	if err := DecodeForm(nil, nil, formOrder{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got: %v", err)
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	r, err := New(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	// urlencoded
+	req := httptest.NewRequest("POST", "/", strings.NewReader("name=carl&items[0].sku=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var v formOrder
+	if err := r.Bind(req, &v); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if v.Name != "carl" || len(v.Items) != 1 || v.Items[0].SKU != "x" {
+		t.Errorf("expected decoded form, got: %+v", v)
+	}
+	// multipart
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	_ = w.WriteField("name", "carl")
+	for _, name := range []string{"photo", "docs", "docs"} {
+		fw, _ := w.CreateFormFile(name, name+".txt")
+		fw.Write([]byte(name))
+	}
+	w.Close()
+	req = httptest.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	v = formOrder{}
+	if err := r.Bind(req, &v); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if v.Name != "carl" || v.Photo == nil || v.Photo.Filename != "photo.txt" || len(v.Docs) != 2 {
+		t.Errorf("expected decoded multipart form, got: %+v", v)
+	}
+}
@@ -0,0 +1,43 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+)
+
+// callbackRE matches valid JSONP callback names, such as "cb" or
+// "jQuery.cb_1".
+var callbackRE = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*(\.[a-zA-Z_$][a-zA-Z0-9_$]*)*$`)
+
+// JSON renders v as JSON, writing it to the response with the status. When
+// the renderer has a JSONP query parameter (see WithJSONP) that is present in
+// the request, the JSON is wrapped in the callback, and requests with an
+// invalid callback name are rejected with 400 Bad Request.
+func (r *Renderer) JSON(res http.ResponseWriter, req *http.Request, status int, v interface{}) error {
+	var callback string
+	if r.jsonp != "" {
+		if callback = req.URL.Query().Get(r.jsonp); callback != "" && (len(callback) > 128 || !callbackRE.MatchString(callback)) {
+			http.Error(res, "invalid callback", http.StatusBadRequest)
+			return ErrInvalidCallback
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	if callback == "" {
+		res.Header().Set("Content-Type", "application/json; charset=utf-8")
+		res.WriteHeader(status)
+		_, err := res.Write(buf.Bytes())
+		return err
+	}
+	res.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	res.WriteHeader(status)
+	// the leading comment prevents content sniffing based attacks
+	_, err := res.Write([]byte("/**/" + callback + "(" + string(bytes.TrimSpace(buf.Bytes())) + ");"))
+	return err
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		jsonp  string
+		query  string
+		status int
+		ctype  string
+		exp    string
+		err    error
+	}{
+		{"", "", http.StatusOK, "application/json; charset=utf-8", "{\"a\":\"\\u003cb\\u003e\"}\n", nil},
+		{"", "?callback=cb", http.StatusOK, "application/json; charset=utf-8", "{\"a\":\"\\u003cb\\u003e\"}\n", nil},
+		{"callback", "", http.StatusOK, "application/json; charset=utf-8", "{\"a\":\"\\u003cb\\u003e\"}\n", nil},
+		{"callback", "?callback=cb", http.StatusOK, "text/javascript; charset=utf-8", "/**/cb({\"a\":\"\\u003cb\\u003e\"});", nil},
+		{"callback", "?callback=jQuery.cb_1", http.StatusOK, "text/javascript; charset=utf-8", "/**/jQuery.cb_1({\"a\":\"\\u003cb\\u003e\"});", nil},
+		{"callback", "?callback=alert(1)", http.StatusBadRequest, "text/plain; charset=utf-8", "invalid callback\n", ErrInvalidCallback},
+		{"callback", "?callback=a..b", http.StatusBadRequest, "text/plain; charset=utf-8", "invalid callback\n", ErrInvalidCallback},
+		{"callback", "?callback=" + strings.Repeat("a", 129), http.StatusBadRequest, "text/plain; charset=utf-8", "invalid callback\n", ErrInvalidCallback},
+	}
+	for i, test := range tests {
+		r, err := New(nil, WithJSONP(test.jsonp))
+		if err != nil {
+			t.Fatalf("test %d expected no error, got: %v", i, err)
+		}
+		res := httptest.NewRecorder()
+		err = r.JSON(res, httptest.NewRequest("GET", "/"+test.query, nil), http.StatusOK, map[string]string{"a": "<b>"})
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Header().Get("Content-Type"); s != test.ctype {
+			t.Errorf("test %d expected %q, got: %q", i, test.ctype, s)
+		}
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
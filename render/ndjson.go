@@ -0,0 +1,141 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kenshaw/goji"
+)
+
+// ErrLineTooLong is the line too long error, returned when a NDJSON line
+// exceeds the maximum line size.
+var ErrLineTooLong = errors.New("line too long")
+
+// NDJSONContentType is the NDJSON (newline delimited JSON) media type.
+const NDJSONContentType = "application/x-ndjson"
+
+// defaultMaxLine is the default maximum size of a NDJSON line.
+const defaultMaxLine = 1 << 20
+
+// NDJSON streams a NDJSON (newline delimited JSON) response, setting the
+// response's Content-Type and invoking the func with a NDJSONWriter. The
+// response is streamed with goji.Stream, flushing encoded items to the client
+// in chunks and at intervals (see goji.WithStreamFlushInterval), and
+// returning the request context's error once the client has disconnected.
+//
+// For example:
+//
+//	err := render.NDJSON(res, req, func(w *render.NDJSONWriter) error {
+//		for item := range items {
+//			if err := w.Encode(item); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	}, goji.WithStreamFlushInterval(time.Second))
+func NDJSON(res http.ResponseWriter, req *http.Request, fn func(*NDJSONWriter) error, opts ...goji.StreamOption) error {
+	res.Header().Set("Content-Type", NDJSONContentType)
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	return goji.Stream(res, req, func(w *goji.StreamWriter) error {
+		return fn(NewNDJSONWriter(w))
+	}, opts...)
+}
+
+// NDJSONWriter writes a NDJSON (newline delimited JSON) response item by
+// item to a goji.StreamWriter (see NDJSON).
+type NDJSONWriter struct {
+	w   *goji.StreamWriter
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter creates a NDJSON writer for the stream writer. The
+// response's Content-Type must be set prior to streaming the response.
+func NewNDJSONWriter(w *goji.StreamWriter) *NDJSONWriter {
+	nw := &NDJSONWriter{w: w}
+	nw.enc = json.NewEncoder(&nw.buf)
+	return nw
+}
+
+// Encode writes v as a line of JSON. Values that cannot be encoded are not
+// written. Encode returns the request context's error once the client has
+// disconnected.
+func (w *NDJSONWriter) Encode(v interface{}) error {
+	w.buf.Reset()
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
+
+// Flush flushes the written items to the client.
+func (w *NDJSONWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// NDJSONReader reads NDJSON (newline delimited JSON) items, such as from a
+// bulk API request body, with a maximum line size.
+//
+// For example:
+//
+//	r := render.NewNDJSONReader(req.Body, 64<<10)
+//	for {
+//		var item Item
+//		switch err := r.Decode(&item); {
+//		case errors.Is(err, io.EOF):
+//			return nil
+//		case err != nil:
+//			return err
+//		}
+//		// ...
+//	}
+type NDJSONReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewNDJSONReader creates a NDJSON reader for the reader, with the maximum
+// line size (default 1 MiB, when 0).
+func NewNDJSONReader(r io.Reader, maxLine int) *NDJSONReader {
+	if maxLine <= 0 {
+		maxLine = defaultMaxLine
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, min(maxLine, 64<<10)), maxLine)
+	return &NDJSONReader{s: s}
+}
+
+// Decode decodes the next item into v, skipping blank lines. Decode returns
+// io.EOF when there are no more items, and ErrLineTooLong when a line exceeds
+// the maximum line size. Decode errors include the line number.
+func (r *NDJSONReader) Decode(v interface{}) error {
+	for r.s.Scan() {
+		r.line++
+		buf := bytes.TrimSpace(r.s.Bytes())
+		if len(buf) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(buf, v); err != nil {
+			return fmt.Errorf("line %d: %w", r.line, err)
+		}
+		return nil
+	}
+	switch err := r.s.Err(); {
+	case errors.Is(err, bufio.ErrTooLong):
+		return fmt.Errorf("line %d: %w", r.line+1, ErrLineTooLong)
+	case err != nil:
+		return err
+	}
+	return io.EOF
+}
+
+// Line returns the line number of the last decoded item.
+func (r *NDJSONReader) Line() int {
+	return r.line
+}
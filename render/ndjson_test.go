@@ -0,0 +1,109 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestNDJSON(t *testing.T) {
+	tests := []struct {
+		opts    []goji.StreamOption
+		flushed bool
+	}{
+		{[]goji.StreamOption{goji.WithStreamChunkSize(1)}, true},
+		{nil, false},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		err := NDJSON(res, httptest.NewRequest("GET", "/", nil), func(w *NDJSONWriter) error {
+			for _, v := range []interface{}{map[string]int{"a": 1}, []string{"b"}} {
+				if err := w.Encode(v); err != nil {
+					t.Fatalf("test %d expected no error, got: %v", i, err)
+				}
+			}
+			if res.Flushed != test.flushed {
+				t.Errorf("test %d expected flushed %t, got: %t", i, test.flushed, res.Flushed)
+			}
+			if err := w.Encode(func() {}); err == nil {
+				t.Errorf("test %d expected error, got: nil", i)
+			}
+			return nil
+		}, test.opts...)
+		if err != nil || !res.Flushed {
+			t.Errorf("test %d expected flush, got: %v", i, err)
+		}
+		if s := res.Header().Get("Content-Type"); s != NDJSONContentType {
+			t.Errorf("test %d expected %q, got: %q", i, NDJSONContentType, s)
+		}
+		if s, exp := res.Body.String(), "{\"a\":1}\n[\"b\"]\n"; s != exp {
+			t.Errorf("test %d expected %q, got: %q", i, exp, s)
+		}
+	}
+}
+
+func TestNDJSONDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	res := httptest.NewRecorder()
+	n := 0
+	err := NDJSON(res, req, func(w *NDJSONWriter) error {
+		for ; ; n++ {
+			if n == 2 {
+				cancel()
+			}
+			if err := w.Encode(n); err != nil {
+				return err
+			}
+		}
+	})
+	if !errors.Is(err, context.Canceled) || n != 2 {
+		t.Errorf("expected %v after 2 items, got: %v after %d", context.Canceled, err, n)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	tests := []struct {
+		s       string
+		maxLine int
+		exp     []int
+		err     string
+	}{
+		{"{\"n\":1}\n{\"n\":2}", 0, []int{1, 2}, "EOF"},
+		{"{\"n\":1}\r\n\n  \n{\"n\":2}\n", 0, []int{1, 2}, "EOF"},
+		{"{\"n\":1}\n{\"n\":\"x\"}\n", 0, []int{1}, "line 2: json: cannot unmarshal"},
+		{"{\"n\":1}\n{\"n\":22222222}\n", 12, []int{1}, "line 2: line too long"},
+	}
+	for i, test := range tests {
+		r := NewNDJSONReader(strings.NewReader(test.s), test.maxLine)
+		var ns []int
+		var err error
+		for {
+			var v struct {
+				N int `json:"n"`
+			}
+			if err = r.Decode(&v); err != nil {
+				break
+			}
+			ns = append(ns, v.N)
+		}
+		if !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("test %d expected error %q, got: %v", i, test.err, err)
+		}
+		if test.err == "EOF" && !errors.Is(err, io.EOF) {
+			t.Errorf("test %d expected io.EOF, got: %v", i, err)
+		}
+		if strings.Contains(test.err, "too long") && !errors.Is(err, ErrLineTooLong) {
+			t.Errorf("test %d expected %v, got: %v", i, ErrLineTooLong, err)
+		}
+		if !reflect.DeepEqual(ns, test.exp) {
+			t.Errorf("test %d expected %v, got: %v", i, test.exp, ns)
+		}
+	}
+}
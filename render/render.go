@@ -0,0 +1,356 @@
+// Package render provides HTML template and JSON rendering for use with
+// goji.Mux.
+//
+// Templates are loaded from a fs.FS, where each page template (a ".html" file
+// outside of the layouts and partials directories) is composed with all
+// layout and partial templates, and is referred to by its path without the
+// extension (for example, "users/show"):
+//
+//	templates/
+//		layouts/base.html   <html>...{{template "content" .}}...</html>
+//		partials/nav.html   <nav>...</nav>
+//		users/show.html     {{define "content"}}{{template "partials/nav" .}}<h1>{{.Params.name}}</h1>{{end}}
+//
+// Pages are rendered with per-request data (see Data), including the bound
+// route params, request ID, and CSRF token:
+//
+//	r, err := render.New(os.DirFS("templates"), render.WithLayout("layouts/base"))
+//	if err != nil {
+//		return err
+//	}
+//	mux.HandleFunc(goji.Get("/users/:name"), func(res http.ResponseWriter, req *http.Request) {
+//		r.HTML(res, req, http.StatusOK, "users/show", user)
+//	})
+//
+// API responses are rendered, and request bodies bound, using the codec
+// negotiated from the Accept and Content-Type headers (see Codec), with JSON,
+// gogo/protobuf, and tinylib/msgp codecs available by default:
+//
+//	if err := r.Bind(req, &user); err != nil {
+//		// ...
+//	}
+//	r.Render(res, req, http.StatusOK, user)
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+// Data is the data passed to templates.
+type Data struct {
+	// Data is the data passed to HTML.
+	Data interface{}
+	// Params are the bound route params.
+	Params map[string]string
+	// RequestID is the request ID.
+	RequestID string
+	// CSRFToken is the CSRF token.
+	CSRFToken string
+	// Values are additional values (see WithValues).
+	Values map[string]interface{}
+	// Request is the request.
+	Request *http.Request
+}
+
+// Renderer is a HTML template renderer.
+type Renderer struct {
+	fsys      fs.FS
+	layouts   string
+	partials  string
+	layout    string
+	funcs     template.FuncMap
+	requestID func(*http.Request) string
+	csrf      func(*http.Request) string
+	values    func(*http.Request) map[string]interface{}
+	reload    bool
+	jsonp     string
+	codecs    []Codec
+	maxBody   int64
+
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+	modTime   time.Time
+}
+
+// New creates a new renderer for the HTML templates in the file system. The
+// file system may be nil when not rendering HTML.
+func New(fsys fs.FS, opts ...Option) (*Renderer, error) {
+	r := &Renderer{
+		fsys:     fsys,
+		layouts:  "layouts",
+		partials: "partials",
+		requestID: func(req *http.Request) string {
+			return req.Header.Get("X-Request-Id")
+		},
+		codecs:  []Codec{JSON, GogoProto, Msgp},
+		maxBody: 10 << 20,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load parses the templates.
+func (r *Renderer) load() error {
+	if r.fsys == nil {
+		return nil
+	}
+	var shared, pages []string
+	var modTime time.Time
+	err := fs.WalkDir(r.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case d.IsDir() || path.Ext(name) != ".html":
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+		if within(name, r.layouts) || within(name, r.partials) {
+			shared = append(shared, name)
+		} else {
+			pages = append(pages, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	base := template.New("").Funcs(r.funcs)
+	for _, name := range shared {
+		if err := r.parse(base, name); err != nil {
+			return err
+		}
+	}
+	templates := make(map[string]*template.Template, len(pages))
+	for _, name := range pages {
+		t, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		if err := r.parse(t, name); err != nil {
+			return err
+		}
+		templates[strings.TrimSuffix(name, ".html")] = t
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates, r.modTime = templates, modTime
+	return nil
+}
+
+// parse parses the named file into the template.
+func (r *Renderer) parse(t *template.Template, name string) error {
+	buf, err := fs.ReadFile(r.fsys, name)
+	if err != nil {
+		return err
+	}
+	if _, err := t.New(strings.TrimSuffix(name, ".html")).Parse(string(buf)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// changed returns whether or not any template has been modified since the
+// templates were loaded.
+func (r *Renderer) changed() bool {
+	r.mu.RLock()
+	modTime := r.modTime
+	r.mu.RUnlock()
+	var changed bool
+	fs.WalkDir(r.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || changed || d.IsDir() || path.Ext(name) != ".html" {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(modTime) {
+			changed = true
+		}
+		return nil
+	})
+	return changed
+}
+
+// HTML renders the named page with the data and the request's data (see
+// Data), writing the rendered page to the response with the status. When
+// the page cannot be rendered, a 500 Internal Server Error is written and the
+// error is returned.
+func (r *Renderer) HTML(res http.ResponseWriter, req *http.Request, status int, name string, data interface{}) error {
+	if r.reload && r.fsys != nil && r.changed() {
+		if err := r.load(); err != nil {
+			http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return err
+		}
+	}
+	r.mu.RLock()
+	t, ok := r.templates[name]
+	r.mu.RUnlock()
+	if !ok {
+		http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	exec := name
+	if r.layout != "" {
+		exec = r.layout
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, exec, r.data(req, data)); err != nil {
+		http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	res.WriteHeader(status)
+	_, err := res.Write(buf.Bytes())
+	return err
+}
+
+// data returns the template data for the request.
+func (r *Renderer) data(req *http.Request, data interface{}) Data {
+	d := Data{
+		Data:      data,
+		Params:    goji.Params(req),
+		RequestID: r.requestID(req),
+		Request:   req,
+	}
+	if r.csrf != nil {
+		d.CSRFToken = r.csrf(req)
+	}
+	if r.values != nil {
+		d.Values = r.values(req)
+	}
+	return d
+}
+
+// within returns whether or not the name is within the directory.
+func within(name, dir string) bool {
+	return dir != "" && strings.HasPrefix(name, dir+"/")
+}
+
+// ErrUnknownTemplate is the unknown template error.
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// ErrInvalidCallback is the invalid JSONP callback error.
+var ErrInvalidCallback = errors.New("invalid callback")
+
+// Option is a renderer option.
+type Option func(*Renderer)
+
+// WithLayout is a renderer option to set the layout template executed when
+// rendering pages (for example, "layouts/base"). Pages are expected to
+// define the templates used by the layout (such as "content").
+func WithLayout(layout string) Option {
+	return func(r *Renderer) {
+		r.layout = layout
+	}
+}
+
+// WithLayoutsDir is a renderer option to set the directory containing layout
+// templates (default "layouts").
+func WithLayoutsDir(dir string) Option {
+	return func(r *Renderer) {
+		r.layouts = dir
+	}
+}
+
+// WithPartialsDir is a renderer option to set the directory containing
+// partial templates (default "partials").
+func WithPartialsDir(dir string) Option {
+	return func(r *Renderer) {
+		r.partials = dir
+	}
+}
+
+// WithFuncs is a renderer option to add template funcs.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(r *Renderer) {
+		if r.funcs == nil {
+			r.funcs = make(template.FuncMap)
+		}
+		for k, v := range funcs {
+			r.funcs[k] = v
+		}
+	}
+}
+
+// WithRequestID is a renderer option to set the func used to determine the
+// request ID (default the X-Request-Id header).
+func WithRequestID(f func(*http.Request) string) Option {
+	return func(r *Renderer) {
+		r.requestID = f
+	}
+}
+
+// WithCSRF is a renderer option to set the func used to determine the CSRF
+// token for the request.
+func WithCSRF(f func(*http.Request) string) Option {
+	return func(r *Renderer) {
+		r.csrf = f
+	}
+}
+
+// WithValues is a renderer option to set the func used to inject additional
+// per-request values.
+func WithValues(f func(*http.Request) map[string]interface{}) Option {
+	return func(r *Renderer) {
+		r.values = f
+	}
+}
+
+// WithJSONP is a renderer option to wrap JSON responses in a JSONP callback
+// when the query parameter (for example, "callback") is present, for legacy
+// integrations that cannot use CORS. Callback names are validated, and
+// requests with invalid callback names are rejected.
+func WithJSONP(param string) Option {
+	return func(r *Renderer) {
+		r.jsonp = param
+	}
+}
+
+// WithCodec is a renderer option to add a codec, replacing any codec for the
+// same content type.
+func WithCodec(c Codec) Option {
+	return func(r *Renderer) {
+		for i, codec := range r.codecs {
+			if codec.ContentType() == c.ContentType() {
+				r.codecs[i] = c
+				return
+			}
+		}
+		r.codecs = append(r.codecs, c)
+	}
+}
+
+// WithMaxBody is a renderer option to set the maximum request body size
+// decoded by Bind (default 10 MiB).
+func WithMaxBody(maxBody int64) Option {
+	return func(r *Renderer) {
+		r.maxBody = maxBody
+	}
+}
+
+// WithReload is a renderer option to re-parse templates when they are
+// modified, for use during development.
+func WithReload(reload bool) Option {
+	return func(r *Renderer) {
+		r.reload = reload
+	}
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kenshaw/goji"
+)
+
+func TestHTML(t *testing.T) {
+	now := time.Now()
+	fsys := fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`<html>{{template "content" .}}</html>`), ModTime: now},
+		"partials/nav.html": {Data: []byte(`<nav>{{.RequestID}}</nav>`), ModTime: now},
+		"users/show.html":   {Data: []byte(`{{define "content"}}{{template "partials/nav" .}}<h1>{{.Params.name}} {{.Data}} {{.CSRFToken}} {{.Values.v}}</h1>{{end}}`), ModTime: now},
+		"bad.html":          {Data: []byte(`{{define "content"}}{{.Data.Missing}}{{end}}`), ModTime: now},
+		"readme.txt":        {Data: []byte(`{{`), ModTime: now},
+	}
+	r, err := New(fsys,
+		WithLayout("layouts/base"),
+		WithCSRF(func(*http.Request) string { return "token" }),
+		WithValues(func(*http.Request) map[string]interface{} { return map[string]interface{}{"v": 1} }),
+		WithReload(true),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := goji.New()
+	m.HandleFunc(goji.Get("/:page/:name"), func(res http.ResponseWriter, req *http.Request) {
+		r.HTML(res, req, http.StatusCreated, goji.Param(req, "page"), "<b>")
+	})
+	serve := func(path string) (int, string) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Request-Id", "abc")
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		return res.Code, res.Body.String()
+	}
+	tests := []struct {
+		path   string
+		status int
+		exp    string
+	}{
+		{"/users%2Fshow/carl", http.StatusCreated, "<html><nav>abc</nav><h1>carl &lt;b&gt; token 1</h1></html>"},
+		{"/bad/carl", http.StatusInternalServerError, "Internal Server Error\n"},
+		{"/missing/carl", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+	for i, test := range tests {
+		if code, body := serve(test.path); code != test.status || body != test.exp {
+			t.Errorf("test %d expected %d %q, got: %d %q", i, test.status, test.exp, code, body)
+		}
+	}
+
+	// reload
+	fsys["partials/nav.html"] = &fstest.MapFile{Data: []byte(`<nav>reloaded</nav>`), ModTime: now.Add(time.Second)}
+	if _, body := serve("/users%2Fshow/carl"); !strings.Contains(body, "<nav>reloaded</nav>") {
+		t.Errorf("expected reloaded template, got: %q", body)
+	}
+}
+
+func TestNewError(t *testing.T) {
+	if _, err := New(fstest.MapFS{"a.html": {Data: []byte(`{{`)}}); err == nil {
+		t.Errorf("expected error, got: nil")
+	}
+}
@@ -2,9 +2,13 @@ package goji
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"unsafe"
 )
 
 // Router is the shared router interface.
@@ -14,6 +18,7 @@ type Router interface {
 }
 
 type route struct {
+	id      uint64
 	matcher Matcher
 	handler http.Handler
 }
@@ -39,13 +44,40 @@ type router struct {
 	routes   []route
 	methods  map[string]*trieNode
 	wildcard trieNode
+	// compacted is whether or not the tries share nodes after Compile, and
+	// must be copied before being modified.
+	compacted bool
+	// version is the route table version, used by the dynamic router.
+	version uint64
+	// next is the id of the last added route.
+	next uint64
 }
 
 func (r *router) Handle(matcher Matcher, handler http.Handler) {
+	r.handle(matcher, handler)
+}
+
+// handle adds the route, returning the route's id.
+func (r *router) handle(matcher Matcher, handler http.Handler) uint64 {
+	r.next++
+	r.add(route{id: r.next, matcher: matcher, handler: handler})
+	return r.next
+}
+
+// add adds the route to the router's tries.
+func (r *router) add(rt route) {
+	if r.compacted {
+		r.wildcard = *r.wildcard.clone()
+		for method, tn := range r.methods {
+			r.methods[method] = tn.clone()
+		}
+		r.compacted = false
+	}
+
 	i := len(r.routes)
-	r.routes = append(r.routes, route{matcher: matcher, handler: handler})
+	r.routes = append(r.routes, rt)
 
-	prefix, methods := matcher.Prefix(), matcher.Methods()
+	prefix, methods := rt.matcher.Prefix(), rt.matcher.Methods()
 	if methods == nil {
 		r.wildcard.add(prefix, i)
 		for _, sub := range r.methods {
@@ -98,6 +130,123 @@ func (r *router) Route(req *http.Request) *http.Request {
 	return req.WithContext(&match{Context: ctx})
 }
 
+// Allowed returns the methods of the method specific routes matching the
+// request's path, with OPTIONS, or nil when there are no matching routes.
+func (r *router) Allowed(req *http.Request) []string {
+	var methods []string
+	for method := range r.methods {
+		req2 := *req
+		req2.Method = method
+		if m := Matched(r.Route(&req2)); m != nil && m.Methods() != nil {
+			methods = append(methods, method)
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	if !slices.Contains(methods, "OPTIONS") {
+		methods = append(methods, "OPTIONS")
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// RouterStats are statistics on the shape and memory use of a router's
+// tries.
+type RouterStats struct {
+	// Routes is the number of registered routes.
+	Routes int `json:"routes"`
+	// Methods is the number of per-method tries (in addition to the wildcard
+	// trie).
+	Methods int `json:"methods"`
+	// Nodes is the number of trie nodes.
+	Nodes int `json:"nodes"`
+	// Leaves is the number of trie nodes without children.
+	Leaves int `json:"leaves"`
+	// MaxDepth is the maximum depth of the tries.
+	MaxDepth int `json:"max_depth"`
+	// AvgLeafRoutes is the average number of candidate routes per leaf node.
+	AvgLeafRoutes float64 `json:"avg_leaf_routes"`
+	// Memory is the estimated memory used by the tries, in bytes.
+	Memory int `json:"memory"`
+}
+
+// Compile compacts the router's tries by deduplicating identical subtrees
+// (which are commonly created when cloning the wildcard trie for each HTTP
+// method). The tries are transparently copied if routes are added after
+// compaction.
+func (r *router) Compile() {
+	canon := make(map[string]*trieNode)
+	r.wildcard.compact(canon)
+	for method, tn := range r.methods {
+		r.methods[method] = tn.compact(canon)
+	}
+	r.compacted = true
+}
+
+// Stats returns statistics on the shape and memory use of the router's
+// tries.
+func (r *router) Stats() RouterStats {
+	stats := RouterStats{
+		Routes:  len(r.routes),
+		Methods: len(r.methods),
+	}
+	var leafRoutes int
+	seen := make(map[*trieNode]bool)
+	r.wildcard.stats(&stats, &leafRoutes, seen, 1)
+	for _, tn := range r.methods {
+		stats.Memory += int(unsafe.Sizeof(tn))
+		tn.stats(&stats, &leafRoutes, seen, 1)
+	}
+	if stats.Leaves != 0 {
+		stats.AvgLeafRoutes = float64(leafRoutes) / float64(stats.Leaves)
+	}
+	return stats
+}
+
+// remove removes the route with the id, rebuilding the router's tries.
+// Returns the removed route, and false when there is no route with the id.
+func (r *router) remove(id uint64) (route, bool) {
+	i := slices.IndexFunc(r.routes, func(rt route) bool {
+		return rt.id == id
+	})
+	if i == -1 {
+		return route{}, false
+	}
+	rt := r.routes[i]
+	r.rebuild(slices.Delete(slices.Clone(r.routes), i, i+1))
+	return rt, true
+}
+
+// replace replaces the matcher and handler of the route with the id,
+// retaining the route's position, and rebuilding the router's tries. Returns
+// the replaced route, and false when there is no route with the id.
+func (r *router) replace(id uint64, matcher Matcher, handler http.Handler) (route, bool) {
+	i := slices.IndexFunc(r.routes, func(rt route) bool {
+		return rt.id == id
+	})
+	if i == -1 {
+		return route{}, false
+	}
+	rt, routes := r.routes[i], slices.Clone(r.routes)
+	routes[i] = route{id: id, matcher: matcher, handler: handler}
+	r.rebuild(routes)
+	return rt, true
+}
+
+// rebuild rebuilds the router's tries from the routes.
+func (r *router) rebuild(routes []route) {
+	r.routes, r.methods, r.wildcard, r.compacted = nil, nil, trieNode{}, false
+	for _, rt := range routes {
+		r.add(rt)
+	}
+}
+
+// list returns the registered routes, in registration order.
+func (r *router) list() []route {
+	return append([]route(nil), r.routes...)
+}
+
 type child struct {
 	prefix string
 	node   *trieNode
@@ -160,6 +309,52 @@ func (tn *trieNode) clone() *trieNode {
 	return clone
 }
 
+// compact replaces the node's children with their canonical (identical)
+// node, returning the node's canonical node.
+func (tn *trieNode) compact(canon map[string]*trieNode) *trieNode {
+	var key strings.Builder
+	for _, i := range tn.routes {
+		key.WriteString(strconv.Itoa(i))
+		key.WriteByte(',')
+	}
+	for i := range tn.children {
+		tn.children[i].node = tn.children[i].node.compact(canon)
+		key.WriteByte('|')
+		key.WriteString(strconv.Quote(tn.children[i].prefix))
+		fmt.Fprintf(&key, "%p", tn.children[i].node)
+	}
+	if c, ok := canon[key.String()]; ok {
+		return c
+	}
+	canon[key.String()] = tn
+	return tn
+}
+
+func (tn *trieNode) stats(stats *RouterStats, leafRoutes *int, seen map[*trieNode]bool, depth int) {
+	if depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+	if seen[tn] {
+		for _, c := range tn.children {
+			c.node.stats(stats, leafRoutes, seen, depth+1)
+		}
+		return
+	}
+	seen[tn] = true
+	stats.Nodes++
+	stats.Memory += int(unsafe.Sizeof(*tn)) +
+		cap(tn.routes)*int(unsafe.Sizeof(int(0))) +
+		cap(tn.children)*int(unsafe.Sizeof(child{}))
+	if len(tn.children) == 0 {
+		stats.Leaves++
+		*leafRoutes += len(tn.routes)
+	}
+	for _, c := range tn.children {
+		stats.Memory += len(c.prefix)
+		c.node.stats(stats, leafRoutes, seen, depth+1)
+	}
+}
+
 // We can be a teensy bit more efficient here: we're maintaining a sorted list,
 // so we know exactly where to insert the new element. But since that involves
 // more bookkeeping and makes the code messier, let's cross that bridge when we
@@ -236,3 +236,77 @@ func (contextMatcher) Methods() map[string]struct{} { return nil }
 type intHandler int
 
 func (intHandler) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+func TestRouterStats(t *testing.T) {
+	r := new(router)
+	for _, p := range []*PathSpec{Get("/a/b"), Get("/a/c"), Post("/a/b"), NewPathSpec("/")} {
+		r.Handle(p, intHandler(0))
+	}
+	stats := r.Stats()
+	if stats.Routes != 4 || stats.Methods != 3 {
+		t.Errorf("expected routes=4 methods=3, got: %+v", stats)
+	}
+	// wildcard: "" -> "/"
+	// GET, HEAD: "" -> "/" -> "a/" -> "b", "c"
+	// POST: "" -> "/" -> "a/b"
+	if stats.Nodes != 15 || stats.Leaves != 6 || stats.MaxDepth != 4 {
+		t.Errorf("expected nodes=15 leaves=6 max_depth=4, got: %+v", stats)
+	}
+	if stats.AvgLeafRoutes != 11.0/6.0 || stats.Memory <= 0 {
+		t.Errorf("expected avg_leaf_routes=11/6 memory>0, got: %+v", stats)
+	}
+}
+
+func TestRouterCompile(t *testing.T) {
+	specs := []*PathSpec{
+		NewPathSpec("/"),
+		Get("/users/:name"),
+		Post("/users/:name"),
+		Put("/users/:name"),
+		NewPathSpec("/users/*"),
+		Get("/posts/:id"),
+		Delete("/posts/:id"),
+		NewPathSpec("/static/*"),
+	}
+	r, exp := new(router), new(router)
+	for i, p := range specs {
+		r.Handle(p, intHandler(i))
+		exp.Handle(p, intHandler(i))
+	}
+	before := r.Stats()
+	r.Compile()
+	after := r.Stats()
+	if after.Nodes >= before.Nodes || after.Memory >= before.Memory {
+		t.Errorf("expected compaction to reduce nodes and memory, before: %+v, after: %+v", before, after)
+	}
+	if after.Routes != before.Routes || after.MaxDepth != before.MaxDepth {
+		t.Errorf("expected routes and max depth to be unchanged, before: %+v, after: %+v", before, after)
+	}
+
+	paths := []string{"/", "/users/carl", "/users/carl/photos", "/posts/1", "/static/a.css", "/nope"}
+	methods := []string{"GET", "HEAD", "POST", "PUT", "DELETE", "PATCH"}
+	check := func() {
+		for _, method := range methods {
+			for _, path := range paths {
+				req := reqPath(method, path)
+				h1, _ := r.Route(req).Context().Value(handlerKey).(http.Handler)
+				h2, _ := exp.Route(req).Context().Value(handlerKey).(http.Handler)
+				if h1 != h2 {
+					t.Errorf("%s %s expected %v, got: %v", method, path, h2, h1)
+				}
+			}
+		}
+	}
+	check()
+
+	// add after compile
+	for _, p := range []*PathSpec{Patch("/users/:name"), Get("/nope")} {
+		r.Handle(p, intHandler(len(specs)))
+		exp.Handle(p, intHandler(len(specs)))
+		specs = append(specs, p)
+	}
+	check()
+	if stats := r.Stats(); stats.Nodes != exp.Stats().Nodes {
+		t.Errorf("expected %d nodes, got: %d", exp.Stats().Nodes, stats.Nodes)
+	}
+}
@@ -0,0 +1,67 @@
+package goji
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// rpcContentTypes are the media type prefixes of gRPC, gRPC-Web, and
+// Connect streaming requests.
+var rpcContentTypes = []string{
+	"application/grpc",
+	"application/grpc-web",
+	"application/grpc-web-text",
+	"application/connect",
+}
+
+// IsRPC returns whether or not the request is a gRPC, gRPC-Web, or Connect
+// protocol request, as determined by its content type (such as
+// "application/grpc-web+proto"), or the Connect-Protocol-Version header (or
+// "connect" query parameter) of Connect unary requests.
+func IsRPC(req *http.Request) bool {
+	if req.Header.Get("Connect-Protocol-Version") != "" || req.Method == "GET" && req.URL.Query().Get("connect") != "" {
+		return true
+	}
+	typ, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	base, _, _ := strings.Cut(typ, "+")
+	for _, t := range rpcContentTypes {
+		if base == t {
+			return true
+		}
+	}
+	return false
+}
+
+// rpcMatcher is a Matcher that matches gRPC, gRPC-Web, and Connect protocol
+// requests.
+type rpcMatcher struct {
+	Matcher
+}
+
+// RPC returns a Matcher that matches gRPC, gRPC-Web, and Connect protocol
+// requests (see IsRPC) that also match the matcher, allowing RPC traffic to
+// be routed to a mounted RPC handler while REST traffic for the same prefix
+// is routed to regular handlers:
+//
+//	mux.Handle(goji.RPC(goji.NewPathSpec("/api/*")), grpcWebHandler)
+//	mux.Handle(goji.Get("/api/users"), users)
+func RPC(matcher Matcher) Matcher {
+	return rpcMatcher{matcher}
+}
+
+// Match satisfies the Matcher interface.
+func (m rpcMatcher) Match(req *http.Request) *http.Request {
+	if !IsRPC(req) {
+		return nil
+	}
+	return m.Matcher.Match(req)
+}
+
+// Unwrap returns the wrapped Matcher.
+func (m rpcMatcher) Unwrap() Matcher {
+	return m.Matcher
+}
@@ -0,0 +1,44 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRPC(t *testing.T) {
+	m := New()
+	m.HandleFunc(RPC(NewPathSpec("/api/*")), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("rpc"))
+	})
+	m.HandleFunc(NewPathSpec("/api/*"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("rest"))
+	})
+	tests := []struct {
+		method  string
+		target  string
+		headers map[string]string
+		exp     string
+	}{
+		{"POST", "/api/users.v1.Users/Get", map[string]string{"Content-Type": "application/grpc-web+proto"}, "rpc"},
+		{"POST", "/api/users.v1.Users/Get", map[string]string{"Content-Type": "application/grpc-web-text"}, "rpc"},
+		{"POST", "/api/users.v1.Users/Get", map[string]string{"Content-Type": "application/grpc"}, "rpc"},
+		{"POST", "/api/users.v1.Users/Watch", map[string]string{"Content-Type": "application/connect+json"}, "rpc"},
+		{"POST", "/api/users.v1.Users/Get", map[string]string{"Content-Type": "application/json", "Connect-Protocol-Version": "1"}, "rpc"},
+		{"GET", "/api/users.v1.Users/Get?connect=v1&encoding=json", nil, "rpc"},
+		{"POST", "/api/users", map[string]string{"Content-Type": "application/json"}, "rest"},
+		{"POST", "/api/users", map[string]string{"Content-Type": "application/grpcx"}, "rest"},
+		{"GET", "/api/users", nil, "rest"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.target, nil)
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package goji
+
+import (
+	"net/http"
+	"strings"
+)
+
+// SchemeSpec provides a Matcher that matches requests based on the request's
+// scheme ("http" or "https").
+//
+// SchemeSpec is intended for use with And, allowing HTTPS-only routes and
+// HTTP redirect routes to be registered on the same Mux:
+//
+//	mux.Handle(goji.And(goji.NewPathSpec("/*"), goji.Scheme("http").TrustForwarded()), redirect)
+//	mux.HandleFunc(goji.Get("/users"), users)
+type SchemeSpec struct {
+	scheme    string
+	forwarded bool
+}
+
+// Scheme returns a SchemeSpec that matches requests with the scheme. The
+// scheme of a request is "https" when the request was received over TLS, the
+// request URL's scheme when set (see middleware.Forwarded), and "http"
+// otherwise.
+func Scheme(scheme string) *SchemeSpec {
+	return &SchemeSpec{
+		scheme: strings.ToLower(scheme),
+	}
+}
+
+// TrustForwarded sets the spec to honor the X-Forwarded-Proto header, as sent
+// by a load balancer terminating TLS, returning the spec. Only use with a
+// load balancer that sets (or strips) the header.
+func (s *SchemeSpec) TrustForwarded() *SchemeSpec {
+	s.forwarded = true
+	return s
+}
+
+// Match satisfies the Matcher interface.
+func (s *SchemeSpec) Match(req *http.Request) *http.Request {
+	if s.requestScheme(req) != s.scheme {
+		return nil
+	}
+	return req
+}
+
+// Methods satisfies the Matcher interface.
+func (s *SchemeSpec) Methods() map[string]struct{} {
+	return nil
+}
+
+// Prefix satisfies the Matcher interface.
+func (s *SchemeSpec) Prefix() string {
+	return ""
+}
+
+// String satisfies the fmt.Stringer interface.
+func (s *SchemeSpec) String() string {
+	return "Scheme(" + s.scheme + ")"
+}
+
+// requestScheme returns the scheme of the request.
+func (s *SchemeSpec) requestScheme(req *http.Request) string {
+	if s.forwarded {
+		proto, _, _ := strings.Cut(req.Header.Get("X-Forwarded-Proto"), ",")
+		if proto = strings.TrimSpace(proto); proto != "" {
+			return strings.ToLower(proto)
+		}
+	}
+	switch {
+	case req.TLS != nil:
+		return "https"
+	case req.URL.Scheme != "":
+		return strings.ToLower(req.URL.Scheme)
+	}
+	return "http"
+}
@@ -0,0 +1,62 @@
+package goji
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScheme(t *testing.T) {
+	tests := []struct {
+		m     *SchemeSpec
+		tls   bool
+		url   string
+		proto string
+		exp   bool
+	}{
+		{Scheme("http"), false, "/", "", true},
+		{Scheme("https"), false, "/", "", false},
+		{Scheme("HTTPS"), true, "/", "", true},
+		{Scheme("https"), false, "https://example.com/", "", true},
+		{Scheme("http"), false, "/", "https", true},
+		{Scheme("https"), false, "/", "https", false},
+		{Scheme("https").TrustForwarded(), false, "/", "HTTPS", true},
+		{Scheme("https").TrustForwarded(), false, "/", "https, http", true},
+		{Scheme("http").TrustForwarded(), true, "/", "http", true},
+		{Scheme("https").TrustForwarded(), true, "/", "", true},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.url, nil)
+		if test.tls {
+			req.TLS = new(tls.ConnectionState)
+		}
+		if test.proto != "" {
+			req.Header.Set("X-Forwarded-Proto", test.proto)
+		}
+		if ok := test.m.Match(req) != nil; ok != test.exp {
+			t.Errorf("test %d expected %t, got: %t", i, test.exp, ok)
+		}
+	}
+}
+
+func TestSchemeMux(t *testing.T) {
+	m := New()
+	m.Handle(And(NewPathSpec("/*"), Scheme("http").TrustForwarded()), http.RedirectHandler("https://example.com/", http.StatusMovedPermanently))
+	m.HandleFunc(Get("/users"), func(http.ResponseWriter, *http.Request) {})
+	for i, test := range []struct {
+		proto  string
+		status int
+	}{
+		{"http", http.StatusMovedPermanently},
+		{"https", http.StatusOK},
+	} {
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Header.Set("X-Forwarded-Proto", test.proto)
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected %d, got: %d", i, test.status, res.Code)
+		}
+	}
+}
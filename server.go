@@ -0,0 +1,355 @@
+package goji
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Hook is a server lifecycle hook.
+type Hook func(context.Context) error
+
+// Run serves the handler on the address until the context is canceled or the
+// process receives a SIGINT or SIGTERM signal, at which point the server is
+// gracefully shut down. When the handler is a Mux, it is drained (see
+// Mux.Drain) prior to shutdown, failing readiness checks (see
+// health.Readiness), and the server continues serving for the drain delay
+// (see WithDrainDelay) before shutting down.
+//
+// OnStart hooks are invoked in order, each with the hook timeout, after all
+// listeners have been opened but before serving begins, so that listen
+// errors (such as an address already in use) are reported before any hook
+// runs, and hooks can rely on the listeners accepting connections once
+// serving begins. OnShutdown hooks are invoked in order, each with the hook
+// timeout, after the server has shut down. All errors encountered are
+// returned, joined with errors.Join.
+//
+// Run allows the main func for a service to be written as:
+//
+//	func main() {
+//		mux := goji.New()
+//		mux.HandleFunc(goji.Get("/"), index)
+//		if err := goji.Run(context.Background(), ":3000", mux); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Run(ctx context.Context, addr string, handler http.Handler, opts ...ServerOption) error {
+	primary := &httpServer{
+		server: &http.Server{
+			Addr:    addr,
+			Handler: handler,
+		},
+	}
+	s := &server{
+		server:          primary,
+		servers:         []*httpServer{primary},
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+		hookTimeout:     15 * time.Second,
+		shutdownTimeout: 30 * time.Second,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s.run(ctx)
+}
+
+// server is a signal-aware server runner.
+type server struct {
+	server          *httpServer
+	servers         []*httpServer
+	signals         []os.Signal
+	onStart         []Hook
+	onShutdown      []Hook
+	connState       []func(net.Conn, http.ConnState)
+	drainDelay      time.Duration
+	hookTimeout     time.Duration
+	shutdownTimeout time.Duration
+}
+
+// run runs the server.
+func (s *server) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if len(s.signals) != 0 {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, s.signals...)
+		defer signal.Stop(ch)
+		go func() {
+			select {
+			case <-ch:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	if len(s.connState) != 0 {
+		for _, srv := range s.servers {
+			srv.server.ConnState = connState(srv.server.ConnState, s.connState)
+		}
+	}
+
+	// listen
+	var errs []error
+	listeners := make([]net.Listener, len(s.servers))
+	for i, srv := range s.servers {
+		var err error
+		if listeners[i], err = srv.listen(); err != nil {
+			for _, l := range listeners[:i] {
+				l.Close()
+			}
+			return err
+		}
+	}
+
+	// start hooks
+	for _, h := range s.onStart {
+		if err := s.hook(ctx, h); err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return err
+		}
+	}
+
+	// serve
+	done := make(chan error, len(s.servers))
+	for i, srv := range s.servers {
+		go func(srv *httpServer, l net.Listener) {
+			done <- srv.serve(l)
+		}(srv, listeners[i])
+	}
+	select {
+	case err := <-done:
+		if err != nil && err != http.ErrServerClosed {
+			errs = append(errs, err)
+		}
+	case <-ctx.Done():
+	}
+
+	// shutdown
+	for _, srv := range s.servers {
+		if d, ok := srv.server.Handler.(interface{ Drain() }); ok {
+			d.Drain()
+		}
+	}
+	if s.drainDelay > 0 {
+		t := time.NewTimer(s.drainDelay)
+		select {
+		case <-t.C:
+		case err := <-done:
+			t.Stop()
+			if err != nil && err != http.ErrServerClosed {
+				errs = append(errs, err)
+			}
+		}
+	}
+	sctx, scancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	for _, srv := range s.servers {
+		if err := srv.server.Shutdown(sctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	scancel()
+
+	for _, h := range s.onShutdown {
+		if err := s.hook(context.Background(), h); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// httpServer is a http.Server and its listener.
+type httpServer struct {
+	server   *http.Server
+	listener net.Listener
+	listenf  func() (net.Listener, error)
+}
+
+// listen returns the server's listener, listening on the server's address if
+// no listener was provided.
+func (srv *httpServer) listen() (net.Listener, error) {
+	switch {
+	case srv.listener != nil:
+		return srv.listener, nil
+	case srv.listenf != nil:
+		return srv.listenf()
+	}
+	addr := srv.server.Addr
+	switch {
+	case addr == "" && srv.server.TLSConfig != nil:
+		addr = ":https"
+	case addr == "":
+		addr = ":http"
+	}
+	return net.Listen("tcp", addr)
+}
+
+// serve serves on the listener.
+func (srv *httpServer) serve(l net.Listener) error {
+	if srv.server.TLSConfig != nil {
+		return srv.server.ServeTLS(l, "", "")
+	}
+	return srv.server.Serve(l)
+}
+
+// hook invokes the hook with the hook timeout.
+func (s *server) hook(ctx context.Context, h Hook) error {
+	ctx, cancel := context.WithTimeout(ctx, s.hookTimeout)
+	defer cancel()
+	ch := make(chan error, 1)
+	go func() {
+		ch <- h(ctx)
+	}()
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ServerOption is a server option.
+type ServerOption func(*server)
+
+// OnStart is a server option to add a hook invoked after listening, before
+// serving begins.
+func OnStart(h Hook) ServerOption {
+	return func(s *server) {
+		s.onStart = append(s.onStart, h)
+	}
+}
+
+// OnShutdown is a server option to add a hook invoked after the server has
+// shut down.
+func OnShutdown(h Hook) ServerOption {
+	return func(s *server) {
+		s.onShutdown = append(s.onShutdown, h)
+	}
+}
+
+// WithHookTimeout is a server option to set the timeout for each lifecycle
+// hook (default 15s).
+func WithHookTimeout(timeout time.Duration) ServerOption {
+	return func(s *server) {
+		s.hookTimeout = timeout
+	}
+}
+
+// WithShutdownTimeout is a server option to set the timeout for gracefully
+// shutting down the server (default 30s).
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(s *server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithDrainDelay is a server option to set the amount of time the server
+// continues serving after being drained (see Mux.Drain) before shutting down,
+// allowing load balancers and orchestrators to observe failing readiness
+// checks and stop sending traffic (default 0).
+func WithDrainDelay(delay time.Duration) ServerOption {
+	return func(s *server) {
+		s.drainDelay = delay
+	}
+}
+
+// WithSignals is a server option to set the signals that trigger a graceful
+// shutdown (default SIGINT and SIGTERM).
+func WithSignals(signals ...os.Signal) ServerOption {
+	return func(s *server) {
+		s.signals = signals
+	}
+}
+
+// WithListener is a server option to serve on the listener instead of
+// listening on the address.
+func WithListener(l net.Listener) ServerOption {
+	return func(s *server) {
+		s.server.listener = l
+	}
+}
+
+// WithAddr is a server option to additionally serve the handler on the
+// address, sharing the lifecycle (signals, hooks, draining, and shutdown) of
+// the main server. WithAddr allows the common two-plane service layout, such
+// as a public API and an internal admin Mux bound to a localhost port:
+//
+//	admin := goji.New()
+//	admin.Handle(goji.Get("/healthz"), checker)
+//	err := goji.Run(ctx, ":443", api, goji.WithAddr("localhost:9090", admin))
+//
+// The read header timeout (see WithReadHeaderTimeout) also applies to the
+// additional server.
+func WithAddr(addr string, handler http.Handler) ServerOption {
+	return func(s *server) {
+		s.servers = append(s.servers, &httpServer{
+			server: &http.Server{
+				Addr:              addr,
+				Handler:           handler,
+				ReadHeaderTimeout: s.server.server.ReadHeaderTimeout,
+			},
+		})
+	}
+}
+
+// WithReadHeaderTimeout is a server option to set the amount of time allowed
+// to read request headers, protecting the server from slow-loris style
+// clients. See middleware.BodyTimeout for enforcing a deadline on reading
+// request bodies.
+func WithReadHeaderTimeout(timeout time.Duration) ServerOption {
+	return func(s *server) {
+		for _, srv := range s.servers {
+			srv.server.ReadHeaderTimeout = timeout
+		}
+	}
+}
+
+// WithServer is a server option to configure the underlying http.Server
+// (timeouts, TLS config, error log, ...).
+func WithServer(f func(*http.Server)) ServerOption {
+	return func(s *server) {
+		f(s.server.server)
+	}
+}
+
+// WithH2C is a server option to enable cleartext HTTP/2 (h2c) in addition to
+// HTTP/1.x, for both "prior knowledge" connections and HTTP/1.1 requests
+// asking to upgrade to h2c (the "Upgrade: h2c" header). h2c is intended for
+// deployments where TLS is terminated elsewhere, such as internal
+// gRPC-gateway or trusted service mesh deployments.
+func WithH2C() ServerOption {
+	return func(s *server) {
+		s.server.server.Handler = &h2cHandler{
+			Handler: h2c.NewHandler(s.server.server.Handler, new(http2.Server)),
+			next:    s.server.server.Handler,
+		}
+	}
+}
+
+// h2cHandler is a http.Handler that serves h2c connections and upgrade
+// requests with the wrapped handler.
+type h2cHandler struct {
+	http.Handler
+	next http.Handler
+}
+
+// Drain drains the wrapped handler, when it supports draining (see
+// Mux.Drain).
+func (h *h2cHandler) Drain() {
+	if d, ok := h.next.(interface{ Drain() }); ok {
+		d.Drain()
+	}
+}
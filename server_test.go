@@ -0,0 +1,294 @@
+package goji
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+func TestRun(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := New()
+	m.HandleFunc(Get("/hello"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("hello"))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	var seq []string
+	hook := func(name string) Hook {
+		return func(context.Context) error {
+			seq = append(seq, name)
+			return nil
+		}
+	}
+	started := make(chan bool)
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, "", m,
+			WithListener(l),
+			OnStart(hook("start one")),
+			OnStart(hook("start two")),
+			OnStart(func(context.Context) error {
+				close(started)
+				return nil
+			}),
+			OnShutdown(hook("shutdown one")),
+			OnShutdown(hook("shutdown two")),
+		)
+	}()
+	<-started
+	res, err := http.Get("http://" + l.Addr().String() + "/hello")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != "hello" {
+		t.Errorf("expected hello, got: %q", body)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !m.Draining() {
+		t.Error("expected mux to be draining")
+	}
+	exp := []string{"start one", "start two", "shutdown one", "shutdown two"}
+	if !reflect.DeepEqual(seq, exp) {
+		t.Errorf("expected %v, got: %v", exp, seq)
+	}
+}
+
+func TestRunSignal(t *testing.T) {
+	started := make(chan bool)
+	done := make(chan error)
+	go func() {
+		done <- Run(context.Background(), "127.0.0.1:0", New(), WithSignals(syscall.SIGUSR1), OnStart(func(context.Context) error {
+			close(started)
+			return nil
+		}))
+	}()
+	<-started
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestRunHookErrors(t *testing.T) {
+	errStart := errors.New("start failed")
+	err := Run(context.Background(), "127.0.0.1:0", New(), OnStart(func(context.Context) error {
+		return errStart
+	}), OnStart(func(context.Context) error {
+		t.Error("expected hook to not be called")
+		return nil
+	}))
+	if !errors.Is(err, errStart) {
+		t.Errorf("expected %v, got: %v", errStart, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errOne := errors.New("one")
+	err = Run(ctx, "127.0.0.1:0", New(), WithHookTimeout(10*time.Millisecond),
+		OnShutdown(func(context.Context) error { return errOne }),
+		OnShutdown(func(context.Context) error {
+			time.Sleep(time.Second)
+			return errors.New("two")
+		}),
+	)
+	errs, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(errs.Unwrap()) != 2 || !errors.Is(err, errOne) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected [one, deadline exceeded], got: %v", err)
+	}
+}
+
+func TestWithH2C(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := New()
+	m.HandleFunc(Get("/user/:name"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(req.Proto + " " + Param(req, "name")))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	started, done := make(chan bool), make(chan error)
+	go func() {
+		done <- Run(ctx, "", m, WithListener(l), WithH2C(), OnStart(func(context.Context) error {
+			close(started)
+			return nil
+		}))
+	}()
+	<-started
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	}()
+
+	// prior knowledge
+	p := new(http.Protocols)
+	p.SetUnencryptedHTTP2(true)
+	expectBody(t, &http.Client{Transport: &http.Transport{Protocols: p}}, "http://"+l.Addr().String()+"/user/carl", "HTTP/2.0 carl")
+
+	// http/1.1
+	expectBody(t, new(http.Client), "http://"+l.Addr().String()+"/user/carl", "HTTP/1.1 carl")
+
+	// upgrade
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET /user/carl HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade, HTTP2-Settings\r\nUpgrade: h2c\r\nHTTP2-Settings: \r\n\r\n")
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected %d, got: %d", http.StatusSwitchingProtocols, res.StatusCode)
+	}
+	io.WriteString(conn, http2.ClientPreface)
+	fr := http2.NewFramer(conn, br)
+	if err := fr.WriteSettings(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for {
+		f, err := fr.ReadFrame()
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		// the upgraded request is answered on stream 1, with the original
+		// request's proto
+		if f, ok := f.(*http2.DataFrame); ok && f.StreamID == 1 {
+			if s := string(f.Data()); s != "HTTP/1.1 carl" {
+				t.Errorf("expected %q, got: %q", "HTTP/1.1 carl", s)
+			}
+			break
+		}
+	}
+}
+
+func TestWithReadHeaderTimeout(t *testing.T) {
+	s := &server{server: &httpServer{server: new(http.Server)}}
+	s.servers = []*httpServer{s.server}
+	WithReadHeaderTimeout(time.Second)(s)
+	if d := s.server.server.ReadHeaderTimeout; d != time.Second {
+		t.Errorf("expected 1s, got: %v", d)
+	}
+}
+
+func TestWithAddr(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	al, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m, admin, cs := New(), New(), NewConnStats()
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("public"))
+	})
+	admin.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("admin"))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan bool)
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, "", m,
+			WithListener(l),
+			WithAddr("", admin),
+			func(s *server) {
+				s.servers[1].listener = al
+			},
+			WithReadHeaderTimeout(time.Second),
+			WithConnStats(cs),
+			OnStart(func(context.Context) error {
+				close(started)
+				return nil
+			}),
+		)
+	}()
+	<-started
+	expectBody(t, new(http.Client), "http://"+l.Addr().String()+"/", "public")
+	expectBody(t, new(http.Client), "http://"+al.Addr().String()+"/", "admin")
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if total := cs.Total(); total != 2 {
+		t.Errorf("expected 2 connections, got: %d", total)
+	}
+	if !m.Draining() || !admin.Draining() {
+		t.Error("expected muxes to be draining")
+	}
+}
+
+func TestRunDrainDelay(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	m := New()
+	m.HandleFunc(Get("/ready"), func(res http.ResponseWriter, req *http.Request) {
+		if Draining(req) {
+			res.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan bool)
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, "", m,
+			WithListener(l),
+			WithDrainDelay(time.Second),
+			OnStart(func(context.Context) error {
+				close(started)
+				return nil
+			}),
+		)
+	}()
+	<-started
+	cl := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	get := func() int {
+		res, err := cl.Get("http://" + l.Addr().String() + "/ready")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	if code := get(); code != http.StatusOK {
+		t.Errorf("expected %d, got: %d", http.StatusOK, code)
+	}
+	cancel()
+	for !m.Draining() {
+		time.Sleep(time.Millisecond)
+	}
+	if code := get(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got: %d", http.StatusServiceUnavailable, code)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package goji
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SLOKey is the route metadata key for the route's service level objective,
+// an Objective, tracked by a SLOTracker.
+//
+// For example:
+//
+//	mux.HandleFunc(goji.Get("/users/:id", goji.WithMeta(goji.SLOKey, goji.Objective{
+//		Availability:  0.999,
+//		Latency:       200 * time.Millisecond,
+//		LatencyTarget: 0.99,
+//	})), user)
+const SLOKey = "slo"
+
+// Objective is a route's service level objective.
+type Objective struct {
+	// Availability is the target fraction (0 to 1) of requests that do not
+	// fail with a server error (5xx), or 0 for no availability objective.
+	Availability float64 `json:"availability,omitempty"`
+	// Latency is the latency threshold of the latency objective.
+	Latency time.Duration `json:"latency,omitempty"`
+	// LatencyTarget is the target fraction (0 to 1) of requests served
+	// within the latency threshold, or 0 for no latency objective.
+	LatencyTarget float64 `json:"latency_target,omitempty"`
+}
+
+// SLOReport is a route's service level objective compliance report for a
+// request method, over the tracker's window (see WithSLOWindow).
+type SLOReport struct {
+	// Method is the request method.
+	Method string `json:"method"`
+	// Route is the route template (see RouteTemplate).
+	Route string `json:"route"`
+	// Objective is the route's objective.
+	Objective Objective `json:"objective"`
+	// Requests is the number of requests.
+	Requests int64 `json:"requests"`
+	// Errors is the number of server error (5xx) responses.
+	Errors int64 `json:"errors"`
+	// Slow is the number of requests exceeding the latency threshold.
+	Slow int64 `json:"slow"`
+	// Availability is the observed fraction of requests without server
+	// errors.
+	Availability float64 `json:"availability"`
+	// LatencyCompliance is the observed fraction of requests served within
+	// the latency threshold.
+	LatencyCompliance float64 `json:"latency_compliance"`
+	// AvailabilityBurnRate is the rate the availability error budget is
+	// being consumed, where 1 consumes exactly the budget over the window.
+	AvailabilityBurnRate float64 `json:"availability_burn_rate"`
+	// LatencyBurnRate is the rate the latency error budget is being
+	// consumed.
+	LatencyBurnRate float64 `json:"latency_burn_rate"`
+	// Compliant is whether or not the route meets its objective.
+	Compliant bool `json:"compliant"`
+}
+
+// sloBuckets is the number of buckets in a SLOTracker's window.
+const sloBuckets = 60
+
+// SLOTracker tracks the compliance of routes with their service level
+// objectives (see SLOKey) over a sliding window.
+type SLOTracker struct {
+	window time.Duration
+	now    func() time.Time
+	mu     sync.Mutex
+	routes map[sloKey]*sloRoute
+}
+
+// sloKey is the key of a tracked route.
+type sloKey struct {
+	method string
+	route  string
+}
+
+// sloRoute is a route's tracked requests.
+type sloRoute struct {
+	objective Objective
+	buckets   [sloBuckets]sloBucket
+}
+
+// sloBucket is a bucket of tracked requests.
+type sloBucket struct {
+	slot     int64
+	requests int64
+	errors   int64
+	slow     int64
+}
+
+// TrackSLOs creates a SLOTracker tracking the responses of the Mux's routes
+// with a service level objective (see SLOKey), by request method and route
+// template. The tracker's report can be served as JSON by the admin Mux (see
+// WithAdminSLOs), and its burn rates published as an expvar (see
+// SLOTracker.Expvar):
+//
+//	slos := goji.TrackSLOs(mux)
+//	expvar.Publish("slo", slos.Expvar())
+//	admin := goji.AdminMux(mux, goji.WithAdminSLOs(slos))
+//
+// It is not safe to create a tracker concurrently with requests.
+func TrackSLOs(m *Mux, opts ...SLOOption) *SLOTracker {
+	t := &SLOTracker{
+		window: time.Hour,
+		now:    time.Now,
+		routes: make(map[sloKey]*sloRoute),
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	m.OnResponse(func(req *http.Request, status int, d time.Duration) {
+		if objective, ok := Meta(req, SLOKey).(Objective); ok {
+			t.track(sloKey{req.Method, RouteTemplate(req)}, objective, status >= 500, objective.LatencyTarget > 0 && d > objective.Latency)
+		}
+	})
+	return t
+}
+
+// slot returns the current bucket slot.
+func (t *SLOTracker) slot() int64 {
+	return t.now().UnixNano() / int64(max(t.window/sloBuckets, 1))
+}
+
+// track tracks a request for the route.
+func (t *SLOTracker) track(key sloKey, objective Objective, failed, slow bool) {
+	slot := t.slot()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.routes[key]
+	if !ok {
+		r = &sloRoute{objective: objective}
+		t.routes[key] = r
+	}
+	b := &r.buckets[slot%sloBuckets]
+	if b.slot != slot {
+		*b = sloBucket{slot: slot}
+	}
+	b.requests++
+	if failed {
+		b.errors++
+	}
+	if slow {
+		b.slow++
+	}
+}
+
+// Report returns the compliance reports of the tracked routes, sorted by
+// route and method.
+func (t *SLOTracker) Report() []SLOReport {
+	slot := t.slot()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	reports := make([]SLOReport, 0, len(t.routes))
+	for key, r := range t.routes {
+		report := SLOReport{
+			Method:            key.method,
+			Route:             key.route,
+			Objective:         r.objective,
+			Availability:      1,
+			LatencyCompliance: 1,
+		}
+		for _, b := range r.buckets {
+			if b.slot > slot-sloBuckets && b.slot <= slot {
+				report.Requests += b.requests
+				report.Errors += b.errors
+				report.Slow += b.slow
+			}
+		}
+		if report.Requests != 0 {
+			report.Availability = 1 - float64(report.Errors)/float64(report.Requests)
+			report.LatencyCompliance = 1 - float64(report.Slow)/float64(report.Requests)
+		}
+		report.AvailabilityBurnRate = burnRate(report.Availability, r.objective.Availability)
+		report.LatencyBurnRate = burnRate(report.LatencyCompliance, r.objective.LatencyTarget)
+		report.Compliant = report.Availability >= r.objective.Availability && report.LatencyCompliance >= r.objective.LatencyTarget
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Route != reports[j].Route {
+			return reports[i].Route < reports[j].Route
+		}
+		return reports[i].Method < reports[j].Method
+	})
+	return reports
+}
+
+// burnRate returns the error budget burn rate for the observed compliance and
+// target, or 0 when there is no target.
+func burnRate(observed, target float64) float64 {
+	switch {
+	case target <= 0:
+		return 0
+	case target >= 1:
+		if observed < 1 {
+			return 1
+		}
+		return 0
+	}
+	return (1 - observed) / (1 - target)
+}
+
+// Expvar returns an expvar.Var of the burn rates of the tracked routes,
+// keyed by method and route (for example, "GET /users/:id").
+func (t *SLOTracker) Expvar() expvar.Var {
+	return expvar.Func(func() interface{} {
+		v := make(map[string]map[string]float64)
+		for _, report := range t.Report() {
+			v[report.Method+" "+report.Route] = map[string]float64{
+				"availability_burn_rate": report.AvailabilityBurnRate,
+				"latency_burn_rate":      report.LatencyBurnRate,
+			}
+		}
+		return v
+	})
+}
+
+// ServeHTTP satisfies the http.Handler interface, serving the JSON encoded
+// report.
+func (t *SLOTracker) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(res)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(t.Report())
+}
+
+// SLOOption is a SLO tracker option.
+type SLOOption func(*SLOTracker)
+
+// WithSLOWindow is a SLO tracker option to set the sliding window over which
+// compliance is computed (default 1 hour).
+func WithSLOWindow(window time.Duration) SLOOption {
+	return func(t *SLOTracker) {
+		t.window = window
+	}
+}
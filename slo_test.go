@@ -0,0 +1,93 @@
+package goji
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSLOTracker(t *testing.T) {
+	m := New()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	slos := TrackSLOs(m, WithSLOWindow(time.Minute))
+	slos.now = func() time.Time { return now }
+	m.HandleFunc(Get("/plain"), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Get("/users/:id", WithMeta(SLOKey, Objective{Availability: 0.9})), func(res http.ResponseWriter, req *http.Request) {
+		if Param(req, "id") == "0" {
+			res.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	m.HandleFunc(Get("/slow", WithMeta(SLOKey, Objective{Latency: time.Millisecond, LatencyTarget: 0.5})), func(http.ResponseWriter, *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+	})
+	for _, path := range []string{"/plain", "/users/0", "/users/1", "/users/2", "/users/3", "/slow"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	reports := slos.Report()
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got: %d", len(reports))
+	}
+	r := reports[0]
+	if r.Route != "/slow" || r.Requests != 1 || r.Slow != 1 || r.LatencyCompliance != 0 || r.LatencyBurnRate != 2 || r.Compliant {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	r = reports[1]
+	if r.Route != "/users/:id" || r.Requests != 4 || r.Errors != 1 || r.Availability != 0.75 || r.Compliant {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if rate := r.AvailabilityBurnRate; rate < 2.49 || rate > 2.51 {
+		t.Errorf("expected burn rate 2.5, got: %f", rate)
+	}
+	// requests outside the window are not counted
+	now = now.Add(time.Minute)
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	admin := AdminMux(m, WithAdminSLOs(slos))
+	res := httptest.NewRecorder()
+	admin.ServeHTTP(res, httptest.NewRequest("GET", "/slo", nil))
+	if err := json.Unmarshal(res.Body.Bytes(), &reports); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got: %d", len(reports))
+	}
+	r = reports[1]
+	if r.Requests != 1 || r.Errors != 0 || r.Availability != 1 || r.AvailabilityBurnRate != 0 || !r.Compliant {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if s := slos.Expvar().String(); s != `{"GET /slow":{"availability_burn_rate":0,"latency_burn_rate":0},"GET /users/:id":{"availability_burn_rate":0,"latency_burn_rate":0}}` {
+		t.Errorf("unexpected expvar: %s", s)
+	}
+}
+
+func TestSLOTrackerMethods(t *testing.T) {
+	m := New()
+	slos := TrackSLOs(m)
+	m.HandleFunc(Get("/users", WithMeta(SLOKey, Objective{Availability: 0.99})), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Post("/users", WithMeta(SLOKey, Objective{Availability: 0.9})), func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusInternalServerError)
+	})
+	for _, method := range []string{"GET", "GET", "POST"} {
+		m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/users", nil))
+	}
+	reports := slos.Report()
+	tests := []struct {
+		method       string
+		availability float64
+		requests     int64
+		errors       int64
+	}{
+		{"GET", 0.99, 2, 0},
+		{"POST", 0.9, 1, 1},
+	}
+	if len(reports) != len(tests) {
+		t.Fatalf("expected %d reports, got: %d", len(tests), len(reports))
+	}
+	for i, test := range tests {
+		r := reports[i]
+		if r.Method != test.method || r.Route != "/users" || r.Objective.Availability != test.availability || r.Requests != test.requests || r.Errors != test.errors {
+			t.Errorf("test %d unexpected report: %+v", i, r)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package goji
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NewStdPathSpec returns a Matcher for a net/http.ServeMux pattern (see
+// net/http.ServeMux), allowing route strings to be reused verbatim when
+// migrating from net/http.ServeMux:
+//
+//	mux.Handle(goji.NewStdPathSpec("GET /users/{id}"), h)
+//	mux.Handle(goji.NewStdPathSpec("/files/{path...}"), files)
+//
+// Patterns have the form "[METHOD ][HOST]/[PATH]", where wildcards are
+// whole path segments of the form "{name}", or "{name...}" for the
+// remainder of the path (see PathSpec's Named Wildcards). As with
+// net/http.ServeMux, patterns ending in a slash match all paths with the
+// pattern as a prefix unless ending in "{$}", the "GET" method also matches
+// "HEAD" requests, and patterns with a host are matched against the request
+// host (see Host).
+//
+// NewStdPathSpec panics when the pattern is invalid.
+func NewStdPathSpec(pattern string, opts ...PathSpecOption) Matcher {
+	spec, method, host, err := parseStdPattern(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("goji: invalid pattern %q: %v", pattern, err))
+	}
+	switch method {
+	case "":
+	case "GET":
+		opts = append([]PathSpecOption{WithMethod("GET", "HEAD")}, opts...)
+	default:
+		opts = append([]PathSpecOption{WithMethod(method)}, opts...)
+	}
+	p := NewPathSpec(spec, opts...)
+	if host != "" {
+		return Host(host, p)
+	}
+	return p
+}
+
+// parseStdPattern parses a net/http.ServeMux pattern, returning the
+// equivalent path spec, method, and host.
+func parseStdPattern(pattern string) (string, string, string, error) {
+	var method, host string
+	if i := strings.IndexAny(pattern, " \t"); i != -1 {
+		method, pattern = pattern[:i], strings.TrimLeft(pattern[i+1:], " \t")
+	}
+	i := strings.IndexByte(pattern, '/')
+	if i == -1 {
+		return "", "", "", fmt.Errorf("missing path")
+	}
+	host, pattern = pattern[:i], pattern[i:]
+	segments := strings.Split(pattern[1:], "/")
+	var sb strings.Builder
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		sb.WriteByte('/')
+		switch {
+		case !strings.ContainsAny(seg, "{}"):
+			if last && seg == "" {
+				// trailing slash matches the prefix
+				sb.WriteByte('*')
+			} else {
+				sb.WriteString(seg)
+			}
+		case seg == "{$}":
+			if !last {
+				return "", "", "", fmt.Errorf("{$} not at end")
+			}
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "...}"):
+			name := seg[1 : len(seg)-4]
+			if !last {
+				return "", "", "", fmt.Errorf("%s not at end", seg)
+			}
+			if !isIdent(name) {
+				return "", "", "", fmt.Errorf("bad wildcard name %q", name)
+			}
+			sb.WriteString("*" + name)
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			name := seg[1 : len(seg)-1]
+			if !isIdent(name) {
+				return "", "", "", fmt.Errorf("bad wildcard name %q", name)
+			}
+			sb.WriteString(":" + name)
+		default:
+			return "", "", "", fmt.Errorf("bad wildcard segment %q", seg)
+		}
+	}
+	return sb.String(), method, host, nil
+}
+
+// isIdent returns whether or not s is a valid Go identifier.
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c != '_' && !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') && (i == 0 || !('0' <= c && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// HandleStd dispatches to the handler for requests matching the
+// net/http.ServeMux pattern (see NewStdPathSpec), returning the route's token
+// (see Handle).
+func (m *Mux) HandleStd(pattern string, handler http.Handler) RouteToken {
+	return m.Handle(NewStdPathSpec(pattern), handler)
+}
+
+// serveMuxMatcher is a Matcher for the routes of a net/http.ServeMux.
+type serveMuxMatcher struct {
+	sm *http.ServeMux
+}
+
+// FromServeMux returns a Matcher that matches requests for which the
+// net/http.ServeMux has a registered pattern, allowing an existing ServeMux
+// to be mounted within a Mux's route table during incremental migrations:
+//
+//	mux.Handle(goji.FromServeMux(sm), sm)
+//
+// The ServeMux is matched against the request's remaining path (see Path),
+// and matched requests have their URL path set to the remaining path, so a
+// ServeMux can also be mounted under a prefix within a sub-Mux. Conversely,
+// a Mux can be mounted within a ServeMux as a http.Handler, using
+// http.StripPrefix to mount it under a prefix.
+func FromServeMux(sm *http.ServeMux) Matcher {
+	return serveMuxMatcher{sm}
+}
+
+// Match satisfies the Matcher interface.
+func (m serveMuxMatcher) Match(req *http.Request) *http.Request {
+	path := Path(req.Context())
+	if path != "" && path != req.URL.EscapedPath() {
+		unescaped, err := url.PathUnescape(path)
+		if err != nil {
+			return nil
+		}
+		u := *req.URL
+		u.Path, u.RawPath = unescaped, path
+		req = req.Clone(req.Context())
+		req.URL = &u
+	}
+	if _, pattern := m.sm.Handler(req); pattern == "" {
+		return nil
+	}
+	return req
+}
+
+// Methods satisfies the Matcher interface.
+func (serveMuxMatcher) Methods() map[string]struct{} {
+	return nil
+}
+
+// Prefix satisfies the Matcher interface.
+func (serveMuxMatcher) Prefix() string {
+	return ""
+}
+
+// String satisfies the fmt.Stringer interface.
+func (serveMuxMatcher) String() string {
+	return "http.ServeMux"
+}
@@ -0,0 +1,112 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNewStdPathSpec(t *testing.T) {
+	tests := []struct {
+		pattern string
+		method  string
+		target  string
+		params  map[string]string
+	}{
+		{"GET /users/{id}", "GET", "/users/7", map[string]string{"id": "7"}},
+		{"GET /users/{id}", "HEAD", "/users/7", map[string]string{"id": "7"}},
+		{"GET /users/{id}", "POST", "/users/7", nil},
+		{"GET /users/{id}", "GET", "/users/7/x", nil},
+		{"POST /users", "POST", "/users", map[string]string{}},
+		{"/files/{path...}", "PUT", "/files/a/b.txt", map[string]string{"path": "a/b.txt"}},
+		{"/static/", "GET", "/static/css/app.css", map[string]string{}},
+		{"/static/{$}", "GET", "/static/", map[string]string{}},
+		{"/static/{$}", "GET", "/static/app.css", nil},
+		{"/{$}", "GET", "/", map[string]string{}},
+		{"/{$}", "GET", "/x", nil},
+		{"/", "GET", "/anything", map[string]string{}},
+		{"GET example.com/{name}", "GET", "http://example.com/carl", map[string]string{"name": "carl"}},
+		{"GET example.com/{name}", "GET", "http://other.com/carl", nil},
+	}
+	for i, test := range tests {
+		m := New()
+		var params map[string]string
+		m.HandleFunc(NewStdPathSpec(test.pattern), func(res http.ResponseWriter, req *http.Request) {
+			if params = Params(req); params == nil {
+				params = map[string]string{}
+			}
+		})
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.target, nil))
+		if !reflect.DeepEqual(params, test.params) {
+			t.Errorf("test %d expected %v, got: %v", i, test.params, params)
+		}
+	}
+}
+
+func TestNewStdPathSpecInvalid(t *testing.T) {
+	for i, pattern := range []string{
+		"GET",
+		"/users/{id",
+		"/users/x{id}",
+		"/{path...}/x",
+		"/{$}/x",
+		"/{1id}",
+		"/{}",
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("test %d expected panic for %q", i, pattern)
+				}
+			}()
+			NewStdPathSpec(pattern)
+		}()
+	}
+}
+
+func TestHandleStd(t *testing.T) {
+	m := New()
+	m.HandleStd("GET /users/{id}", http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(Param(req, "id")))
+	}))
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest("GET", "/users/7", nil))
+	if s := res.Body.String(); s != "7" {
+		t.Errorf("expected %q, got: %q", "7", s)
+	}
+}
+
+func TestFromServeMux(t *testing.T) {
+	sm := http.NewServeMux()
+	sm.HandleFunc("GET /legacy/{id}", func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("legacy " + req.PathValue("id") + " " + req.URL.Path))
+	})
+	sub := NewSubMux()
+	sub.Handle(FromServeMux(sm), sm)
+	m := New()
+	m.HandleFunc(Get("/users"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("goji"))
+	})
+	m.Handle(FromServeMux(sm), sm)
+	m.Handle(NewPathSpec("/v1/*"), sub)
+	tests := []struct {
+		method string
+		path   string
+		exp    string
+	}{
+		{"GET", "/users", "goji"},
+		{"GET", "/legacy/7", "legacy 7 /legacy/7"},
+		{"GET", "/v1/legacy/8", "legacy 8 /legacy/8"},
+		{"POST", "/legacy/7", "404 page not found\n"},
+		{"GET", "/other", "404 page not found\n"},
+	}
+	for i, test := range tests {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest(test.method, test.path, nil))
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package goji
+
+import (
+	"context"
+	"sync"
+)
+
+// store is a request-scoped value store.
+type store struct {
+	mu sync.RWMutex
+	m  map[interface{}]interface{}
+}
+
+// Set sets the value for the key in the request-scoped value store allocated
+// by the Mux for each request, providing middleware with a way to pass values
+// to handlers without allocating a context per value. Set is a no-op when the
+// context was not created by a Mux.
+//
+// As with context keys, keys should be of an unexported type to avoid
+// collisions:
+//
+//	type userKey struct{}
+//	goji.Set(req.Context(), userKey{}, user)
+func Set(ctx context.Context, key, v interface{}) {
+	s, ok := ctx.Value(storeKey).(*store)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[interface{}]interface{})
+	}
+	s.m[key] = v
+}
+
+// Value returns the value of type T for the key in the request-scoped value
+// store (see Set), and whether or not a value of type T was set.
+//
+//	user, ok := goji.Value[*User](req.Context(), userKey{})
+func Value[T any](ctx context.Context, key interface{}) (T, bool) {
+	var v T
+	s, ok := ctx.Value(storeKey).(*store)
+	if !ok {
+		return v, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok = s.m[key].(T)
+	return v, ok
+}
@@ -0,0 +1,50 @@
+package goji
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type storeKeyA struct{}
+
+type storeKeyB struct{}
+
+func TestStore(t *testing.T) {
+	m := New()
+	m.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			Set(req.Context(), storeKeyA{}, "carl")
+			Set(req.Context(), storeKeyB{}, 42)
+			next.ServeHTTP(res, req)
+		})
+	})
+	var called bool
+	m.HandleFunc(Get("/"), func(res http.ResponseWriter, req *http.Request) {
+		called = true
+		if v, ok := Value[string](req.Context(), storeKeyA{}); !ok || v != "carl" {
+			t.Errorf("expected %q, got: %q %t", "carl", v, ok)
+		}
+		if v, ok := Value[int](req.Context(), storeKeyB{}); !ok || v != 42 {
+			t.Errorf("expected %d, got: %d %t", 42, v, ok)
+		}
+		if v, ok := Value[string](req.Context(), storeKeyB{}); ok || v != "" {
+			t.Errorf("expected no value for wrong type, got: %q %t", v, ok)
+		}
+		if _, ok := Value[string](req.Context(), "missing"); ok {
+			t.Errorf("expected no value for missing key")
+		}
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !called {
+		t.Fatalf("expected handler to be called")
+	}
+
+	// no store
+	ctx := context.Background()
+	Set(ctx, storeKeyA{}, "carl")
+	if _, ok := Value[string](ctx, storeKeyA{}); ok {
+		t.Errorf("expected no value without store")
+	}
+}
@@ -0,0 +1,122 @@
+package goji
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Stream streams a response, invoking the func with a StreamWriter that
+// flushes written data to the client in chunks (see WithStreamChunkSize) and
+// at intervals (see WithStreamFlushInterval), unifying the handling needed by
+// SSE, NDJSON, and long export endpoints.
+//
+// Writes block until flushed data has been accepted by the connection,
+// pacing the func to the client (backpressure), and return the request
+// context's error once the client has disconnected. Stream flushes any
+// remaining data after the func returns, and returns the func's error, or
+// the context's error when the client disconnected:
+//
+//	err := goji.Stream(res, req, func(w *goji.StreamWriter) error {
+//		for row := range rows {
+//			if _, err := w.Write(row.CSV()); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	})
+//
+// Headers (such as Content-Type) must be set prior to calling Stream.
+func Stream(res http.ResponseWriter, req *http.Request, fn func(*StreamWriter) error, opts ...StreamOption) error {
+	w := &StreamWriter{
+		res:       res,
+		rc:        http.NewResponseController(res),
+		ctx:       req.Context(),
+		chunkSize: 32 << 10,
+		last:      time.Now(),
+	}
+	for _, o := range opts {
+		o(w)
+	}
+	err := fn(w)
+	if ctxErr := w.ctx.Err(); ctxErr != nil && (err == nil || errors.Is(err, ctxErr)) {
+		return ctxErr
+	}
+	if ferr := w.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// StreamWriter is a streaming response writer (see Stream).
+type StreamWriter struct {
+	res       http.ResponseWriter
+	rc        *http.ResponseController
+	ctx       context.Context
+	chunkSize int
+	interval  time.Duration
+	pending   int
+	last      time.Time
+}
+
+// Context returns the request context, which is canceled when the client
+// disconnects.
+func (w *StreamWriter) Context() context.Context {
+	return w.ctx
+}
+
+// Write satisfies the io.Writer interface, flushing the response when the
+// pending data reaches the chunk size or the flush interval has elapsed.
+func (w *StreamWriter) Write(buf []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := w.res.Write(buf)
+	if err != nil {
+		return n, err
+	}
+	w.pending += n
+	if w.pending >= w.chunkSize || w.interval > 0 && time.Since(w.last) >= w.interval {
+		return n, w.Flush()
+	}
+	return n, nil
+}
+
+// WriteString satisfies the io.StringWriter interface.
+func (w *StreamWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush flushes the pending data to the client. Flush is a no-op when the
+// underlying http.ResponseWriter does not support flushing.
+func (w *StreamWriter) Flush() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	w.pending, w.last = 0, time.Now()
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// StreamOption is a stream option.
+type StreamOption func(*StreamWriter)
+
+// WithStreamChunkSize is a stream option to set the amount of pending data
+// that triggers a flush (default 32 KiB). A size of 1 flushes every write.
+func WithStreamChunkSize(n int) StreamOption {
+	return func(w *StreamWriter) {
+		w.chunkSize = n
+	}
+}
+
+// WithStreamFlushInterval is a stream option to flush pending data on writes
+// after the interval has elapsed since the last flush, bounding the latency
+// of small writes.
+func WithStreamFlushInterval(interval time.Duration) StreamOption {
+	return func(w *StreamWriter) {
+		w.interval = interval
+	}
+}
@@ -0,0 +1,76 @@
+package goji
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Body.String())
+}
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		chunkSize int
+		writes    []string
+		exp       []string
+	}{
+		{4, []string{"ab", "cd", "ef"}, []string{"abcd", "abcdef"}},
+		{1, []string{"ab", "cd"}, []string{"ab", "abcd", "abcd"}},
+		{32, []string{"ab", "cd"}, []string{"abcd"}},
+	}
+	for i, test := range tests {
+		res := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		err := Stream(res, httptest.NewRequest("GET", "/", nil), func(w *StreamWriter) error {
+			for _, s := range test.writes {
+				if _, err := w.WriteString(s); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, WithStreamChunkSize(test.chunkSize))
+		if err != nil {
+			t.Errorf("test %d expected no error, got: %v", i, err)
+		}
+		if s := res.Body.String(); s != strings.Join(test.writes, "") {
+			t.Errorf("test %d expected %q, got: %q", i, strings.Join(test.writes, ""), s)
+		}
+		if a, b := strings.Join(res.flushes, ","), strings.Join(test.exp, ","); a != b {
+			t.Errorf("test %d expected flushes %q, got: %q", i, b, a)
+		}
+	}
+}
+
+func TestStreamDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	res := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	var writes int
+	err := Stream(res, req, func(w *StreamWriter) error {
+		for {
+			if _, err := w.Write([]byte("row\n")); err != nil {
+				return err
+			}
+			if writes++; writes == 3 {
+				cancel()
+			}
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got: %v", context.Canceled, err)
+	}
+	if writes != 3 {
+		t.Errorf("expected 3 writes, got: %d", writes)
+	}
+	if s := res.Body.String(); s != "row\nrow\nrow\n" {
+		t.Errorf("expected %q, got: %q", "row\nrow\nrow\n", s)
+	}
+}
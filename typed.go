@@ -0,0 +1,79 @@
+package goji
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+)
+
+// paramTypes are the parsers for typed named matches (see PathSpec's Typed
+// Matches).
+var paramTypes = map[string]func(string) (interface{}, bool){
+	"int": func(s string) (interface{}, bool) {
+		v, err := strconv.ParseInt(s, 10, 64)
+		return v, err == nil
+	},
+	"uint": func(s string) (interface{}, bool) {
+		v, err := strconv.ParseUint(s, 10, 64)
+		return v, err == nil
+	},
+	"uuid": func(s string) (interface{}, bool) {
+		return ParseUUID(s)
+	},
+}
+
+// UUID is a UUID.
+type UUID [16]byte
+
+// ParseUUID parses a UUID in its canonical form (for example,
+// "f47ac10b-58cc-4372-a567-0e02b2c3d479"), case insensitively.
+func ParseUUID(s string) (UUID, bool) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, false
+	}
+	j := 0
+	for _, i := range [...]int{0, 4, 9, 14, 19, 24, 28, 32} {
+		if _, err := hex.Decode(u[j:j+2], []byte(s[i:i+4])); err != nil {
+			return u, false
+		}
+		j += 2
+	}
+	return u, true
+}
+
+// String satisfies the fmt.Stringer interface.
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// ParamInt returns the parsed value of the "int" typed named match (for
+// example, ":id:int") bound in the request context.
+func ParamInt(req *http.Request, name string) (int64, bool) {
+	v, ok := req.Context().Value(typedKey(name)).(int64)
+	return v, ok
+}
+
+// ParamUint returns the parsed value of the "uint" typed named match bound
+// in the request context.
+func ParamUint(req *http.Request, name string) (uint64, bool) {
+	v, ok := req.Context().Value(typedKey(name)).(uint64)
+	return v, ok
+}
+
+// ParamUUID returns the parsed value of the "uuid" typed named match bound
+// in the request context.
+func ParamUUID(req *http.Request, name string) (UUID, bool) {
+	v, ok := req.Context().Value(typedKey(name)).(UUID)
+	return v, ok
+}
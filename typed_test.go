@@ -0,0 +1,60 @@
+package goji
+
+import "testing"
+
+func TestTypedMatches(t *testing.T) {
+	p := NewPathSpec("/orders/:id:int")
+	if r := p.Match(reqPath("GET", "/orders/abc")); r != nil {
+		t.Errorf("expected no match")
+	}
+	r := p.Match(reqPath("GET", "/orders/-42"))
+	if r == nil {
+		t.Fatalf("expected match")
+	}
+	if v, ok := ParamInt(r, "id"); !ok || v != -42 {
+		t.Errorf("expected %d, got: %d (%t)", -42, v, ok)
+	}
+	if s := Param(r, "id"); s != "-42" {
+		t.Errorf("expected %q, got: %q", "-42", s)
+	}
+	if _, ok := ParamUint(r, "id"); ok {
+		t.Errorf("expected no uint")
+	}
+	if s, err := p.URL("id", "7"); err != nil || s != "/orders/7" {
+		t.Errorf("expected %q, got: %q (%v)", "/orders/7", s, err)
+	}
+
+	p = NewPathSpec("/items/:sku:uuid/:n:uint")
+	if r := p.Match(reqPath("GET", "/items/f47ac10b-58cc-4372-a567-0e02b2c3d47/1")); r != nil {
+		t.Errorf("expected no match")
+	}
+	if r := p.Match(reqPath("GET", "/items/f47ac10b-58cc-4372-a567-0e02b2c3d479/-1")); r != nil {
+		t.Errorf("expected no match")
+	}
+	r = p.Match(reqPath("GET", "/items/F47AC10B-58CC-4372-A567-0E02B2C3D479/1"))
+	if r == nil {
+		t.Fatalf("expected match")
+	}
+	if v, ok := ParamUUID(r, "sku"); !ok || v.String() != "f47ac10b-58cc-4372-a567-0e02b2c3d479" {
+		t.Errorf("expected uuid, got: %s (%t)", v, ok)
+	}
+	if v, ok := ParamUint(r, "n"); !ok || v != 1 {
+		t.Errorf("expected %d, got: %d (%t)", 1, v, ok)
+	}
+	if _, ok := ParamInt(r, "sku"); ok {
+		t.Errorf("expected no int")
+	}
+
+	// unknown types are literal names
+	p = NewPathSpec("/:a:b")
+	r = p.Match(reqPath("GET", "/x"))
+	if r == nil {
+		t.Fatalf("expected match")
+	}
+	if s := Param(r, "a:b"); s != "x" {
+		t.Errorf("expected %q, got: %q", "x", s)
+	}
+	if _, ok := ParamInt(r, "a"); ok {
+		t.Errorf("expected no int")
+	}
+}
@@ -0,0 +1,207 @@
+package goji
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrUnknownRoute is the unknown route error.
+var ErrUnknownRoute = errors.New("unknown route")
+
+// ErrNotReversible is the not reversible error, returned when a route's
+// Matcher cannot build URLs.
+var ErrNotReversible = errors.New("route is not reversible")
+
+// ErrMissingParam is the missing param error.
+var ErrMissingParam = errors.New("missing param")
+
+// ErrInvalidParams is the invalid params error, returned when params are not
+// name and value pairs.
+var ErrInvalidParams = errors.New("invalid params")
+
+// name registers the matcher's route name (see matcherName), if any. The
+// caller must hold m.mu, so that route names are updated together with the
+// route table.
+func (m *Mux) name(matcher Matcher) {
+	name := matcherName(matcher)
+	if name == "" {
+		return
+	}
+	if m.names == nil {
+		m.names = make(map[string]Matcher)
+	}
+	m.names[name] = matcher
+}
+
+// HandleNamed adds a new named route to the Mux and returns the route's token
+// (see Mux.Handle). HandleNamed is sugar for the WithName path spec option,
+// and additionally names routes whose Matcher is not a PathSpec (such as
+// And), preserving the Matcher's route policies (see WithAuth). The route's
+// URL can be built with URL:
+//
+//	mux.HandleNamed("user_show", goji.Get("/user/:name"), h)
+//	urlstr, err := mux.URL("user_show", "name", "carl") // "/user/carl"
+//
+// is equivalent to:
+//
+//	mux.Handle(goji.Get("/user/:name", goji.WithName("user_show")), h)
+func (m *Mux) HandleNamed(name string, matcher Matcher, handler http.Handler) RouteToken {
+	return m.Handle(named(name, matcher), handler)
+}
+
+// named returns the Matcher with the route name.
+func named(name string, matcher Matcher) Matcher {
+	if p, ok := matcher.(*PathSpec); ok {
+		spec := *p
+		spec.name = name
+		return &spec
+	}
+	return namedMatcher{Matcher: matcher, name: name}
+}
+
+// namedMatcher is a Matcher with a route name.
+type namedMatcher struct {
+	Matcher
+	name string
+}
+
+// Name returns the route name.
+func (m namedMatcher) Name() string {
+	return m.name
+}
+
+// Unwrap returns the wrapped Matcher.
+func (m namedMatcher) Unwrap() Matcher {
+	return m.Matcher
+}
+
+// String satisfies the fmt.Stringer interface.
+func (m namedMatcher) String() string {
+	return matcherString(m.Matcher)
+}
+
+// URL builds the URL path using the wrapped Matcher.
+func (m namedMatcher) URL(params ...string) (string, error) {
+	if u, ok := m.Matcher.(interface {
+		URL(...string) (string, error)
+	}); ok {
+		return u.URL(params...)
+	}
+	return "", ErrNotReversible
+}
+
+// unname unregisters the matcher's route name, if any, re-registering the
+// name for any remaining route with the same name. The caller must hold m.mu.
+func (m *Mux) unname(matcher Matcher) {
+	name := matcherName(matcher)
+	if name == "" {
+		return
+	}
+	delete(m.names, name)
+	for _, rt := range m.routes() {
+		if matcherName(registered(rt.matcher)) == name {
+			m.name(registered(rt.matcher))
+		}
+	}
+}
+
+// URL builds the URL path for the named route (see WithName) from the name
+// and value pairs of the route's variables, including the Mux's base path.
+// Named routes of sub-Muxes (including sub-Muxes mounted with Mount) are
+// built with the sub-Mux's path prefix.
+//
+// For example:
+//
+//	mux.HandleFunc(goji.Get("/user/:name", goji.WithName("user_show")), h)
+//	urlstr, err := mux.URL("user_show", "name", "carl") // "/user/carl"
+func (m *Mux) URL(name string, params ...string) (string, error) {
+	m.mu.RLock()
+	matcher, ok := m.names[name]
+	m.mu.RUnlock()
+	if !ok {
+		return m.subURL(name, params...)
+	}
+	u, ok := matcher.(interface {
+		URL(...string) (string, error)
+	})
+	if !ok {
+		return "", ErrNotReversible
+	}
+	path, err := u.URL(params...)
+	if err != nil {
+		return "", err
+	}
+	return m.basePath + path, nil
+}
+
+// subURL builds the URL path for the named route of the Mux's sub-Muxes.
+func (m *Mux) subURL(name string, params ...string) (string, error) {
+	for _, rt := range m.routes() {
+		sub, ok := routeHandler(rt.handler).(*Mux)
+		if !ok {
+			continue
+		}
+		path, err := sub.URL(name, params...)
+		switch {
+		case errors.Is(err, ErrUnknownRoute):
+			continue
+		case err != nil:
+			return "", err
+		}
+		prefix := unwrapMatcher(rt.matcher).Prefix()
+		if u, ok := registered(rt.matcher).(interface {
+			URL(...string) (string, error)
+		}); ok {
+			if prefix, err = u.URL(mountParams(params)...); err != nil {
+				return "", err
+			}
+		}
+		return m.basePath + strings.TrimSuffix(prefix, "/") + path, nil
+	}
+	return "", ErrUnknownRoute
+}
+
+// mountParams returns the params without the wildcard param, for building
+// the path prefix of a sub-Mux's route.
+func mountParams(params []string) []string {
+	var v []string
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i] != "*" {
+			v = append(v, params[i], params[i+1])
+		}
+	}
+	return v
+}
+
+// AbsoluteURL builds the externally valid absolute URL for the named route
+// (see Mux.URL) of the Mux serving the request, for use in emails, Location
+// headers, and hypermedia links.
+//
+// The scheme and host are determined from the request, and the request's
+// forwarded prefix (see ForwardedPrefix) is included. Use the
+// middleware.Forwarded middleware to apply the scheme and host sent by a
+// reverse proxy.
+func AbsoluteURL(req *http.Request, name string, params ...string) (string, error) {
+	m, ok := req.Context().Value(muxKey).(*Mux)
+	if !ok {
+		return "", ErrUnknownRoute
+	}
+	path, err := m.URL(name, params...)
+	if err != nil {
+		return "", err
+	}
+	scheme := req.URL.Scheme
+	switch {
+	case scheme != "":
+	case req.TLS != nil:
+		scheme = "https"
+	default:
+		scheme = "http"
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	return scheme + "://" + host + ForwardedPrefix(req.Context()) + path, nil
+}
@@ -0,0 +1,216 @@
+package goji
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPathSpecURL(t *testing.T) {
+	tests := []struct {
+		spec   string
+		params []string
+		exp    string
+		err    error
+	}{
+		{"/", nil, "/", nil},
+		{"/user/:name", []string{"name", "carl"}, "/user/carl", nil},
+		{"/user/:name", []string{"name", "a b/c"}, "/user/a%20b%2Fc", nil},
+		{"/:file.:ext", []string{"ext", "json", "file", "data"}, "/data.json", nil},
+		{"/user/*", nil, "/user/", nil},
+		{"/user/:name/*", []string{"name", "carl", "*", "/photos/1"}, "/user/carl/photos/1", nil},
+		{"/files/*", []string{"*", "a b/c?d/#e"}, "/files/a%20b/c%3Fd/%23e", nil},
+		{"/user/:name", nil, "", ErrMissingParam},
+		{"/user/:name", []string{"name", ""}, "", ErrMissingParam},
+		{"/user/:name", []string{"name"}, "", ErrInvalidParams},
+	}
+	for i, test := range tests {
+		s, err := NewPathSpec(test.spec).URL(test.params...)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestMuxURL(t *testing.T) {
+	m := New(WithBasePath("/app"))
+	m.HandleFunc(Get("/user/:name", WithName("user_show")), func(http.ResponseWriter, *http.Request) {})
+	m.HandleFunc(Host(":tenant.example.com", Get("/", WithName("tenant_index"))), func(http.ResponseWriter, *http.Request) {})
+	tests := []struct {
+		name   string
+		params []string
+		exp    string
+		err    error
+	}{
+		{"user_show", []string{"name", "carl"}, "/app/user/carl", nil},
+		{"tenant_index", nil, "/app/", nil},
+		{"user_show", nil, "", ErrMissingParam},
+		{"unknown", nil, "", ErrUnknownRoute},
+	}
+	for i, test := range tests {
+		s, err := m.URL(test.name, test.params...)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestMuxURLSubMux(t *testing.T) {
+	h := func(http.ResponseWriter, *http.Request) {}
+	api := NewSubMux()
+	api.HandleFunc(Get("/user/:name", WithName("api_user")), h)
+	admin := New(WithBasePath("/v2"))
+	admin.HandleFunc(Get("/stats", WithName("admin_stats")), h)
+	m := New(WithBasePath("/app"))
+	m.Handle(NewPathSpec("/:tenant/api/*"), api)
+	m.Mount("/admin", admin)
+	tests := []struct {
+		name   string
+		params []string
+		exp    string
+		err    error
+	}{
+		{"api_user", []string{"tenant", "acme", "name", "carl"}, "/app/acme/api/user/carl", nil},
+		{"api_user", []string{"name", "carl"}, "", ErrMissingParam},
+		{"admin_stats", nil, "/app/admin/v2/stats", nil},
+		{"unknown", nil, "", ErrUnknownRoute},
+	}
+	for i, test := range tests {
+		s, err := m.URL(test.name, test.params...)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	m := New(WithBasePath("/app"), TrustForwardedPrefix)
+	m.HandleFunc(Get("/user/:name", WithName("user_show")), func(res http.ResponseWriter, req *http.Request) {
+		s, err := AbsoluteURL(req, "user_show", "name", Param(req, "name"))
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		res.Write([]byte(s))
+	})
+	tests := []struct {
+		host   string
+		tls    bool
+		prefix string
+		exp    string
+	}{
+		{"example.com", false, "", "http://example.com/app/user/carl"},
+		{"example.com:8443", true, "", "https://example.com:8443/app/user/carl"},
+		{"example.com", false, "/proxy", "http://example.com/proxy/app/user/carl"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/app/user/carl", nil)
+		req.Host = test.host
+		if test.tls {
+			req.TLS = new(tls.ConnectionState)
+		}
+		if test.prefix != "" {
+			req.Header.Set("X-Forwarded-Prefix", test.prefix)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	if _, err := AbsoluteURL(httptest.NewRequest("GET", "/", nil), "user_show"); !errors.Is(err, ErrUnknownRoute) {
+		t.Errorf("expected %v, got: %v", ErrUnknownRoute, err)
+	}
+}
+
+func TestHandleNamed(t *testing.T) {
+	m := New()
+	h := func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(RouteName(req) + " " + RouteTemplate(req)))
+	}
+	spec := Get("/user/:name")
+	m.HandleNamed("user_show", spec, http.HandlerFunc(h))
+	m.HandleNamed("tenant_index", Host(":tenant.example.com", Get("/")), http.HandlerFunc(h))
+	m.HandleNamed("beta", Header("X-Beta", ""), http.HandlerFunc(h))
+	if spec.name != "" {
+		t.Errorf("expected registered path spec to be unmodified, got: %q", spec.name)
+	}
+	tests := []struct {
+		name   string
+		params []string
+		exp    string
+		err    error
+	}{
+		{"user_show", []string{"name", "a b/c"}, "/user/a%20b%2Fc", nil},
+		{"tenant_index", nil, "/", nil},
+		{"beta", nil, "", ErrNotReversible},
+	}
+	for i, test := range tests {
+		s, err := m.URL(test.name, test.params...)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d expected error %v, got: %v", i, test.err, err)
+		}
+		if s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+	for _, test := range []struct {
+		host, path, header string
+		exp                string
+	}{
+		{"example.com", "/user/carl", "", "user_show /user/:name"},
+		{"acme.example.com", "/", "", "tenant_index :tenant.example.com/"},
+		{"example.com", "/other", "1", "beta Header(X-Beta=)"},
+	} {
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Host = test.host
+		if test.header != "" {
+			req.Header.Set("X-Beta", test.header)
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("expected %q, got: %q", test.exp, s)
+		}
+	}
+}
+
+func TestHandleNamedPolicies(t *testing.T) {
+	m := New()
+	m.HandleNamed("admin", And(Get("/admin", WithAuth(true)), Header("X-Admin", "")), http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(RouteName(req)))
+	}))
+	tests := []struct {
+		principal interface{}
+		status    int
+		body      string
+	}{
+		{nil, 401, "Unauthorized\n"},
+		{"alice", 200, "admin"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("X-Admin", "1")
+		if test.principal != nil {
+			req = req.WithContext(WithPrincipal(req.Context(), test.principal))
+		}
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != test.status {
+			t.Errorf("test %d expected status %d, got: %d", i, test.status, res.Code)
+		}
+		if s := res.Body.String(); s != test.body {
+			t.Errorf("test %d expected body %q, got: %q", i, test.body, s)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package goji
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProblemType is a route table problem type.
+type ProblemType int
+
+// Problem types.
+const (
+	// ProblemConflict is the problem type for a route sharing its path and
+	// some of its methods with an earlier route.
+	ProblemConflict ProblemType = iota
+	// ProblemUnreachable is the problem type for a route that is never
+	// matched, as all of its requests are matched by an earlier route.
+	ProblemUnreachable
+	// ProblemDuplicateName is the problem type for a route with the same
+	// name as an earlier route.
+	ProblemDuplicateName
+	// ProblemUnusedParam is the problem type for a route param not used by
+	// the route's handler (see ParamsKey).
+	ProblemUnusedParam
+	// ProblemFileServer is the problem type for a http.FileServer mounted on
+	// a wildcard route with a path prefix, which serves files for the full
+	// request path instead of the wildcard's path.
+	ProblemFileServer
+	// ProblemUndocumented is the problem type for a route not documented by
+	// an OpenAPI document (see Mux.CheckOpenAPI).
+	ProblemUndocumented
+	// ProblemUnrouted is the problem type for an operation documented by an
+	// OpenAPI document that is not routed (see Mux.CheckOpenAPI).
+	ProblemUnrouted
+)
+
+// String satisfies the fmt.Stringer interface.
+func (typ ProblemType) String() string {
+	switch typ {
+	case ProblemConflict:
+		return "Conflict"
+	case ProblemUnreachable:
+		return "Unreachable"
+	case ProblemDuplicateName:
+		return "DuplicateName"
+	case ProblemUnusedParam:
+		return "UnusedParam"
+	case ProblemFileServer:
+		return "FileServer"
+	case ProblemUndocumented:
+		return "Undocumented"
+	case ProblemUnrouted:
+		return "Unrouted"
+	}
+	return "ProblemType(" + strconv.Itoa(int(typ)) + ")"
+}
+
+// ParamsKey is the route metadata key for the names of the route params used
+// by the route's handler, as a []string, checked by Mux.Validate.
+//
+// For example:
+//
+//	mux.HandleFunc(goji.Get("/users/:org/:id", goji.WithMeta(goji.ParamsKey, []string{"id"})), user)
+const ParamsKey = "params"
+
+// Problem is a route table problem found by Mux.Validate.
+type Problem struct {
+	Type ProblemType
+	// Route is the route template (see RouteTemplate).
+	Route string
+	// Message describes the problem.
+	Message string
+}
+
+// String satisfies the fmt.Stringer interface.
+func (p Problem) String() string {
+	return p.Type.String() + ": " + p.Route + ": " + p.Message
+}
+
+// Validate checks the Mux's route table, returning the problems found in
+// registration order. The following are checked:
+//
+//   - routes sharing a path and methods with an earlier route
+//   - routes unreachable due to an earlier route, such as a wildcard
+//   - duplicate route names
+//   - route params not used by the route's handler (see ParamsKey)
+//   - a http.FileServer mounted on a prefixed wildcard route without
+//     stripping the prefix
+//
+// Validate is suitable for running in tests or at startup, failing fast:
+//
+//	if problems := mux.Validate(); len(problems) != 0 {
+//		log.Fatalf("invalid routes: %v", problems)
+//	}
+//
+// Only PathSpec routes are checked for conflicts and params, and routes are
+// only reported as shadowed or conflicting by an earlier route whose Matcher
+// is a PathSpec without further conditions (such as Weighted or And).
+func (m *Mux) Validate() []Problem {
+	var problems []Problem
+	add := func(typ ProblemType, route Matcher, format string, v ...interface{}) {
+		problems = append(problems, Problem{
+			Type:    typ,
+			Route:   matcherString(route),
+			Message: fmt.Sprintf(format, v...),
+		})
+	}
+	routes := m.routes()
+	names := make(map[string]Matcher)
+	for i, rt := range routes {
+		matcher := unwrapMatcher(rt.matcher)
+		if name := matcherName(rt.matcher); name != "" {
+			if prev, ok := names[name]; ok {
+				add(ProblemDuplicateName, matcher, "name %q is used by %s", name, matcherString(prev))
+			} else {
+				names[name] = matcher
+			}
+		}
+		p, ok := routePathSpec(rt.matcher)
+		if !ok {
+			continue
+		}
+		for _, prev := range routes[:i] {
+			q, ok := unconditionalPathSpec(prev.matcher)
+			if !ok {
+				continue
+			}
+			if covers(q, p) {
+				add(ProblemUnreachable, p, "shadowed by %s", matcherString(q))
+				break
+			}
+			if p.raw == q.raw && overlaps(q.methods, p.methods) {
+				add(ProblemConflict, p, "methods conflict with %s", matcherString(q))
+				break
+			}
+		}
+		if used, ok := p.meta[ParamsKey].([]string); ok {
+			for _, name := range specParams(p) {
+				if !containsAny(used, []string{name}) {
+					add(ProblemUnusedParam, p, "param %q is not used", name)
+				}
+			}
+		}
+		if p.wildcard && p.literals[0] != "/" && isFileServer(rt.handler) {
+			add(ProblemFileServer, p, "file server serves the full request path, use http.StripPrefix")
+		}
+	}
+	return problems
+}
+
+// routePathSpec returns the PathSpec that all requests matched by the
+// matcher must match, unwrapping wrapped Matchers (see Weighted) and
+// combined Matchers (see And).
+func routePathSpec(m Matcher) (*PathSpec, bool) {
+	for m != nil {
+		switch v := m.(type) {
+		case *PathSpec:
+			return v, true
+		case and:
+			for _, m := range v {
+				if p, ok := routePathSpec(m); ok {
+					return p, true
+				}
+			}
+			return nil, false
+		case interface{ Unwrap() Matcher }:
+			m = v.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// unconditionalPathSpec returns the PathSpec of the matcher when the matcher
+// matches all requests matched by the PathSpec, such as when registered with
+// only a name (see HandleNamed) or with matcher isolation. Matchers adding
+// conditions (such as Weighted, And, and Host) are not unconditional.
+func unconditionalPathSpec(m Matcher) (*PathSpec, bool) {
+	for {
+		switch v := m.(type) {
+		case *PathSpec:
+			return v, true
+		case isolatedMatcher:
+			m = v.Matcher
+		case namedMatcher:
+			m = v.Matcher
+		default:
+			return nil, false
+		}
+	}
+}
+
+// covers returns whether or not all requests matched by p are matched by q.
+func covers(q, p *PathSpec) bool {
+	if q.alts != nil || q.values != nil || q.protos != nil || !methodsCover(q.methods, p.methods) {
+		return false
+	}
+	for _, spec := range q.specs {
+		if spec.typ != "" {
+			return false
+		}
+	}
+	switch {
+	case q.raw == p.raw:
+		return true
+	case q.wildcard && len(q.specs) == 0:
+		return strings.HasPrefix(p.raw, q.literals[0])
+	}
+	return false
+}
+
+// methodsCover returns whether or not the methods of a cover the methods of
+// b, where nil methods are all methods.
+func methodsCover(a, b map[string]struct{}) bool {
+	switch {
+	case a == nil:
+		return true
+	case b == nil:
+		return false
+	}
+	for method := range b {
+		if _, ok := a[method]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns whether or not the methods of a and b overlap, where nil
+// methods are all methods.
+func overlaps(a, b map[string]struct{}) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	for method := range b {
+		if _, ok := a[method]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// specParams returns the sorted param names of the path spec.
+func specParams(p *PathSpec) []string {
+	m := make(map[string]bool)
+	for _, q := range append([]*PathSpec{p}, p.alts...) {
+		for _, spec := range q.specs {
+			m[string(spec.name)] = true
+		}
+		if q.wildcardName != "" {
+			m[string(q.wildcardName)] = true
+		}
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fileServerType is the type of handlers returned by http.FileServer.
+var fileServerType = reflect.TypeOf(http.FileServer(http.Dir("")))
+
+// isFileServer returns whether or not the handler is a http.FileServer.
+func isFileServer(h http.Handler) bool {
+	return reflect.TypeOf(h) == fileServerType
+}
+
+// unwrapMatcher returns the matcher wrapped by the matcher, if any.
+func unwrapMatcher(m Matcher) Matcher {
+	if u, ok := m.(interface{ Unwrap() Matcher }); ok {
+		return u.Unwrap()
+	}
+	return m
+}
+
+// matcherString returns the string form of the matcher.
+func matcherString(m Matcher) string {
+	if s, ok := m.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", m)
+}
@@ -0,0 +1,60 @@
+package goji
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	m := New()
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(Get("/users/:id", WithName("user")), h)
+	m.HandleFunc(Post("/users/:id"), h)
+	m.HandleFunc(NewPathSpec("/users/:id"), h)
+	m.HandleFunc(Get("/users/:id"), h)
+	m.HandleFunc(Get("/api/*"), h)
+	m.HandleFunc(Get("/api/users"), h)
+	m.HandleFunc(Post("/api/users"), h)
+	m.HandleFunc(Get("/orgs/:org/:id", WithName("user"), WithMeta(ParamsKey, []string{"id"})), h)
+	m.Handle(Get("/static/*"), http.FileServer(http.Dir(".")))
+	m.Handle(Get("/assets/*"), http.StripPrefix("/assets", http.FileServer(http.Dir("."))))
+	m.HandleFunc(NewPathSpec("/files/:name"), h)
+	exp := []Problem{
+		{ProblemConflict, "/users/:id", "methods conflict with /users/:id"},
+		{ProblemUnreachable, "/users/:id", "shadowed by /users/:id"},
+		{ProblemUnreachable, "/api/users", "shadowed by /api/*"},
+		{ProblemDuplicateName, "/orgs/:org/:id", `name "user" is used by /users/:id`},
+		{ProblemUnusedParam, "/orgs/:org/:id", `param "org" is not used`},
+		{ProblemFileServer, "/static/*", "file server serves the full request path, use http.StripPrefix"},
+	}
+	if problems := m.Validate(); !reflect.DeepEqual(problems, exp) {
+		t.Errorf("expected %v, got: %v", exp, problems)
+	}
+	if problems := New().Validate(); problems != nil {
+		t.Errorf("expected no problems, got: %v", problems)
+	}
+}
+
+func TestValidateConditional(t *testing.T) {
+	m := New()
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(Weighted(0.1, Get("/a")), h)
+	m.HandleFunc(Get("/a"), h)
+	m.HandleFunc(RPC(Post("/b")), h)
+	m.HandleFunc(Post("/b"), h)
+	m.HandleFunc(And(Get("/c/*"), Header("X-C", "")), h)
+	m.HandleFunc(Get("/c/d"), h)
+	m.HandleFunc(Host("example.com", Get("/e")), h)
+	m.HandleFunc(Get("/e"), h)
+	m.HandleNamed("f", Get("/f/*"), http.HandlerFunc(h))
+	m.HandleFunc(Weighted(0.1, Get("/f/g")), h)
+	m.HandleFunc(And(Header("X-H", ""), Get("/f/h")), h)
+	exp := []Problem{
+		{ProblemUnreachable, "/f/g", "shadowed by /f/*"},
+		{ProblemUnreachable, "/f/h", "shadowed by /f/*"},
+	}
+	if problems := m.Validate(); !reflect.DeepEqual(problems, exp) {
+		t.Errorf("expected %v, got: %v", exp, problems)
+	}
+}
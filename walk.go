@@ -0,0 +1,86 @@
+package goji
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Walk walks the Mux's routes in registration order, invoking the func with
+// each route's Matcher and handler. Routes handled by a sub-Mux (including
+// sub-Muxes mounted with Mount) are walked after the route of the sub-Mux
+// itself, with the sub-Mux's Matchers (which match the path relative to the
+// sub-Mux's prefix). Mounted routes are walked with the mounted handler. Walk
+// stops and returns the error when the func returns an error.
+//
+// It is not safe to walk routes concurrently with registering routes, unless
+// the Mux was created with the Dynamic option.
+func (m *Mux) Walk(fn func(matcher Matcher, handler http.Handler) error) error {
+	for _, rt := range m.routes() {
+		handler := routeHandler(rt.handler)
+		if err := fn(registered(rt.matcher), handler); err != nil {
+			return err
+		}
+		if sub, ok := handler.(*Mux); ok {
+			if err := sub.Walk(fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// routeHandler returns the handler registered for a route, unwrapping
+// mounted handlers (see Mount).
+func routeHandler(handler http.Handler) http.Handler {
+	if h, ok := handler.(mounted); ok {
+		return h.handler
+	}
+	return handler
+}
+
+// Routes returns information about the Mux's routes in registration order,
+// for generating documentation, checking authentication coverage, or
+// printing the route table at startup. The routes of sub-Muxes (including
+// sub-Muxes mounted with Mount) are included in place of the sub-Mux's route,
+// with templates prefixed by the sub-Mux's path prefix. Sub-routes without an
+// enforced authentication policy report the policy of the sub-Mux's route,
+// which the parent Mux enforces.
+//
+// For example:
+//
+//	for _, route := range mux.Routes() {
+//		log.Printf("%v %s", route.Methods, route.Template)
+//	}
+func (m *Mux) Routes() []RouteInfo {
+	return m.routeInfos("", nil)
+}
+
+// routeInfos returns information about the Mux's routes, with templates
+// prefixed by the prefix, and the auth policy for routes without a policy.
+func (m *Mux) routeInfos(prefix string, auth *AuthPolicy) []RouteInfo {
+	var routes []RouteInfo
+	for _, rt := range m.routes() {
+		matcher := unwrapMatcher(rt.matcher)
+		policy := matcherAuth(rt.matcher)
+		if policy == nil || auth != nil && !policy.Required && len(policy.Roles) == 0 {
+			policy = auth
+		}
+		if sub, ok := routeHandler(rt.handler).(*Mux); ok {
+			routes = append(routes, sub.routeInfos(prefix+strings.TrimSuffix(matcher.Prefix(), "/"), policy)...)
+			continue
+		}
+		info := RouteInfo{
+			Name:     matcherName(rt.matcher),
+			Matcher:  matcher,
+			Template: prefix + matcherString(matcher),
+			Auth:     policy,
+		}
+		for method := range matcher.Methods() {
+			info.Methods = append(info.Methods, method)
+		}
+		sort.Strings(info.Methods)
+		routes = append(routes, info)
+	}
+	return routes
+}
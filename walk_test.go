@@ -0,0 +1,99 @@
+package goji
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	h := func(http.ResponseWriter, *http.Request) {}
+	api := NewSubMux()
+	api.HandleFunc(Get("/users/:id", WithName("user")), h)
+	api.HandleFunc(Post("/users"), h)
+	m := New()
+	m.HandleFunc(Get("/"), h)
+	m.Handle(NewPathSpec("/api/*"), api)
+	m.HandleFunc(NewPathSpec("/healthz"), h)
+	var walked []string
+	err := m.Walk(func(matcher Matcher, handler http.Handler) error {
+		walked = append(walked, matcherString(matcher))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := []string{"/", "/api/*", "/users/:id", "/users", "/healthz"}; !reflect.DeepEqual(walked, exp) {
+		t.Errorf("expected %v, got: %v", exp, walked)
+	}
+	stop := errors.New("stop")
+	var n int
+	if err := m.Walk(func(Matcher, http.Handler) error {
+		if n++; n == 3 {
+			return stop
+		}
+		return nil
+	}); !errors.Is(err, stop) || n != 3 {
+		t.Errorf("expected error %v after 3 routes, got: %v after %d", stop, err, n)
+	}
+	var routes []string
+	for _, route := range m.Routes() {
+		routes = append(routes, route.Name+" "+route.Template+" "+strings.Join(route.Methods, ","))
+	}
+	exp := []string{
+		" / GET,HEAD",
+		"user /api/users/:id GET,HEAD",
+		" /api/users POST",
+		" /healthz ",
+	}
+	if !reflect.DeepEqual(routes, exp) {
+		t.Errorf("expected %q, got: %q", exp, routes)
+	}
+}
+
+func TestWalkMount(t *testing.T) {
+	h := func(http.ResponseWriter, *http.Request) {}
+	admin := NewSubMux()
+	admin.HandleFunc(Get("/users"), h)
+	admin.HandleFunc(Get("/public", WithAuth(false)), h)
+	m := New()
+	m.Mount("/admin/", admin, WithAuth(true, "admin"))
+	var walked []string
+	var handler http.Handler
+	m.Walk(func(matcher Matcher, h http.Handler) error {
+		if handler == nil {
+			handler = h
+		}
+		walked = append(walked, matcherString(matcher))
+		return nil
+	})
+	if exp := []string{"/admin/*", "/users", "/public"}; !reflect.DeepEqual(walked, exp) {
+		t.Errorf("expected %v, got: %v", exp, walked)
+	}
+	if handler != admin {
+		t.Errorf("expected mounted handler, got: %T", handler)
+	}
+	tests := []struct {
+		template string
+		required bool
+		roles    []string
+	}{
+		{"/admin/users", true, []string{"admin"}},
+		{"/admin/public", true, []string{"admin"}},
+	}
+	routes := m.Routes()
+	if len(routes) != len(tests) {
+		t.Fatalf("expected %d routes, got: %d", len(tests), len(routes))
+	}
+	for i, test := range tests {
+		route := routes[i]
+		if route.Template != test.template {
+			t.Errorf("test %d expected %q, got: %q", i, test.template, route.Template)
+		}
+		if route.Auth == nil || route.Auth.Required != test.required || !reflect.DeepEqual(route.Auth.Roles, test.roles) {
+			t.Errorf("test %d expected auth required=%t roles=%v, got: %+v", i, test.required, test.roles, route.Auth)
+		}
+	}
+}
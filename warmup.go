@@ -0,0 +1,92 @@
+package goji
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WarmUp is a set of warm-up funcs (such as populating caches) gating
+// expensive routes (see WithWarmUp). Until the warm-up has completed, the
+// Mux responds to requests for gated routes with 503 Service Unavailable and
+// a Retry-After header, while other routes are served immediately.
+//
+// For example:
+//
+//	warm := goji.NewWarmUp(loadCatalog, loadPrices)
+//	mux.HandleFunc(goji.Get("/search", goji.WithWarmUp(warm)), search)
+//	go func() {
+//		if err := warm.Run(ctx); err != nil {
+//			log.Fatal(err)
+//		}
+//	}()
+type WarmUp struct {
+	fns        []func(context.Context) error
+	retryAfter time.Duration
+	mu         sync.Mutex
+	ready      atomic.Bool
+}
+
+// NewWarmUp creates a warm-up for the funcs.
+func NewWarmUp(fns ...func(context.Context) error) *WarmUp {
+	return &WarmUp{
+		fns:        fns,
+		retryAfter: 5 * time.Second,
+	}
+}
+
+// RetryAfter sets the Retry-After duration sent to clients while the warm-up
+// has not completed (default 5 seconds).
+func (w *WarmUp) RetryAfter(d time.Duration) *WarmUp {
+	w.retryAfter = d
+	return w
+}
+
+// Run runs the warm-up funcs concurrently, marking the warm-up as ready when
+// all funcs succeed. When any func fails, the joined errors are returned and
+// the warm-up remains not ready, allowing Run to be retried. Run returns
+// immediately when the warm-up is already ready.
+func (w *WarmUp) Run(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ready.Load() {
+		return nil
+	}
+	errs := make([]error, len(w.fns))
+	var wg sync.WaitGroup
+	for i, f := range w.fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = f(ctx)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	w.ready.Store(true)
+	return nil
+}
+
+// Ready returns whether or not the warm-up has completed.
+func (w *WarmUp) Ready() bool {
+	return w.ready.Load()
+}
+
+// warmUpPolicy enforces the matched route's warm-up (see WithWarmUp),
+// returning false when the request was rejected.
+func warmUpPolicy(res http.ResponseWriter, req *http.Request) bool {
+	for _, m := range policies[interface{ WarmUp() *WarmUp }](routeTree(req)) {
+		if w := m.WarmUp(); w != nil && !w.Ready() {
+			res.Header().Set("Retry-After", strconv.Itoa(int(w.retryAfter.Round(time.Second)/time.Second)))
+			http.Error(res, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package goji
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWarmUp(t *testing.T) {
+	fail := errors.New("cache unavailable")
+	var calls int
+	warm := NewWarmUp(func(context.Context) error {
+		if calls++; calls == 1 {
+			return fail
+		}
+		return nil
+	}).RetryAfter(30 * time.Second)
+	m := New()
+	h := func(http.ResponseWriter, *http.Request) {}
+	m.HandleFunc(Get("/health"), h)
+	m.HandleFunc(Get("/search", WithWarmUp(warm)), h)
+	m.HandleFunc(Host("api.example.com", Get("/catalog", WithWarmUp(warm))), h)
+	check := func(path string, status int, retryAfter string) {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "api.example.com"
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, req)
+		if res.Code != status {
+			t.Errorf("%s expected %d, got: %d", path, status, res.Code)
+		}
+		if s := res.Header().Get("Retry-After"); s != retryAfter {
+			t.Errorf("%s expected Retry-After %q, got: %q", path, retryAfter, s)
+		}
+	}
+	check("/health", http.StatusOK, "")
+	check("/search", http.StatusServiceUnavailable, "30")
+	check("/catalog", http.StatusServiceUnavailable, "30")
+	if err := warm.Run(context.Background()); !errors.Is(err, fail) {
+		t.Errorf("expected error %v, got: %v", fail, err)
+	}
+	if warm.Ready() {
+		t.Errorf("expected warm-up to not be ready")
+	}
+	check("/search", http.StatusServiceUnavailable, "30")
+	if err := warm.Run(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := warm.Run(context.Background()); err != nil || calls != 2 {
+		t.Errorf("expected no error and 2 calls, got: %v, %d", err, calls)
+	}
+	check("/search", http.StatusOK, "")
+	check("/catalog", http.StatusOK, "")
+}
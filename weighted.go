@@ -0,0 +1,72 @@
+package goji
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"net/http"
+)
+
+// WeightedMatcher provides a Matcher that matches a percentage of the
+// requests matched by a wrapped Matcher.
+type WeightedMatcher struct {
+	Matcher
+	weight float64
+	header string
+}
+
+// Weighted returns a WeightedMatcher that matches the weight (0 to 1) of the
+// requests matched by the matcher, allowing the canary rollout of a new
+// handler for the same route. When the weighted matcher does not match, the
+// request falls through to later routes:
+//
+//	mux.HandleFunc(goji.Weighted(0.05, goji.Get("/users/:id")), usersV2)
+//	mux.HandleFunc(goji.Get("/users/:id"), users)
+//
+// Requests are selected at random, unless a header is set (see ByHeader).
+func Weighted(weight float64, matcher Matcher) *WeightedMatcher {
+	return &WeightedMatcher{
+		Matcher: matcher,
+		weight:  weight,
+	}
+}
+
+// ByHeader sets the header used to select requests, returning the matcher.
+// Requests are selected by a hash of the header's value, so that requests
+// with the same value (such as a user ID or session cookie) are consistently
+// selected. Requests without the header are selected at random.
+func (m *WeightedMatcher) ByHeader(name string) *WeightedMatcher {
+	m.header = name
+	return m
+}
+
+// Match satisfies the Matcher interface.
+func (m *WeightedMatcher) Match(req *http.Request) *http.Request {
+	if !m.selected(req) {
+		return nil
+	}
+	return m.Matcher.Match(req)
+}
+
+// Unwrap returns the wrapped Matcher.
+func (m *WeightedMatcher) Unwrap() Matcher {
+	return m.Matcher
+}
+
+// selected returns whether or not the request is selected.
+func (m *WeightedMatcher) selected(req *http.Request) bool {
+	switch {
+	case m.weight <= 0:
+		return false
+	case m.weight >= 1:
+		return true
+	}
+	if m.header != "" {
+		if v := req.Header.Get(m.header); v != "" {
+			sum := sha256.Sum256([]byte(v))
+			return float64(binary.BigEndian.Uint64(sum[:]))/math.MaxUint64 < m.weight
+		}
+	}
+	return rand.Float64() < m.weight
+}
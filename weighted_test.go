@@ -0,0 +1,77 @@
+package goji
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestWeighted(t *testing.T) {
+	tests := []struct {
+		weight float64
+		header string
+		min    int
+		max    int
+	}{
+		{0, "", 0, 0},
+		{1, "", 1000, 1000},
+		{0.3, "", 200, 400},
+		{0.3, "X-User", 200, 400},
+	}
+	for i, test := range tests {
+		m := Weighted(test.weight, Header("X-User", ""))
+		if test.header != "" {
+			m.ByHeader(test.header)
+		}
+		var n int
+		for j := 0; j < 1000; j++ {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-User", strconv.Itoa(j))
+			if m.Match(req) != nil {
+				n++
+			}
+		}
+		if n < test.min || n > test.max {
+			t.Errorf("test %d expected %d-%d matches, got: %d", i, test.min, test.max, n)
+		}
+	}
+}
+
+func TestWeightedStable(t *testing.T) {
+	m := Weighted(0.5, Header("X-User", "")).ByHeader("X-User")
+	for i := 0; i < 100; i++ {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-User", "user"+strconv.Itoa(i))
+		exp := m.Match(req) != nil
+		for j := 0; j < 10; j++ {
+			if ok := m.Match(req) != nil; ok != exp {
+				t.Fatalf("test %d expected %t, got: %t", i, exp, ok)
+			}
+		}
+	}
+}
+
+func TestWeightedMux(t *testing.T) {
+	m := New()
+	m.Handle(Weighted(1, Get("/users/:id", WithName("canary"))), http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(RouteName(req)))
+	}))
+	m.Handle(Weighted(0, Get("/other")), http.NotFoundHandler())
+	m.HandleFunc(Get("/other"), func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("other"))
+	})
+	for i, test := range []struct {
+		path string
+		exp  string
+	}{
+		{"/users/7", "canary"},
+		{"/other", "other"},
+	} {
+		res := httptest.NewRecorder()
+		m.ServeHTTP(res, httptest.NewRequest("GET", test.path, nil))
+		if s := res.Body.String(); s != test.exp {
+			t.Errorf("test %d expected %q, got: %q", i, test.exp, s)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package goji
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// StatusWriter is a http.ResponseWriter that records the response status,
+// for use by middleware and hooks (see Mux.OnResponse) observing responses.
+// Informational (1xx) responses are not recorded, other than 101 Switching
+// Protocols, which is also recorded when the connection is hijacked.
+//
+// For example:
+//
+//	w := &goji.StatusWriter{ResponseWriter: res}
+//	next.ServeHTTP(w, req)
+//	log.Printf("%s %d", req.URL.Path, w.Status())
+type StatusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader satisfies the http.ResponseWriter interface.
+func (w *StatusWriter) WriteHeader(code int) {
+	if w.status == 0 && (code >= 200 || code == http.StatusSwitchingProtocols) {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write satisfies the http.ResponseWriter interface.
+func (w *StatusWriter) Write(buf []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(buf)
+}
+
+// Flush satisfies the http.Flusher interface.
+func (w *StatusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack satisfies the http.Hijacker interface.
+func (w *StatusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the recorded response status, or 200 OK when no response
+// has been written.
+func (w *StatusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Written returns whether or not the response status has been written.
+func (w *StatusWriter) Written() bool {
+	return w.status != 0
+}
+
+// HeaderWriter is a http.ResponseWriter that invokes a func with the
+// response header and status before the response header is written, once,
+// allowing middleware to add or modify response headers based on the
+// response's status.
+//
+// For example:
+//
+//	w := &goji.HeaderWriter{ResponseWriter: res, Func: func(h http.Header, status int) {
+//		if status >= 500 {
+//			h.Set("Cache-Control", "no-store")
+//		}
+//	}}
+//	next.ServeHTTP(w, req)
+type HeaderWriter struct {
+	http.ResponseWriter
+	// Func is the func invoked before the response header is written.
+	Func func(http.Header, int)
+	done bool
+}
+
+// before invokes the func, once.
+func (w *HeaderWriter) before(code int) {
+	if !w.done {
+		w.done = true
+		w.Func(w.ResponseWriter.Header(), code)
+	}
+}
+
+// WriteHeader satisfies the http.ResponseWriter interface.
+func (w *HeaderWriter) WriteHeader(code int) {
+	if code >= 200 {
+		w.before(code)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write satisfies the http.ResponseWriter interface.
+func (w *HeaderWriter) Write(buf []byte) (int, error) {
+	w.before(http.StatusOK)
+	return w.ResponseWriter.Write(buf)
+}
+
+// Flush satisfies the http.Flusher interface.
+func (w *HeaderWriter) Flush() {
+	w.before(http.StatusOK)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack satisfies the http.Hijacker interface. The func is not invoked for
+// hijacked connections.
+func (w *HeaderWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.done = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *HeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// headWriter is a http.ResponseWriter that discards the response body of a
+// HEAD request, deferring the response header until the response is finished
+// (see finish) or flushed, so that the Content-Length header can be set.
+type headWriter struct {
+	http.ResponseWriter
+	status int
+	n      int64
+	wrote  bool
+}
+
+// WriteHeader satisfies the http.ResponseWriter interface.
+func (w *headWriter) WriteHeader(code int) {
+	switch {
+	case code < 200:
+		w.ResponseWriter.WriteHeader(code)
+	case w.status == 0:
+		w.status = code
+	}
+}
+
+// Write satisfies the http.ResponseWriter interface.
+func (w *headWriter) Write(buf []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.n += int64(len(buf))
+	return len(buf), nil
+}
+
+// Flush satisfies the http.Flusher interface.
+func (w *headWriter) Flush() {
+	w.writeHeader(false)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack satisfies the http.Hijacker interface. The response header is not
+// written for hijacked connections.
+func (w *headWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.wrote = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the response header, with the Content-Length header set to
+// the length of the discarded body.
+func (w *headWriter) finish() {
+	w.writeHeader(true)
+}
+
+// writeHeader writes the response header, once.
+func (w *headWriter) writeHeader(length bool) {
+	if w.wrote {
+		return
+	}
+	w.wrote = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if h := w.ResponseWriter.Header(); length && w.n != 0 && h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+		h.Set("Content-Length", strconv.FormatInt(w.n, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}